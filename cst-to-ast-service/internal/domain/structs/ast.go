@@ -2,11 +2,12 @@ package structs
 
 import "github.com/Oleja123/code-vizualization/cst-to-ast-service/internal/domain/interfaces"
 
-// Type представляет тип данных в C (поддержка int, указателей и многомерных массивов)
+// Type представляет тип данных в C (поддержка int, char, float, double, указателей и многомерных массивов)
 type Type struct {
-	BaseType     string `json:"baseType"`     // "int"
-	PointerLevel int    `json:"pointerLevel"` // 0 = int, 1 = int*, 2 = int**
-	ArraySizes   []int  `json:"arraySizes"`   // пустой если не массив, [10, 20] для int[10][20]
+	BaseType     string `json:"baseType"`       // "int", "char", "float", "double"
+	PointerLevel int    `json:"pointerLevel"`   // 0 = int, 1 = int*, 2 = int**
+	ArraySizes   []int  `json:"arraySizes"`     // пустой если не массив, [10, 20] для int[10][20]
+	Enum         string `json:"enum,omitempty"` // имя перечисления для enum-типа (BaseType при этом "int")
 }
 
 // Parameter представляет параметр функции
@@ -55,6 +56,37 @@ func (f *FunctionDecl) StmtNode()                        {}
 func (f *FunctionDecl) NodeType() string                 { return "FunctionDecl" }
 func (f *FunctionDecl) GetLocation() interfaces.Location { return f.Loc }
 
+// Enumerator представляет константу перечисления с вычисленным значением
+type Enumerator struct {
+	Name  string              `json:"name"`
+	Value int                 `json:"value"`
+	Loc   interfaces.Location `json:"location"`
+}
+
+// EnumDecl представляет определение перечисления enum
+type EnumDecl struct {
+	Type        string              `json:"type"` // всегда "EnumDecl"
+	Name        string              `json:"name"` // пустое для анонимного перечисления
+	Enumerators []Enumerator        `json:"enumerators"`
+	Loc         interfaces.Location `json:"location"`
+}
+
+func (e *EnumDecl) StmtNode()                        {}
+func (e *EnumDecl) NodeType() string                 { return "EnumDecl" }
+func (e *EnumDecl) GetLocation() interfaces.Location { return e.Loc }
+
+// TypedefDecl представляет объявление псевдонима типа typedef
+type TypedefDecl struct {
+	Type       string              `json:"type"` // всегда "TypedefDecl"
+	Name       string              `json:"name"`
+	Underlying Type                `json:"underlying"` // тип, в который раскрывается псевдоним
+	Loc        interfaces.Location `json:"location"`
+}
+
+func (t *TypedefDecl) StmtNode()                        {}
+func (t *TypedefDecl) NodeType() string                 { return "TypedefDecl" }
+func (t *TypedefDecl) GetLocation() interfaces.Location { return t.Loc }
+
 // IfStmt представляет условный оператор if/else
 // else if представлен как else с вложенным if (как в C)
 type IfStmt struct {
@@ -195,6 +227,75 @@ func (l *IntLiteral) ExprNode()                        {}
 func (l *IntLiteral) NodeType() string                 { return "IntLiteral" }
 func (l *IntLiteral) GetLocation() interfaces.Location { return l.Loc }
 
+// FloatLiteral представляет вещественный литерал (1.5, 2e-3, 0.5f)
+type FloatLiteral struct {
+	Type    string              `json:"type"` // всегда "FloatLiteral"
+	Value   float64             `json:"value"`
+	IsFloat bool                `json:"isFloat"` // true для литералов с суффиксом f/F (тип float), иначе double
+	Loc     interfaces.Location `json:"location"`
+}
+
+func (l *FloatLiteral) ExprNode()                        {}
+func (l *FloatLiteral) NodeType() string                 { return "FloatLiteral" }
+func (l *FloatLiteral) GetLocation() interfaces.Location { return l.Loc }
+
+// CharLiteral представляет символьный литерал ('a', '\n', '\0')
+type CharLiteral struct {
+	Type  string              `json:"type"`  // всегда "CharLiteral"
+	Value int                 `json:"value"` // код символа
+	Loc   interfaces.Location `json:"location"`
+}
+
+func (l *CharLiteral) ExprNode()                        {}
+func (l *CharLiteral) NodeType() string                 { return "CharLiteral" }
+func (l *CharLiteral) GetLocation() interfaces.Location { return l.Loc }
+
+// StringLiteral представляет строковый литерал "..." с раскрытыми escape-последовательностями
+// (без завершающего нуля)
+type StringLiteral struct {
+	Type  string              `json:"type"` // всегда "StringLiteral"
+	Value string              `json:"value"`
+	Loc   interfaces.Location `json:"location"`
+}
+
+func (l *StringLiteral) ExprNode()                        {}
+func (l *StringLiteral) NodeType() string                 { return "StringLiteral" }
+func (l *StringLiteral) GetLocation() interfaces.Location { return l.Loc }
+
+// NullLiteral представляет константу нулевого указателя NULL
+type NullLiteral struct {
+	Type string              `json:"type"` // всегда "NullLiteral"
+	Loc  interfaces.Location `json:"location"`
+}
+
+func (l *NullLiteral) ExprNode()                        {}
+func (l *NullLiteral) NodeType() string                 { return "NullLiteral" }
+func (l *NullLiteral) GetLocation() interfaces.Location { return l.Loc }
+
+// SizeofExpr представляет оператор sizeof: от имени типа (sizeof(int)) или от выражения (sizeof arr)
+type SizeofExpr struct {
+	Type    string              `json:"type"`              // всегда "SizeofExpr"
+	ArgType *Type               `json:"argType,omitempty"` // задан для sizeof(тип)
+	Operand interfaces.Expr     `json:"operand,omitempty"` // задан для sizeof выражения
+	Loc     interfaces.Location `json:"location"`
+}
+
+func (s *SizeofExpr) ExprNode()                        {}
+func (s *SizeofExpr) NodeType() string                 { return "SizeofExpr" }
+func (s *SizeofExpr) GetLocation() interfaces.Location { return s.Loc }
+
+// CastExpr представляет явное приведение типа (type)expr
+type CastExpr struct {
+	Type       string              `json:"type"` // всегда "CastExpr"
+	TargetType Type                `json:"targetType"`
+	Operand    interfaces.Expr     `json:"operand"`
+	Loc        interfaces.Location `json:"location"`
+}
+
+func (c *CastExpr) ExprNode()                        {}
+func (c *CastExpr) NodeType() string                 { return "CastExpr" }
+func (c *CastExpr) GetLocation() interfaces.Location { return c.Loc }
+
 // BinaryExpr представляет бинарное выражение
 type BinaryExpr struct {
 	Type     string              `json:"type"` // всегда "BinaryExpr"
@@ -0,0 +1,65 @@
+// Package builtins описывает встроенные функции, которые программа может вызывать без определения:
+// подмножество стандартной библиотеки C и вспомогательные функции.
+//
+// Пакет содержит только сигнатуры и заголовки. Валидатор использует их для проверки числа
+// аргументов, интерпретатор сопоставляет каждой сигнатуре свою реализацию.
+// Функция программы с тем же именем перекрывает встроенную.
+package builtins
+
+import "sort"
+
+// Типы параметров, которые не являются типами C
+const (
+	// CharArray - массив char или строковый литерал (аргумент строковых функций)
+	CharArray = "char[]"
+	// Scalar - значение любого скалярного типа, передается без преобразования
+	Scalar = "scalar"
+)
+
+// Signature - сигнатура встроенной функции
+type Signature struct {
+	Name       string   `json:"name"`
+	Header     string   `json:"header"` // заголовок, объявляющий функцию; пуст для вспомогательных функций
+	Params     []string `json:"params"`
+	ReturnType string   `json:"returnType"`
+}
+
+// strcpy и strcat в C возвращают char* на приемник. Массивы в стеке не имеют адресов (указатели адресуют
+// только кучу), поэтому здесь обе функции возвращают void: результат вызова нельзя использовать как значение
+var signatures = map[string]Signature{
+	"strlen": {Name: "strlen", Header: "string.h", Params: []string{CharArray}, ReturnType: "int"},
+	"strcpy": {Name: "strcpy", Header: "string.h", Params: []string{CharArray, CharArray}, ReturnType: "void"},
+	"strcmp": {Name: "strcmp", Header: "string.h", Params: []string{CharArray, CharArray}, ReturnType: "int"},
+	"strcat": {Name: "strcat", Header: "string.h", Params: []string{CharArray, CharArray}, ReturnType: "void"},
+
+	"malloc":  {Name: "malloc", Header: "stdlib.h", Params: []string{"int"}, ReturnType: "void*"},
+	"calloc":  {Name: "calloc", Header: "stdlib.h", Params: []string{"int", "int"}, ReturnType: "void*"},
+	"realloc": {Name: "realloc", Header: "stdlib.h", Params: []string{"void*", "int"}, ReturnType: "void*"},
+	"free":    {Name: "free", Header: "stdlib.h", Params: []string{"void*"}, ReturnType: "void"},
+
+	"abs":   {Name: "abs", Header: "stdlib.h", Params: []string{"int"}, ReturnType: "int"},
+	"rand":  {Name: "rand", Header: "stdlib.h", Params: []string{}, ReturnType: "int"},
+	"srand": {Name: "srand", Header: "stdlib.h", Params: []string{"int"}, ReturnType: "void"},
+	"exit":  {Name: "exit", Header: "stdlib.h", Params: []string{"int"}, ReturnType: "void"},
+
+	"assert": {Name: "assert", Header: "assert.h", Params: []string{Scalar}, ReturnType: "void"},
+
+	"min": {Name: "min", Params: []string{"int", "int"}, ReturnType: "int"},
+	"max": {Name: "max", Params: []string{"int", "int"}, ReturnType: "int"},
+}
+
+// Lookup возвращает сигнатуру встроенной функции по имени
+func Lookup(name string) (Signature, bool) {
+	s, ok := signatures[name]
+	return s, ok
+}
+
+// All возвращает сигнатуры всех встроенных функций, упорядоченные по имени
+func All() []Signature {
+	result := make([]Signature, 0, len(signatures))
+	for _, s := range signatures {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Name < result[b].Name })
+	return result
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/internal/domain/interfaces"
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/internal/domain/structs"
@@ -32,10 +33,19 @@ type (
 	ExprStmt     = structs.ExprStmt
 	BreakStmt    = structs.BreakStmt
 	ContinueStmt = structs.ContinueStmt
+	EnumDecl     = structs.EnumDecl
+	Enumerator   = structs.Enumerator
+	TypedefDecl  = structs.TypedefDecl
 
 	// Выражения
 	VariableExpr    = structs.VariableExpr
 	IntLiteral      = structs.IntLiteral
+	FloatLiteral    = structs.FloatLiteral
+	CharLiteral     = structs.CharLiteral
+	StringLiteral   = structs.StringLiteral
+	NullLiteral     = structs.NullLiteral
+	SizeofExpr      = structs.SizeofExpr
+	CastExpr        = structs.CastExpr
 	BinaryExpr      = structs.BinaryExpr
 	UnaryExpr       = structs.UnaryExpr
 	AssignmentExpr  = structs.AssignmentExpr
@@ -51,7 +61,8 @@ type (
 
 // CConverter реализует конвертер tree-sitter CST в AST для языка C
 type CConverter struct {
-	parser *sitter.Parser
+	parser     *sitter.Parser
+	typeScopes []*typeScope
 }
 
 // NewCConverter создает новый конвертер для C
@@ -119,6 +130,7 @@ func (c *CConverter) Parse(sourceCode []byte) (*sitter.Tree, error) {
 // ConvertToProgram преобразует корневой узел tree-sitter в Program
 func (c *CConverter) ConvertToProgram(tree *sitter.Tree, sourceCode []byte) (interfaces.Node, error) {
 	rootNode := tree.RootNode()
+	c.resetTypeScopes()
 
 	program := &structs.Program{
 		Type:         "Program",
@@ -130,8 +142,13 @@ func (c *CConverter) ConvertToProgram(tree *sitter.Tree, sourceCode []byte) (int
 	for i := 0; i < int(rootNode.ChildCount()); i++ {
 		child := rootNode.Child(i)
 
-		// Пропускаем комментарии и пробелы
-		if child.Type() == "comment" {
+		// Пропускаем комментарии и ';' после определения перечисления
+		if child.Type() == "comment" || child.Type() == ";" {
+			continue
+		}
+
+		// #include не влияет на AST: встроенные функции доступны без подключения заголовков
+		if child.Type() == "preproc_include" {
 			continue
 		}
 
@@ -145,6 +162,16 @@ func (c *CConverter) ConvertToProgram(tree *sitter.Tree, sourceCode []byte) (int
 			continue
 		}
 
+		// Определения перечислений и typedef
+		if child.Type() == "type_definition" || child.Type() == "enum_specifier" {
+			stmts, err := c.convertTypeDeclarations(child, sourceCode)
+			if err != nil {
+				return nil, newConverterError(ErrStmtConversion, "failed to convert type declaration", child, err)
+			}
+			program.Declarations = append(program.Declarations, stmts...)
+			continue
+		}
+
 		stmt, err := c.ConvertStmt(child, sourceCode)
 		if err != nil {
 			return nil, newConverterError(ErrStmtConversion, "failed to convert statement", child, err)
@@ -233,7 +260,20 @@ func (c *CConverter) ConvertExpr(node *sitter.Node, sourceCode []byte) (interfac
 	case "identifier":
 		return c.convertIdentifier(node, sourceCode)
 	case "number_literal":
+		if c.isFloatLiteral(c.getNodeText(node, sourceCode)) {
+			return c.convertFloatLiteral(node, sourceCode)
+		}
 		return c.convertIntLiteral(node, sourceCode)
+	case "char_literal":
+		return c.convertCharLiteral(node, sourceCode)
+	case "string_literal":
+		return c.convertStringLiteral(node, sourceCode)
+	case "null":
+		return &structs.NullLiteral{Type: "NullLiteral", Loc: c.getLocation(node)}, nil
+	case "sizeof_expression":
+		return c.convertSizeofExpression(node, sourceCode)
+	case "cast_expression":
+		return c.convertCastExpression(node, sourceCode)
 	case "binary_expression":
 		return c.convertBinaryExpression(node, sourceCode)
 	case "unary_expression":
@@ -403,7 +443,7 @@ func (c *CConverter) convertDeclaration(node *sitter.Node, sourceCode []byte) (i
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		switch child.Type() {
-		case "primitive_type", "type_identifier":
+		case "primitive_type", "type_identifier", "enum_specifier":
 			typeNode = child
 		case "init_declarator":
 			// init_declarator содержит: declarator = initializer
@@ -440,8 +480,16 @@ func (c *CConverter) convertDeclaration(node *sitter.Node, sourceCode []byte) (i
 		return nil, newConverterError(ErrStmtConversion, "invalid declaration: missing type or declarator", node, nil)
 	}
 
+	baseType, enumDecl, err := c.resolveBaseType(typeNode, sourceCode, "")
+	if err != nil {
+		return nil, err
+	}
+	if enumDecl != nil {
+		return nil, newConverterError(ErrStmtConversion, "enum definition is not allowed in this declaration", typeNode, nil)
+	}
+
 	varType, varName := c.parseDeclarator(declaratorNode, sourceCode)
-	varType.BaseType = c.getNodeText(typeNode, sourceCode)
+	applyBaseType(&varType, baseType)
 
 	// Проверка на пустое имя переменной
 	if varName == "" {
@@ -449,7 +497,6 @@ func (c *CConverter) convertDeclaration(node *sitter.Node, sourceCode []byte) (i
 	}
 
 	var initExpr interfaces.Expr
-	var err error
 	if initNode != nil {
 		initExpr, err = c.ConvertExpr(initNode, sourceCode)
 		if err != nil {
@@ -483,7 +530,7 @@ func (c *CConverter) convertDeclarationsMultiple(node *sitter.Node, sourceCode [
 		childType := child.Type()
 
 		switch childType {
-		case "primitive_type", "type_identifier":
+		case "primitive_type", "type_identifier", "enum_specifier":
 			typeNode = child
 
 		case "init_declarator":
@@ -534,10 +581,19 @@ func (c *CConverter) convertDeclarationsMultiple(node *sitter.Node, sourceCode [
 		return nil, newConverterError(ErrStmtConversion, "declaration without declarators", node, nil)
 	}
 
+	baseType, enumDecl, err := c.resolveBaseType(typeNode, sourceCode, "")
+	if err != nil {
+		return nil, err
+	}
+	// enum Color { ... } c; - сначала определение перечисления, затем переменные
+	if enumDecl != nil {
+		declarations = append(declarations, enumDecl)
+	}
+
 	// Для каждого declarator создаём отдельное VariableDecl
 	for idx, declNode := range declaratorNodes {
 		varType, varName := c.parseDeclarator(declNode, sourceCode)
-		varType.BaseType = c.getNodeText(typeNode, sourceCode)
+		applyBaseType(&varType, baseType)
 
 		if varName == "" {
 			return nil, newConverterError(ErrStmtConversion, "declaration without variable name", declNode, nil)
@@ -577,7 +633,8 @@ func (c *CConverter) parseDeclarator(node *sitter.Node, sourceCode []byte) (stru
 	var name string
 
 	switch node.Type() {
-	case "identifier":
+	case "identifier", "type_identifier":
+		// type_identifier - имя псевдонима в typedef
 		name = c.getNodeText(node, sourceCode)
 
 	case "pointer_declarator":
@@ -594,8 +651,8 @@ func (c *CConverter) parseDeclarator(node *sitter.Node, sourceCode []byte) (stru
 				}
 			}
 		}
-		// После цикла current может быть identifier или array_declarator
-		if current.Type() == "identifier" {
+		// После цикла current может быть identifier (type_identifier в typedef) или array_declarator
+		if current.Type() == "identifier" || current.Type() == "type_identifier" {
 			name = c.getNodeText(current, sourceCode)
 		} else if current.Type() == "array_declarator" {
 			// Рекурсивно обработать array_declarator (например, int *arr[10])
@@ -663,14 +720,16 @@ func (c *CConverter) convertFunctionDefinition(node *sitter.Node, sourceCode []b
 		child := node.Child(i)
 
 		switch child.Type() {
-		case "primitive_type", "type_identifier":
-			typeName := c.getNodeText(child, sourceCode)
+		case "primitive_type", "type_identifier", "enum_specifier":
 			// Принимаем любой тип - семантика обработает
-			returnType = structs.Type{
-				BaseType:     typeName,
-				PointerLevel: 0,
-				ArraySizes:   make([]int, 0),
+			baseType, enumDecl, err := c.resolveBaseType(child, sourceCode, "")
+			if err != nil {
+				return nil, err
 			}
+			if enumDecl != nil {
+				return nil, newConverterError(ErrStmtConversion, "enum definition in function return type is not supported", child, nil)
+			}
+			returnType = baseType
 
 		case "function_declarator":
 			for j := 0; j < int(child.ChildCount()); j++ {
@@ -678,12 +737,19 @@ func (c *CConverter) convertFunctionDefinition(node *sitter.Node, sourceCode []b
 				if subChild.Type() == "identifier" {
 					funcName = c.getNodeText(subChild, sourceCode)
 				} else if subChild.Type() == "parameter_list" {
-					params = c.parseParameterList(subChild, sourceCode)
+					parsedParams, err := c.parseParameterList(subChild, sourceCode)
+					if err != nil {
+						return nil, err
+					}
+					params = parsedParams
 				}
 			}
 
 		case "pointer_declarator":
-			name, parsedParams, pointerLevel := c.parseFunctionDeclarator(child, sourceCode)
+			name, parsedParams, pointerLevel, err := c.parseFunctionDeclarator(child, sourceCode)
+			if err != nil {
+				return nil, err
+			}
 			if name != "" {
 				funcName = name
 			}
@@ -715,7 +781,7 @@ func (c *CConverter) convertFunctionDefinition(node *sitter.Node, sourceCode []b
 	}, nil
 }
 
-func (c *CConverter) parseFunctionDeclarator(node *sitter.Node, sourceCode []byte) (string, []structs.Parameter, int) {
+func (c *CConverter) parseFunctionDeclarator(node *sitter.Node, sourceCode []byte) (string, []structs.Parameter, int, error) {
 	var name string
 	var params []structs.Parameter
 	var pointerLevel int
@@ -738,17 +804,21 @@ func (c *CConverter) parseFunctionDeclarator(node *sitter.Node, sourceCode []byt
 			if child.Type() == "identifier" {
 				name = c.getNodeText(child, sourceCode)
 			} else if child.Type() == "parameter_list" {
-				params = c.parseParameterList(child, sourceCode)
+				parsedParams, err := c.parseParameterList(child, sourceCode)
+				if err != nil {
+					return "", nil, 0, err
+				}
+				params = parsedParams
 			}
 		}
 	} else if current.Type() == "identifier" {
 		name = c.getNodeText(current, sourceCode)
 	}
 
-	return name, params, pointerLevel
+	return name, params, pointerLevel, nil
 }
 
-func (c *CConverter) parseParameterList(node *sitter.Node, sourceCode []byte) []structs.Parameter {
+func (c *CConverter) parseParameterList(node *sitter.Node, sourceCode []byte) ([]structs.Parameter, error) {
 	params := make([]structs.Parameter, 0)
 
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -757,17 +827,29 @@ func (c *CConverter) parseParameterList(node *sitter.Node, sourceCode []byte) []
 		if child.Type() == "parameter_declaration" {
 			var paramType structs.Type
 			var paramName string
+			var baseType *structs.Type
 
 			for j := 0; j < int(child.ChildCount()); j++ {
 				subChild := child.Child(j)
 
-				if subChild.Type() == "primitive_type" || subChild.Type() == "type_identifier" {
-					paramType.BaseType = c.getNodeText(subChild, sourceCode)
+				if isTypeSpecifier(subChild.Type()) {
+					resolved, enumDecl, err := c.resolveBaseType(subChild, sourceCode, "")
+					if err != nil {
+						return nil, err
+					}
+					if enumDecl != nil {
+						return nil, newConverterError(ErrStmtConversion, "enum definition in parameter list is not supported", subChild, nil)
+					}
+					baseType = &resolved
 				} else if subChild.Type() == "identifier" || subChild.Type() == "pointer_declarator" ||
 					subChild.Type() == "array_declarator" {
 					paramType, paramName = c.parseDeclarator(subChild, sourceCode)
 				}
 			}
+			// parseDeclarator по умолчанию считает тип int, поэтому базовый тип задаем после
+			if baseType != nil {
+				applyBaseType(&paramType, *baseType)
+			}
 
 			params = append(params, structs.Parameter{
 				Type: paramType,
@@ -777,7 +859,7 @@ func (c *CConverter) parseParameterList(node *sitter.Node, sourceCode []byte) []
 		}
 	}
 
-	return params
+	return params, nil
 }
 
 func (c *CConverter) convertIfStatement(node *sitter.Node, sourceCode []byte) (interfaces.Stmt, error) {
@@ -1103,11 +1185,15 @@ func (c *CConverter) convertReturnStatement(node *sitter.Node, sourceCode []byte
 func (c *CConverter) convertBlockStatement(node *sitter.Node, sourceCode []byte) (interfaces.Stmt, error) {
 	statements := make([]interfaces.Stmt, 0)
 
+	// typedef и перечисления, объявленные в блоке, видны только внутри него
+	c.enterTypeScope()
+	defer c.exitTypeScope()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 
-		// Пропускаем фигурные скобки и комментарии
-		if child.Type() == "{" || child.Type() == "}" || child.Type() == "comment" {
+		// Пропускаем фигурные скобки, комментарии и ';' после определения перечисления
+		if child.Type() == "{" || child.Type() == "}" || child.Type() == "comment" || child.Type() == ";" {
 			continue
 		}
 
@@ -1121,6 +1207,15 @@ func (c *CConverter) convertBlockStatement(node *sitter.Node, sourceCode []byte)
 			continue
 		}
 
+		if child.Type() == "type_definition" || child.Type() == "enum_specifier" {
+			stmts, err := c.convertTypeDeclarations(child, sourceCode)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, stmts...)
+			continue
+		}
+
 		stmt, err := c.ConvertStmt(child, sourceCode)
 		if err != nil {
 			return nil, err
@@ -1160,6 +1255,295 @@ func (c *CConverter) convertExpressionStatement(node *sitter.Node, sourceCode []
 	}, nil
 }
 
+// ============= Enum и typedef =============
+
+// typeScope хранит псевдонимы typedef, перечисления и их константы, объявленные в одной области видимости
+type typeScope struct {
+	typedefs      map[string]structs.Type
+	enums         map[string]bool
+	enumConstants map[string]int
+}
+
+func newTypeScope() *typeScope {
+	return &typeScope{
+		typedefs:      make(map[string]structs.Type),
+		enums:         make(map[string]bool),
+		enumConstants: make(map[string]int),
+	}
+}
+
+func (c *CConverter) resetTypeScopes() {
+	c.typeScopes = []*typeScope{newTypeScope()}
+}
+
+func (c *CConverter) enterTypeScope() {
+	c.typeScopes = append(c.typeScopes, newTypeScope())
+}
+
+func (c *CConverter) exitTypeScope() {
+	c.typeScopes = c.typeScopes[:len(c.typeScopes)-1]
+}
+
+func (c *CConverter) currentTypeScope() *typeScope {
+	return c.typeScopes[len(c.typeScopes)-1]
+}
+
+func (c *CConverter) lookupTypedef(name string) (structs.Type, bool) {
+	for i := len(c.typeScopes) - 1; i >= 0; i-- {
+		if t, ok := c.typeScopes[i].typedefs[name]; ok {
+			return t, true
+		}
+	}
+	return structs.Type{}, false
+}
+
+func (c *CConverter) lookupEnum(name string) bool {
+	for i := len(c.typeScopes) - 1; i >= 0; i-- {
+		if c.typeScopes[i].enums[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CConverter) lookupEnumConstant(name string) (int, bool) {
+	for i := len(c.typeScopes) - 1; i >= 0; i-- {
+		if v, ok := c.typeScopes[i].enumConstants[name]; ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// isTypeSpecifier проверяет, является ли узел спецификатором базового типа объявления
+func isTypeSpecifier(nodeType string) bool {
+	return nodeType == "primitive_type" || nodeType == "type_identifier" || nodeType == "enum_specifier"
+}
+
+// resolveBaseType возвращает базовый тип по спецификатору типа. Псевдонимы typedef раскрываются
+// в исходный тип, а определение перечисления с телом дополнительно возвращается как EnumDecl.
+// anonymousName задает имя анонимному перечислению (используется для typedef enum { ... } Name)
+func (c *CConverter) resolveBaseType(node *sitter.Node, sourceCode []byte, anonymousName string) (structs.Type, *structs.EnumDecl, error) {
+	switch node.Type() {
+	case "enum_specifier":
+		return c.convertEnumSpecifier(node, sourceCode, anonymousName)
+	case "type_identifier":
+		name := c.getNodeText(node, sourceCode)
+		if underlying, ok := c.lookupTypedef(name); ok {
+			underlying.ArraySizes = append(make([]int, 0, len(underlying.ArraySizes)), underlying.ArraySizes...)
+			return underlying, nil, nil
+		}
+		// Неизвестное имя типа оставляем как есть - его отклонит семантический анализатор
+		return structs.Type{BaseType: name, ArraySizes: make([]int, 0)}, nil, nil
+	default:
+		return structs.Type{BaseType: c.getNodeText(node, sourceCode), ArraySizes: make([]int, 0)}, nil, nil
+	}
+}
+
+// applyBaseType дополняет тип, полученный из declarator, базовым типом.
+// Размеры массива из typedef идут после размеров из declarator: typedef int row[3]; row m[2] -> int m[2][3]
+func applyBaseType(varType *structs.Type, base structs.Type) {
+	varType.BaseType = base.BaseType
+	varType.Enum = base.Enum
+	varType.PointerLevel += base.PointerLevel
+	if len(base.ArraySizes) > 0 {
+		varType.ArraySizes = append(varType.ArraySizes, base.ArraySizes...)
+	}
+}
+
+// convertEnumSpecifier обрабатывает enum Name или enum [Name] { A, B = 5, C }.
+// Значения констант вычисляются сразу: без явного значения константа на единицу больше предыдущей
+func (c *CConverter) convertEnumSpecifier(node *sitter.Node, sourceCode []byte, anonymousName string) (structs.Type, *structs.EnumDecl, error) {
+	name := anonymousName
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		name = c.getNodeText(nameNode, sourceCode)
+	}
+
+	enumType := structs.Type{BaseType: "int", ArraySizes: make([]int, 0), Enum: name}
+
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		if !c.lookupEnum(name) {
+			return structs.Type{}, nil, newConverterError(ErrEnumDefinition, fmt.Sprintf("unknown enum %s", name), node, nil)
+		}
+		return enumType, nil, nil
+	}
+
+	scope := c.currentTypeScope()
+	if name != "" && scope.enums[name] {
+		return structs.Type{}, nil, newConverterError(ErrEnumDefinition, fmt.Sprintf("redefinition of enum %s", name), node, nil)
+	}
+
+	decl := &structs.EnumDecl{
+		Type:        "EnumDecl",
+		Name:        name,
+		Enumerators: make([]structs.Enumerator, 0),
+		Loc:         c.getLocation(node),
+	}
+
+	next := 0
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		child := body.NamedChild(i)
+		if child.Type() != "enumerator" {
+			continue
+		}
+
+		enumeratorName := c.getNodeText(child.ChildByFieldName("name"), sourceCode)
+		if _, exists := scope.enumConstants[enumeratorName]; exists {
+			return structs.Type{}, nil, newConverterError(ErrEnumDefinition, fmt.Sprintf("redeclaration of enumerator %s", enumeratorName), child, nil)
+		}
+
+		value := next
+		if valueNode := child.ChildByFieldName("value"); valueNode != nil {
+			expr, err := c.ConvertExpr(valueNode, sourceCode)
+			if err != nil {
+				return structs.Type{}, nil, newConverterError(ErrEnumDefinition, fmt.Sprintf("failed to convert value of enumerator %s", enumeratorName), valueNode, err)
+			}
+			v, ok := c.evalConstExpr(expr)
+			if !ok {
+				return structs.Type{}, nil, newConverterError(ErrEnumDefinition, fmt.Sprintf("enumerator value for %s is not an integer constant", enumeratorName), valueNode, nil)
+			}
+			value = v
+		}
+
+		scope.enumConstants[enumeratorName] = value
+		decl.Enumerators = append(decl.Enumerators, structs.Enumerator{
+			Name:  enumeratorName,
+			Value: value,
+			Loc:   c.getLocation(child),
+		})
+		next = value + 1
+	}
+
+	if name != "" {
+		scope.enums[name] = true
+	}
+
+	return enumType, decl, nil
+}
+
+// evalConstExpr вычисляет целочисленное константное выражение из литералов и ранее объявленных констант перечислений
+func (c *CConverter) evalConstExpr(expr interfaces.Expr) (int, bool) {
+	switch e := expr.(type) {
+	case *structs.IntLiteral:
+		return e.Value, true
+	case *structs.CharLiteral:
+		return e.Value, true
+	case *structs.VariableExpr:
+		return c.lookupEnumConstant(e.Name)
+	case *structs.UnaryExpr:
+		v, ok := c.evalConstExpr(e.Operand)
+		if !ok {
+			return 0, false
+		}
+		switch e.Operator {
+		case "-":
+			return -v, true
+		case "+":
+			return v, true
+		case "!":
+			return boolToInt(v == 0), true
+		}
+	case *structs.BinaryExpr:
+		l, ok := c.evalConstExpr(e.Left)
+		if !ok {
+			return 0, false
+		}
+		r, ok := c.evalConstExpr(e.Right)
+		if !ok {
+			return 0, false
+		}
+		switch e.Operator {
+		case "+":
+			return l + r, true
+		case "-":
+			return l - r, true
+		case "*":
+			return l * r, true
+		case "/":
+			if r != 0 {
+				return l / r, true
+			}
+		case "%":
+			if r != 0 {
+				return l % r, true
+			}
+		case "==":
+			return boolToInt(l == r), true
+		case "!=":
+			return boolToInt(l != r), true
+		case "<":
+			return boolToInt(l < r), true
+		case "<=":
+			return boolToInt(l <= r), true
+		case ">":
+			return boolToInt(l > r), true
+		case ">=":
+			return boolToInt(l >= r), true
+		}
+	}
+	return 0, false
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// convertTypeDeclarations обрабатывает определение перечисления без переменных (enum Color { ... };)
+// и объявление typedef. Возвращает EnumDecl (если перечисление определено) и TypedefDecl
+func (c *CConverter) convertTypeDeclarations(node *sitter.Node, sourceCode []byte) ([]interfaces.Stmt, error) {
+	if node.Type() == "enum_specifier" {
+		_, enumDecl, err := c.convertEnumSpecifier(node, sourceCode, "")
+		if err != nil {
+			return nil, err
+		}
+		if enumDecl == nil {
+			return nil, nil
+		}
+		return []interfaces.Stmt{enumDecl}, nil
+	}
+
+	// type_definition: typedef type declarator ;
+	typeNode := node.ChildByFieldName("type")
+	declaratorNode := node.ChildByFieldName("declarator")
+	if typeNode == nil || declaratorNode == nil {
+		return nil, newConverterError(ErrStmtConversion, "invalid typedef: missing type or declarator", node, nil)
+	}
+
+	aliasType, aliasName := c.parseDeclarator(declaratorNode, sourceCode)
+	if aliasName == "" {
+		return nil, newConverterError(ErrStmtConversion, "typedef without name", node, nil)
+	}
+
+	base, enumDecl, err := c.resolveBaseType(typeNode, sourceCode, aliasName)
+	if err != nil {
+		return nil, err
+	}
+	applyBaseType(&aliasType, base)
+
+	scope := c.currentTypeScope()
+	if _, exists := scope.typedefs[aliasName]; exists {
+		return nil, newConverterError(ErrStmtConversion, fmt.Sprintf("redefinition of typedef %s", aliasName), node, nil)
+	}
+	scope.typedefs[aliasName] = aliasType
+
+	stmts := make([]interfaces.Stmt, 0, 2)
+	if enumDecl != nil {
+		stmts = append(stmts, enumDecl)
+	}
+	stmts = append(stmts, &structs.TypedefDecl{
+		Type:       "TypedefDecl",
+		Name:       aliasName,
+		Underlying: aliasType,
+		Loc:        c.getLocation(node),
+	})
+
+	return stmts, nil
+}
+
 // ============= Expression converters =============
 
 func (c *CConverter) convertIdentifier(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
@@ -1204,6 +1588,200 @@ func (c *CConverter) convertIntLiteral(node *sitter.Node, sourceCode []byte) (in
 	}, nil
 }
 
+// isFloatLiteral определяет, является ли числовой литерал вещественным:
+// десятичный литерал с точкой, экспонентой или суффиксом f/F
+func (c *CConverter) isFloatLiteral(text string) bool {
+	lower := strings.ToLower(text)
+	if strings.HasPrefix(lower, "0x") {
+		return false
+	}
+	return strings.ContainsAny(lower, ".ef")
+}
+
+func (c *CConverter) convertFloatLiteral(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
+	text := c.getNodeText(node, sourceCode)
+	isFloat := strings.HasSuffix(text, "f") || strings.HasSuffix(text, "F")
+	value, err := strconv.ParseFloat(strings.TrimRight(text, "fFlL"), 64)
+	if err != nil {
+		return nil, newConverterError(ErrFloatLiteralParse, "failed to parse floating literal", node, err)
+	}
+
+	return &structs.FloatLiteral{
+		Type:    "FloatLiteral",
+		Value:   value,
+		IsFloat: isFloat,
+		Loc:     c.getLocation(node),
+	}, nil
+}
+
+func (c *CConverter) convertCharLiteral(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
+	text := c.getNodeText(node, sourceCode)
+	if len(text) < 2 || text[0] != '\'' || text[len(text)-1] != '\'' {
+		return nil, newConverterError(ErrCharLiteralParse, "unsupported character literal", node, nil)
+	}
+
+	decoded, err := unescapeC(text[1 : len(text)-1])
+	if err != nil {
+		return nil, newConverterError(ErrCharLiteralParse, "failed to parse character literal", node, err)
+	}
+	if len(decoded) != 1 {
+		return nil, newConverterError(ErrCharLiteralParse, "character literal must contain exactly one character", node, nil)
+	}
+
+	return &structs.CharLiteral{
+		Type:  "CharLiteral",
+		Value: int(int8(decoded[0])),
+		Loc:   c.getLocation(node),
+	}, nil
+}
+
+// parseTypeDescriptor разбирает имя типа в sizeof и приведении типа: базовый тип и звездочки указателя (int*, double)
+func (c *CConverter) parseTypeDescriptor(node *sitter.Node, sourceCode []byte) (structs.Type, error) {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return structs.Type{}, newConverterError(ErrExprUnsupported, "type name without type", node, nil)
+	}
+
+	base, enumDecl, err := c.resolveBaseType(typeNode, sourceCode, "")
+	if err != nil {
+		return structs.Type{}, err
+	}
+	if enumDecl != nil {
+		return structs.Type{}, newConverterError(ErrExprUnsupported, "enum definition in type name is not supported", typeNode, nil)
+	}
+
+	t := structs.Type{ArraySizes: make([]int, 0)}
+	for declarator := node.ChildByFieldName("declarator"); declarator != nil; declarator = declarator.ChildByFieldName("declarator") {
+		if declarator.Type() != "abstract_pointer_declarator" {
+			return structs.Type{}, newConverterError(ErrExprUnsupported, fmt.Sprintf("unsupported type name declarator: %s", declarator.Type()), declarator, nil)
+		}
+		t.PointerLevel++
+	}
+	applyBaseType(&t, base)
+
+	return t, nil
+}
+
+func (c *CConverter) convertSizeofExpression(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
+	sizeofExpr := &structs.SizeofExpr{Type: "SizeofExpr", Loc: c.getLocation(node)}
+
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		argType, err := c.parseTypeDescriptor(typeNode, sourceCode)
+		if err != nil {
+			return nil, err
+		}
+		sizeofExpr.ArgType = &argType
+		return sizeofExpr, nil
+	}
+
+	valueNode := node.ChildByFieldName("value")
+	if valueNode == nil {
+		return nil, newConverterError(ErrExprUnsupported, "sizeof without operand", node, nil)
+	}
+	operand, err := c.ConvertExpr(valueNode, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	sizeofExpr.Operand = operand
+
+	return sizeofExpr, nil
+}
+
+func (c *CConverter) convertCastExpression(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
+	typeNode := node.ChildByFieldName("type")
+	valueNode := node.ChildByFieldName("value")
+	if typeNode == nil || valueNode == nil {
+		return nil, newConverterError(ErrExprUnsupported, "invalid cast expression", node, nil)
+	}
+
+	targetType, err := c.parseTypeDescriptor(typeNode, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	operand, err := c.ConvertExpr(valueNode, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.CastExpr{
+		Type:       "CastExpr",
+		TargetType: targetType,
+		Operand:    operand,
+		Loc:        c.getLocation(node),
+	}, nil
+}
+
+func (c *CConverter) convertStringLiteral(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
+	text := c.getNodeText(node, sourceCode)
+	if len(text) < 2 || text[0] != '"' || text[len(text)-1] != '"' {
+		return nil, newConverterError(ErrStringLiteralParse, "unsupported string literal", node, nil)
+	}
+
+	decoded, err := unescapeC(text[1 : len(text)-1])
+	if err != nil {
+		return nil, newConverterError(ErrStringLiteralParse, "failed to parse string literal", node, err)
+	}
+
+	return &structs.StringLiteral{
+		Type:  "StringLiteral",
+		Value: string(decoded),
+		Loc:   c.getLocation(node),
+	}, nil
+}
+
+// unescapeC раскрывает escape-последовательности C: \n, \t, \0, \\, \', \", \xHH и восьмеричные \ooo
+func unescapeC(text string) ([]byte, error) {
+	simple := map[byte]byte{
+		'n': '\n', 't': '\t', 'r': '\r', 'a': '\a', 'b': '\b', 'f': '\f', 'v': '\v',
+		'\\': '\\', '\'': '\'', '"': '"', '?': '?',
+	}
+
+	result := make([]byte, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			result = append(result, text[i])
+			continue
+		}
+		i++
+		if i >= len(text) {
+			return nil, fmt.Errorf("unterminated escape sequence")
+		}
+		if b, ok := simple[text[i]]; ok {
+			result = append(result, b)
+			continue
+		}
+		switch {
+		case text[i] >= '0' && text[i] <= '7':
+			end := i
+			for end < len(text) && end < i+3 && text[end] >= '0' && text[end] <= '7' {
+				end++
+			}
+			value, err := strconv.ParseUint(text[i:end], 8, 16)
+			if err != nil || value > 0xff {
+				return nil, fmt.Errorf("octal escape sequence out of range: \\%s", text[i:end])
+			}
+			result = append(result, byte(value))
+			i = end - 1
+		case text[i] == 'x':
+			end := i + 1
+			for end < len(text) && strings.ContainsRune("0123456789abcdefABCDEF", rune(text[end])) {
+				end++
+			}
+			value, err := strconv.ParseUint(text[i+1:end], 16, 16)
+			if err != nil || value > 0xff {
+				return nil, fmt.Errorf("hex escape sequence out of range: \\%s", text[i:end])
+			}
+			result = append(result, byte(value))
+			i = end - 1
+		default:
+			return nil, fmt.Errorf("unknown escape sequence: \\%c", text[i])
+		}
+	}
+
+	return result, nil
+}
+
 func (c *CConverter) convertBinaryExpression(node *sitter.Node, sourceCode []byte) (interfaces.Expr, error) {
 	var left interfaces.Expr
 	var operator string
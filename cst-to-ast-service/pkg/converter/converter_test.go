@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/internal/domain/structs"
@@ -410,6 +411,123 @@ func TestParseNegativeLiteral(t *testing.T) {
 	}
 }
 
+// TestParseFloatLiteral проверяет парсинг вещественных литералов и типов float/double
+func TestParseFloatLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		varType  string
+		expected float64
+		isFloat  bool
+	}{
+		{name: "double", code: "double x = 1.5;", varType: "double", expected: 1.5},
+		{name: "exponent", code: "double x = 2e-3;", varType: "double", expected: 0.002},
+		{name: "leading_dot", code: "double x = .25;", varType: "double", expected: 0.25},
+		{name: "float_suffix", code: "float x = 0.5f;", varType: "float", expected: 0.5, isFloat: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := NewCConverter()
+			program, convErr := conv.ParseToAST(tt.code)
+			if convErr != nil {
+				t.Fatalf("ParseToAST failed: %v", convErr)
+			}
+
+			varDecl := program.Declarations[0].(*structs.VariableDecl)
+			if varDecl.VarType.BaseType != tt.varType {
+				t.Errorf("Expected base type %s, got %s", tt.varType, varDecl.VarType.BaseType)
+			}
+
+			lit, ok := varDecl.InitExpr.(*structs.FloatLiteral)
+			if !ok {
+				t.Fatalf("Expected FloatLiteral, got %T", varDecl.InitExpr)
+			}
+			if lit.Value != tt.expected {
+				t.Errorf("Expected value %v, got %v", tt.expected, lit.Value)
+			}
+			if lit.IsFloat != tt.isFloat {
+				t.Errorf("Expected isFloat %v, got %v", tt.isFloat, lit.IsFloat)
+			}
+		})
+	}
+}
+
+// TestParseCharLiteral проверяет парсинг символьных литералов и escape-последовательностей
+func TestParseCharLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{name: "letter", code: "char c = 'a';", expected: 97},
+		{name: "newline", code: `char c = '\n';`, expected: 10},
+		{name: "terminator", code: `char c = '\0';`, expected: 0},
+		{name: "quote", code: `char c = '\'';`, expected: 39},
+		{name: "backslash", code: `char c = '\\';`, expected: 92},
+		{name: "hex", code: `char c = '\x41';`, expected: 65},
+		{name: "octal", code: `char c = '\101';`, expected: 65},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := NewCConverter()
+			program, convErr := conv.ParseToAST(tt.code)
+			if convErr != nil {
+				t.Fatalf("ParseToAST failed: %v", convErr)
+			}
+
+			varDecl := program.Declarations[0].(*structs.VariableDecl)
+			if varDecl.VarType.BaseType != "char" {
+				t.Errorf("Expected base type char, got %s", varDecl.VarType.BaseType)
+			}
+
+			lit, ok := varDecl.InitExpr.(*structs.CharLiteral)
+			if !ok {
+				t.Fatalf("Expected CharLiteral, got %T", varDecl.InitExpr)
+			}
+			if lit.Value != tt.expected {
+				t.Errorf("Expected code %d, got %d", tt.expected, lit.Value)
+			}
+		})
+	}
+}
+
+// TestParseStringLiteral проверяет парсинг строкового литерала в инициализаторе массива char
+func TestParseStringLiteral(t *testing.T) {
+	conv := NewCConverter()
+	program, convErr := conv.ParseToAST(`char s[16] = "a\tb\"c\0";`)
+	if convErr != nil {
+		t.Fatalf("ParseToAST failed: %v", convErr)
+	}
+
+	varDecl := program.Declarations[0].(*structs.VariableDecl)
+	if len(varDecl.VarType.ArraySizes) != 1 || varDecl.VarType.ArraySizes[0] != 16 {
+		t.Fatalf("Expected array of size 16, got %v", varDecl.VarType.ArraySizes)
+	}
+
+	lit, ok := varDecl.InitExpr.(*structs.StringLiteral)
+	if !ok {
+		t.Fatalf("Expected StringLiteral, got %T", varDecl.InitExpr)
+	}
+	if lit.Value != "a\tb\"c\x00" {
+		t.Errorf("Expected decoded string %q, got %q", "a\tb\"c\x00", lit.Value)
+	}
+}
+
+// TestParseHexLiteralIsNotFloat проверяет, что шестнадцатеричный литерал с цифрой f
+// не разбирается как вещественный
+func TestParseHexLiteralIsNotFloat(t *testing.T) {
+	conv := NewCConverter()
+	_, convErr := conv.ParseToAST("int x = 0x1f;")
+	if convErr == nil {
+		return
+	}
+	if convErr.GetCode() == ErrFloatLiteralParse {
+		t.Fatalf("Expected hex literal not to be parsed as floating, got %v", convErr)
+	}
+}
+
 // TestParsePrefixIncrement проверяет парсинг префиксного инкремента
 func TestParsePrefixIncrement(t *testing.T) {
 	sourceCode := []byte(`int main() {
@@ -1474,6 +1592,248 @@ func TestParseVoidReturnType(t *testing.T) {
 	}
 }
 
+// TestParseParameterTypes проверяет, что базовый тип параметра берется из объявления
+func TestParseParameterTypes(t *testing.T) {
+	sourceCode := []byte(`double mix(int a, double b, float c) {
+	return a + b + c;
+}`)
+
+	conv := NewCConverter()
+	tree, err := conv.Parse(sourceCode)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	ast, err := conv.ConvertToProgram(tree, sourceCode)
+	if err != nil {
+		t.Fatalf("ConvertToProgram failed: %v", err)
+	}
+
+	program := ast.(*structs.Program)
+	funcDecl := program.Declarations[0].(*structs.FunctionDecl)
+
+	if funcDecl.ReturnType.BaseType != "double" {
+		t.Errorf("Expected return type 'double', got '%s'", funcDecl.ReturnType.BaseType)
+	}
+
+	expected := []string{"int", "double", "float"}
+	if len(funcDecl.Parameters) != len(expected) {
+		t.Fatalf("Expected %d parameters, got %d", len(expected), len(funcDecl.Parameters))
+	}
+	for i, param := range funcDecl.Parameters {
+		if param.Type.BaseType != expected[i] {
+			t.Errorf("Expected parameter %s type '%s', got '%s'", param.Name, expected[i], param.Type.BaseType)
+		}
+	}
+}
+
+// TestParseEnumDecl проверяет вычисление значений констант перечисления и тип enum-переменных
+func TestParseEnumDecl(t *testing.T) {
+	sourceCode := []byte(`enum Color { RED, GREEN = 5, BLUE, LAST = BLUE * 2 - 'a' + 'a' };
+enum Color g = RED;
+int main() {
+	enum Color c = BLUE;
+	return c;
+}`)
+
+	conv := NewCConverter()
+	tree, err := conv.Parse(sourceCode)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	ast, err := conv.ConvertToProgram(tree, sourceCode)
+	if err != nil {
+		t.Fatalf("ConvertToProgram failed: %v", err)
+	}
+
+	program := ast.(*structs.Program)
+	if len(program.Declarations) != 3 {
+		t.Fatalf("Expected 3 declarations, got %d", len(program.Declarations))
+	}
+
+	enumDecl, ok := program.Declarations[0].(*structs.EnumDecl)
+	if !ok {
+		t.Fatalf("Expected EnumDecl, got %T", program.Declarations[0])
+	}
+	if enumDecl.Name != "Color" {
+		t.Errorf("Expected enum name 'Color', got '%s'", enumDecl.Name)
+	}
+
+	expected := []structs.Enumerator{{Name: "RED", Value: 0}, {Name: "GREEN", Value: 5}, {Name: "BLUE", Value: 6}, {Name: "LAST", Value: 12}}
+	if len(enumDecl.Enumerators) != len(expected) {
+		t.Fatalf("Expected %d enumerators, got %d", len(expected), len(enumDecl.Enumerators))
+	}
+	for i, e := range enumDecl.Enumerators {
+		if e.Name != expected[i].Name || e.Value != expected[i].Value {
+			t.Errorf("Expected enumerator %s = %d, got %s = %d", expected[i].Name, expected[i].Value, e.Name, e.Value)
+		}
+	}
+
+	global := program.Declarations[1].(*structs.VariableDecl)
+	if global.VarType.BaseType != "int" || global.VarType.Enum != "Color" {
+		t.Errorf("Expected int type of enum Color, got %+v", global.VarType)
+	}
+
+	funcDecl := program.Declarations[2].(*structs.FunctionDecl)
+	local := funcDecl.Body.Statements[0].(*structs.VariableDecl)
+	if local.VarType.Enum != "Color" {
+		t.Errorf("Expected local variable of enum Color, got %+v", local.VarType)
+	}
+	if init, ok := local.InitExpr.(*structs.VariableExpr); !ok || init.Name != "BLUE" {
+		t.Errorf("Expected enumerator BLUE as initializer, got %#v", local.InitExpr)
+	}
+}
+
+// TestParseTypedef проверяет раскрытие псевдонимов typedef в исходный тип
+func TestParseTypedef(t *testing.T) {
+	sourceCode := []byte(`typedef int myint;
+typedef myint row[3];
+typedef enum { OFF, ON } Switch;
+myint count(row r, Switch s) {
+	row m[2];
+	Switch state = ON;
+	return 0;
+}`)
+
+	conv := NewCConverter()
+	tree, err := conv.Parse(sourceCode)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	ast, err := conv.ConvertToProgram(tree, sourceCode)
+	if err != nil {
+		t.Fatalf("ConvertToProgram failed: %v", err)
+	}
+
+	program := ast.(*structs.Program)
+	if len(program.Declarations) != 5 {
+		t.Fatalf("Expected 5 declarations, got %d", len(program.Declarations))
+	}
+
+	myint := program.Declarations[0].(*structs.TypedefDecl)
+	if myint.Name != "myint" || myint.Underlying.BaseType != "int" {
+		t.Errorf("Expected typedef myint = int, got %+v", myint)
+	}
+
+	row := program.Declarations[1].(*structs.TypedefDecl)
+	if row.Underlying.BaseType != "int" || len(row.Underlying.ArraySizes) != 1 || row.Underlying.ArraySizes[0] != 3 {
+		t.Errorf("Expected typedef row = int[3], got %+v", row.Underlying)
+	}
+
+	enumDecl := program.Declarations[2].(*structs.EnumDecl)
+	if enumDecl.Name != "Switch" {
+		t.Errorf("Expected anonymous enum named after typedef 'Switch', got '%s'", enumDecl.Name)
+	}
+
+	funcDecl := program.Declarations[4].(*structs.FunctionDecl)
+	if funcDecl.ReturnType.BaseType != "int" {
+		t.Errorf("Expected return type 'int', got '%s'", funcDecl.ReturnType.BaseType)
+	}
+	if funcDecl.Parameters[1].Type.Enum != "Switch" {
+		t.Errorf("Expected parameter s of enum Switch, got %+v", funcDecl.Parameters[1].Type)
+	}
+
+	m := funcDecl.Body.Statements[0].(*structs.VariableDecl)
+	if len(m.VarType.ArraySizes) != 2 || m.VarType.ArraySizes[0] != 2 || m.VarType.ArraySizes[1] != 3 {
+		t.Errorf("Expected m to be int[2][3], got %v", m.VarType.ArraySizes)
+	}
+
+	state := funcDecl.Body.Statements[1].(*structs.VariableDecl)
+	if state.VarType.BaseType != "int" || state.VarType.Enum != "Switch" {
+		t.Errorf("Expected state of enum Switch, got %+v", state.VarType)
+	}
+}
+
+// TestParseEnumErrors проверяет ошибки в определениях перечислений
+func TestParseEnumErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		message string
+	}{
+		{"unknown enum", "enum Shape s;", "unknown enum Shape"},
+		{"duplicate enumerator", "enum A { X, Y, X };", "redeclaration of enumerator X"},
+		{"non-constant value", "int n = 1;\nenum A { X = n };", "enumerator value for X is not an integer constant"},
+		{"redefinition", "enum A { X };\nenum A { Y };", "redefinition of enum A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New().ParseToAST(tt.code)
+			if err == nil {
+				t.Fatalf("Expected error for %q", tt.code)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error containing %q, got %q", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+// TestEnumScope проверяет, что перечисление из тела функции не видно в другой функции
+func TestEnumScope(t *testing.T) {
+	code := `int f() { enum Local { A }; return A; }
+int g() { enum Local { A, B }; enum Local x = B; return x; }`
+
+	if _, err := New().ParseToAST(code); err != nil {
+		t.Fatalf("Expected independent local enums, got %v", err)
+	}
+}
+
+// TestParseSizeofCastAndNull проверяет sizeof, приведение типа и NULL (а также пропуск #include)
+func TestParseSizeofCastAndNull(t *testing.T) {
+	code := `#include <stdlib.h>
+int main() {
+	int *p = (int*)malloc(3 * sizeof(int));
+	int n = sizeof p;
+	double d = (double)n;
+	p = NULL;
+	return 0;
+}`
+
+	program, convErr := New().ParseToAST(code)
+	if convErr != nil {
+		t.Fatalf("ParseToAST failed: %v", convErr)
+	}
+
+	funcDecl := program.Declarations[0].(*structs.FunctionDecl)
+	stmts := funcDecl.Body.Statements
+
+	p := stmts[0].(*structs.VariableDecl)
+	if p.VarType.BaseType != "int" || p.VarType.PointerLevel != 1 {
+		t.Errorf("Expected p of type int*, got %+v", p.VarType)
+	}
+	cast, ok := p.InitExpr.(*structs.CastExpr)
+	if !ok {
+		t.Fatalf("Expected CastExpr, got %T", p.InitExpr)
+	}
+	if cast.TargetType.BaseType != "int" || cast.TargetType.PointerLevel != 1 {
+		t.Errorf("Expected cast to int*, got %+v", cast.TargetType)
+	}
+	call := cast.Operand.(*structs.CallExpr)
+	sizeofType := call.Arguments[0].(*structs.BinaryExpr).Right.(*structs.SizeofExpr)
+	if sizeofType.ArgType == nil || sizeofType.ArgType.BaseType != "int" || sizeofType.Operand != nil {
+		t.Errorf("Expected sizeof(int), got %+v", sizeofType)
+	}
+
+	sizeofVar := stmts[1].(*structs.VariableDecl).InitExpr.(*structs.SizeofExpr)
+	if operand, ok := sizeofVar.Operand.(*structs.VariableExpr); !ok || operand.Name != "p" || sizeofVar.ArgType != nil {
+		t.Errorf("Expected sizeof p, got %+v", sizeofVar)
+	}
+
+	toDouble := stmts[2].(*structs.VariableDecl).InitExpr.(*structs.CastExpr)
+	if toDouble.TargetType.BaseType != "double" || toDouble.TargetType.PointerLevel != 0 {
+		t.Errorf("Expected cast to double, got %+v", toDouble.TargetType)
+	}
+
+	assign := stmts[3].(*structs.ExprStmt).Expression.(*structs.AssignmentExpr)
+	if _, ok := assign.Right.(*structs.NullLiteral); !ok {
+		t.Errorf("Expected NullLiteral, got %T", assign.Right)
+	}
+}
+
 // TestCommentsSingleLine проверяет, что однострочные комментарии игнорируются
 func TestCommentsSingleLine(t *testing.T) {
 	sourceCode := []byte(`int main() {
@@ -2026,7 +2386,6 @@ func contains(s, substr string) bool {
 	return false
 }
 
-
 // TestParseDoWhile проверяет парсинг do while statement
 func TestParseDoWhile(t *testing.T) {
 	sourceCode := []byte(`int main() {
@@ -57,7 +57,9 @@
 //	}
 //
 // Поддерживаемое подмножество C:
-//   - Типы: int, int*, int**, int[N]
+//   - Типы: int, char, float, double, int*, int**, int[N], char[N] со строковыми литералами
+//   - Перечисления enum и псевдонимы typedef (раскрываются в исходный тип)
+//   - sizeof, явное приведение типа (int*)p, (double)n и NULL
 //   - Переменные с инициализацией
 //   - Функции с параметрами
 //   - Операторы: if/else if/else, while, do-while, for, return, break, continue, goto, label
@@ -11,12 +11,16 @@ import (
 type ErrorCode string
 
 const (
-	ErrParseFailed     ErrorCode = "ParseFailed"
-	ErrStmtUnsupported ErrorCode = "StmtUnsupported"
-	ErrExprUnsupported ErrorCode = "ExprUnsupported"
-	ErrTreeSitterError ErrorCode = "TreeSitterError"
-	ErrIntLiteralParse ErrorCode = "IntLiteralParse"
-	ErrStmtConversion  ErrorCode = "StmtConversion"
+	ErrParseFailed        ErrorCode = "ParseFailed"
+	ErrStmtUnsupported    ErrorCode = "StmtUnsupported"
+	ErrExprUnsupported    ErrorCode = "ExprUnsupported"
+	ErrTreeSitterError    ErrorCode = "TreeSitterError"
+	ErrIntLiteralParse    ErrorCode = "IntLiteralParse"
+	ErrFloatLiteralParse  ErrorCode = "FloatLiteralParse"
+	ErrCharLiteralParse   ErrorCode = "CharLiteralParse"
+	ErrStringLiteralParse ErrorCode = "StringLiteralParse"
+	ErrEnumDefinition     ErrorCode = "EnumDefinition"
+	ErrStmtConversion     ErrorCode = "StmtConversion"
 )
 
 // ConverterError представляет ошибку парсинга с полной информацией для интерпретатора
@@ -1,10 +1,11 @@
-export async function getSnapshot(code, step) {
+// getSnapshot возвращает снимок состояния на шаге step и значения наблюдаемых выражений watch
+export async function getSnapshot(code, step, watch) {
   const response = await fetch('/api/snapshot', {
     method: 'POST',
     headers: {
       'Content-Type': 'application/json',
     },
-    body: JSON.stringify({ code, step }),
+    body: JSON.stringify({ code, step, watch, read_events: true, index_markers: true }),
   })
 
   const data = await response.json()
@@ -16,3 +17,101 @@ export async function getSnapshot(code, step) {
 
   return data
 }
+
+// navigate выполняет команду навигации (step_over, step_out, next_line, ...) от шага step
+export async function navigate(code, step, action, line, watch) {
+  const response = await fetch(`/api/navigate/${action}`, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    body: JSON.stringify({ code, step, line, watch, read_events: true, index_markers: true }),
+  })
+
+  const data = await response.json()
+
+  if (!data.success) {
+    throw new Error(data.error || 'Неизвестная ошибка')
+  }
+
+  return data
+}
+
+// findBreakpoint ищет от шага step ближайший шаг (direction: forward или backward),
+// на котором срабатывает точка останова или точка наблюдения
+export async function findBreakpoint(code, step, direction, breakpoints, watchpoints, watch) {
+  const response = await fetch('/api/breakpoints', {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    body: JSON.stringify({ code, step, direction, breakpoints, watchpoints, watch, read_events: true, index_markers: true }),
+  })
+
+  const data = await response.json()
+
+  if (!data.success) {
+    throw new Error(data.error || 'Неизвестная ошибка')
+  }
+
+  return data
+}
+
+// getRange возвращает снимок на шаге from и изменения (deltas) на шагах from+1..to
+export async function getRange(code, from, to) {
+  const response = await fetch('/api/range', {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    body: JSON.stringify({ code, from, to }),
+  })
+
+  const data = await response.json()
+
+  if (!data.success) {
+    throw new Error(data.error || 'Неизвестная ошибка')
+  }
+
+  return data
+}
+
+// getDiff возвращает различия снимков на шагах from и to: измененные значения,
+// вошедшие и покинутые кадры и области видимости, появившиеся и исчезнувшие объявления
+export async function getDiff(code, from, to) {
+  const response = await fetch('/api/diff', {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    body: JSON.stringify({ code, from, to }),
+  })
+
+  const data = await response.json()
+
+  if (!data.success) {
+    throw new Error(data.error || 'Неизвестная ошибка')
+  }
+
+  return data.diff
+}
+
+// getHistory выполняет запрос к истории значения выражения: query = 'changes' (все изменения на шагах from..to),
+// 'first' (первый шаг, на котором выражение истинно) или 'last_write' (последняя запись не позже шага before)
+export async function getHistory(code, expression, query = 'changes', { from, to, before } = {}) {
+  const response = await fetch('/api/history', {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    body: JSON.stringify({ code, expression, query, from, to, before }),
+  })
+
+  const data = await response.json()
+
+  if (!data.success) {
+    throw new Error(data.error || 'Неизвестная ошибка')
+  }
+
+  return data
+}
@@ -0,0 +1,27 @@
+// Форматирует типизированное значение интерпретатора ({ type, value, char }) для отображения
+export function formatValue(v) {
+  if (v === null || v === undefined) return '?'
+  if (typeof v !== 'object') return v
+  if (v.value === undefined || v.value === null) return '?'
+  if (v.type === 'char') return `'${v.char}' (${v.value})`
+  if (typeof v.type === 'string' && v.type.endsWith('*')) return formatAddress(v.value)
+  return v.value
+}
+
+// Форматирует адрес указателя: NULL или шестнадцатеричный адрес
+export function formatAddress(address) {
+  if (!address) return 'NULL'
+  return '0x' + address.toString(16)
+}
+
+// Форматирует маркер индекса (snapshot.index_markers): имя переменной и смещение, например j+1
+export function formatMarker(marker) {
+  if (!marker.offset) return marker.variable
+  return marker.offset > 0 ? `${marker.variable}+${marker.offset}` : `${marker.variable}${marker.offset}`
+}
+
+// Форматирует значение переменной; для enum-переменной добавляет имя константы перечисления
+export function formatVariable(variable) {
+  if (variable && variable.enumerator && variable.value) return `${variable.enumerator} (${variable.value.value})`
+  return formatValue(variable ? variable.value : undefined)
+}
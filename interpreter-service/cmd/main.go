@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/handler"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
 	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
@@ -51,6 +52,17 @@ func main() {
 	}
 
 	http.Handle("/snapshot", handler.NewSnapshotHandler(cfg, cacher))
+	for _, action := range step.Actions {
+		http.Handle(handler.NavigationPath(action), handler.NewNavigationHandler(cfg, cacher, action))
+	}
+	http.Handle("/breakpoints", handler.NewBreakpointHandler(cfg, cacher))
+	http.Handle("/range", handler.NewRangeHandler(cfg, cacher))
+	http.Handle("/diff", handler.NewDiffHandler(cfg, cacher))
+	http.Handle("/history", handler.NewHistoryHandler(cfg, cacher))
+	http.Handle("/calltree", handler.NewCallTreeHandler(cfg, cacher))
+	http.Handle("/coverage", handler.NewCoverageHandler(cfg, cacher))
+	http.Handle("/profile", handler.NewProfileHandler(cfg, cacher))
+	http.Handle("/complexity", handler.NewComplexityHandler(cfg))
 
 	address := fmt.Sprintf(":%d", listenPort)
 	log.Printf("interpreter-service listening on %s", address)
@@ -0,0 +1,199 @@
+package breakpoints
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/watch"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+// Direction - направление поиска точки останова от текущего шага
+type Direction string
+
+const (
+	DirectionForward  Direction = "forward"
+	DirectionBackward Direction = "backward"
+)
+
+// Breakpoint - точка останова на строке. Если задано условие, остановка происходит,
+// только когда оно истинно на состоянии программы перед выполнением строки
+type Breakpoint struct {
+	Line      int    `json:"line"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// HitKind - вид сработавшей точки останова
+type HitKind string
+
+const (
+	HitBreakpoint HitKind = "breakpoint"
+	HitWatchpoint HitKind = "watchpoint"
+)
+
+// Hit - сработавшая точка останова. Для точки наблюдения заданы выражение и значения до и после изменения
+type Hit struct {
+	Kind       HitKind        `json:"kind"`
+	Breakpoint *Breakpoint    `json:"breakpoint,omitempty"`
+	Watch      string         `json:"watch,omitempty"`
+	OldValue   *runtime.Value `json:"old_value,omitempty"`
+	NewValue   *runtime.Value `json:"new_value,omitempty"`
+}
+
+type breakpoint struct {
+	Breakpoint
+	condition converter.Expr
+}
+
+type watchpoint struct {
+	source string
+	target converter.Expr
+}
+
+// watchState - результат вычисления наблюдаемого объекта на шаге
+type watchState struct {
+	value       runtime.Value
+	stepChanged int
+	ok          bool
+}
+
+// Searcher ищет шаги, на которых срабатывают точки останова и точки наблюдения
+type Searcher struct {
+	breakpoints []breakpoint
+	watchpoints []watchpoint
+}
+
+// NewSearcher разбирает условия точек останова и наблюдаемые выражения (переменные, элементы массивов)
+func NewSearcher(breakpoints []Breakpoint, watchpoints []string) (*Searcher, error) {
+	s := &Searcher{}
+	for _, bp := range breakpoints {
+		if bp.Line <= 0 {
+			return nil, fmt.Errorf("breakpoint line must be positive, got %d", bp.Line)
+		}
+		compiled := breakpoint{Breakpoint: bp}
+		if bp.Condition != "" {
+			condition, err := watch.ParseExpression(bp.Condition)
+			if err != nil {
+				return nil, fmt.Errorf("breakpoint at line %d: %w", bp.Line, err)
+			}
+			compiled.condition = condition
+		}
+		s.breakpoints = append(s.breakpoints, compiled)
+	}
+
+	for _, source := range watchpoints {
+		target, err := watch.ParseExpression(source)
+		if err != nil {
+			return nil, fmt.Errorf("watchpoint %s: %w", source, err)
+		}
+		if !watch.IsLocation(target) {
+			return nil, fmt.Errorf("watchpoint %s: expected a variable or an array element", source)
+		}
+		s.watchpoints = append(s.watchpoints, watchpoint{source: source, target: target})
+	}
+
+	return s, nil
+}
+
+// Find возвращает индекс ближайшего к from шага (нумерация от начала main) в направлении direction,
+// на котором срабатывает точка останова, и описание сработавшей точки.
+// Если таких шагов нет, поиск вперед останавливается на последнем шаге, а назад - на первом, hit равен nil
+func (s *Searcher) Find(steps []step.Step, stepBegin, from int, direction Direction) (int, *Hit, error) {
+	count := len(steps) - stepBegin
+	if from < 0 || from >= count {
+		return 0, nil, fmt.Errorf("invalid step index: %d (total steps: %d)", from, max(count, 0))
+	}
+	if direction != DirectionForward && direction != DirectionBackward {
+		return 0, nil, fmt.Errorf("unknown direction: %s", direction)
+	}
+
+	ed := eventdispatcher.NewEventDispatcher(stepBegin)
+	ed.Steps = steps
+	states := make([]watchState, len(s.watchpoints))
+
+	target, found := 0, (*Hit)(nil)
+	if direction == DirectionForward {
+		target = count - 1
+	}
+	for ind := 0; ind < count; ind++ {
+		if direction == DirectionBackward && ind >= from {
+			break
+		}
+		if err := ed.ApplyStep(ind); err != nil {
+			return 0, nil, err
+		}
+
+		hit := s.check(ed.GetSnapshot(), steps[stepBegin+ind], ind, states)
+		if hit == nil {
+			continue
+		}
+		if direction == DirectionForward && ind > from {
+			return ind, hit, nil
+		}
+		if direction == DirectionBackward {
+			target, found = ind, hit
+		}
+	}
+
+	return target, found, nil
+}
+
+// check возвращает точку, сработавшую на шаге ind, и обновляет состояния наблюдаемых объектов.
+// Точки наблюдения проверяются все, чтобы states отражали состояние на шаге ind
+func (s *Searcher) check(sn *snapshot.Snapshot, st step.Step, ind int, states []watchState) *Hit {
+	var hit *Hit
+	for k, wp := range s.watchpoints {
+		prev := states[k]
+		value, stepChanged, err := interpreter.InspectLocation(sn, wp.target)
+		states[k] = watchState{value: value, stepChanged: stepChanged, ok: err == nil}
+
+		// объект изменен на этом шаге, и его значение отличается от прежнего
+		if hit != nil || err != nil || stepChanged != ind || (prev.ok && prev.value == value) {
+			continue
+		}
+		hit = &Hit{Kind: HitWatchpoint, Watch: wp.source, NewValue: &value}
+		if prev.ok {
+			hit.OldValue = &prev.value
+		}
+	}
+	if hit != nil {
+		return hit
+	}
+
+	if !stopsAtLine(st) {
+		return nil
+	}
+	for _, bp := range s.breakpoints {
+		if bp.Line != st.Line {
+			continue
+		}
+		if bp.condition != nil {
+			value, err := interpreter.Inspect(sn, bp.condition)
+			if err != nil {
+				// условие, которое нельзя вычислить (например, переменная еще не инициализирована), ложно
+				continue
+			}
+			if truth, err := value.Truth(); err != nil || !truth {
+				continue
+			}
+		}
+		return &Hit{Kind: HitBreakpoint, Breakpoint: &bp.Breakpoint}
+	}
+
+	return nil
+}
+
+// stopsAtLine сообщает, начинается ли на шаге выполнение конструкции строки.
+// Шаги подвыражений и возврата из вызова продолжают уже начатую конструкцию
+func stopsAtLine(st step.Step) bool {
+	switch st.Kind {
+	case step.KindSubexpression, step.KindCallReturn, step.KindProgramEnd, step.KindError:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,146 @@
+package breakpoints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+const loopCode = `int main() {
+	int a[5];
+	int sum = 0;
+	for (int i = 0; i < 5; i++) {
+		a[i] = i * i;
+		sum = sum + a[i];
+	}
+	return sum;
+}`
+
+func run(t *testing.T, code string) ([]step.Step, int) {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	_, steps, stepBegin, err := interpreter.NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+	return steps, stepBegin
+}
+
+func mustSearcher(t *testing.T, watchpoints []string) *Searcher {
+	t.Helper()
+
+	s, err := NewSearcher(nil, watchpoints)
+	require.NoError(t, err)
+	return s
+}
+
+func TestSearcher_LineBreakpoint(t *testing.T) {
+	steps, stepBegin := run(t, loopCode)
+	s, err := NewSearcher([]Breakpoint{{Line: 6}}, nil)
+	require.NoError(t, err)
+
+	first, hit, err := s.Find(steps, stepBegin, 0, DirectionForward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.Equal(t, HitBreakpoint, hit.Kind)
+	assert.Equal(t, 6, steps[stepBegin+first].Line)
+
+	second, _, err := s.Find(steps, stepBegin, first, DirectionForward)
+	require.NoError(t, err)
+	assert.Greater(t, second, first)
+	assert.Equal(t, 6, steps[stepBegin+second].Line)
+
+	back, hit, err := s.Find(steps, stepBegin, second, DirectionBackward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.Equal(t, first, back)
+}
+
+func TestSearcher_ConditionalBreakpoint(t *testing.T) {
+	steps, stepBegin := run(t, loopCode)
+	s, err := NewSearcher([]Breakpoint{{Line: 6, Condition: "i == 3 && a[i] > 8"}}, nil)
+	require.NoError(t, err)
+
+	ind, hit, err := s.Find(steps, stepBegin, 0, DirectionForward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.Equal(t, "i == 3 && a[i] > 8", hit.Breakpoint.Condition)
+
+	// на строке 6 четвертой итерации sum еще не включает a[3]
+	_, hit, err = mustSearcher(t, []string{"sum"}).Find(steps, stepBegin, ind, DirectionForward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	require.NotNil(t, hit.OldValue)
+	assert.Equal(t, 5, hit.OldValue.Int)
+	assert.Equal(t, 14, hit.NewValue.Int)
+}
+
+func TestSearcher_Watchpoints(t *testing.T) {
+	steps, stepBegin := run(t, loopCode)
+
+	// объявление с инициализатором - первое изменение sum
+	ind, hit, err := mustSearcher(t, []string{"sum"}).Find(steps, stepBegin, 0, DirectionForward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.Equal(t, HitWatchpoint, hit.Kind)
+	assert.Nil(t, hit.OldValue)
+	assert.Equal(t, 0, hit.NewValue.Int)
+
+	// sum = sum + a[0] не меняет значение 0 и не считается изменением
+	ind, hit, err = mustSearcher(t, []string{"sum"}).Find(steps, stepBegin, ind, DirectionForward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.Equal(t, 0, hit.OldValue.Int)
+	assert.Equal(t, 1, hit.NewValue.Int)
+
+	_, hit, err = mustSearcher(t, []string{"a[3]"}).Find(steps, stepBegin, 0, DirectionForward)
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.Equal(t, "a[3]", hit.Watch)
+	assert.Nil(t, hit.OldValue)
+	assert.Equal(t, 9, hit.NewValue.Int)
+}
+
+func TestSearcher_NoHit(t *testing.T) {
+	steps, stepBegin := run(t, loopCode)
+	s, err := NewSearcher([]Breakpoint{{Line: 6, Condition: "sum > 100"}}, nil)
+	require.NoError(t, err)
+
+	ind, hit, err := s.Find(steps, stepBegin, 3, DirectionForward)
+	require.NoError(t, err)
+	assert.Nil(t, hit)
+	assert.Equal(t, len(steps)-stepBegin-1, ind)
+
+	ind, hit, err = s.Find(steps, stepBegin, 3, DirectionBackward)
+	require.NoError(t, err)
+	assert.Nil(t, hit)
+	assert.Equal(t, 0, ind)
+
+	_, _, err = s.Find(steps, stepBegin, len(steps), DirectionForward)
+	assert.Error(t, err)
+}
+
+func TestNewSearcher_Errors(t *testing.T) {
+	_, err := NewSearcher([]Breakpoint{{Line: 0}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewSearcher([]Breakpoint{{Line: 3, Condition: "sum = 5"}}, nil)
+	assert.ErrorContains(t, err, "assignment is not allowed")
+
+	_, err = NewSearcher([]Breakpoint{{Line: 3, Condition: "i++ > 2"}}, nil)
+	assert.ErrorContains(t, err, "operator ++ is not allowed")
+
+	_, err = NewSearcher([]Breakpoint{{Line: 3, Condition: "f(1)"}}, nil)
+	assert.ErrorContains(t, err, "call of f is not allowed")
+
+	_, err = NewSearcher(nil, []string{"sum + 1"})
+	assert.ErrorContains(t, err, "expected a variable or an array element")
+
+	_, err = NewSearcher(nil, []string{"x); return (1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package calltree
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+// Node - вызов функции в дереве вызовов. StepFrom - первый шаг внутри вызова, StepTo - шаг,
+// на котором управление вернулось в вызывающую функцию (нумерация от начала main).
+// Если выполнение прервано до возврата, Returned ложно, а StepTo - последний шаг трассы
+type Node struct {
+	ID          int                 `json:"id"` // порядковый номер вызова, 0 - main
+	Function    string              `json:"function"`
+	Loc         *converter.Location `json:"loc,omitempty"` // место вызова (nil для main)
+	Arguments   []events.Argument   `json:"arguments,omitempty"`
+	ReturnValue *runtime.Value      `json:"return_value,omitempty"`
+	Returned    bool                `json:"returned"`
+	Depth       int                 `json:"depth"` // 0 для main
+	StepFrom    int                 `json:"step_from"`
+	StepTo      int                 `json:"step_to"`
+	Children    []*Node             `json:"children,omitempty"`
+}
+
+// Build строит дерево вызовов трассы по событиям FunctionCall и FunctionReturn. Корень - вызов main
+func Build(steps []step.Step, stepBegin int) (*Node, int, error) {
+	var root *Node
+	var stack []*Node
+	count := 0
+	for ind := stepBegin; ind < len(steps); ind++ {
+		for _, event := range steps[ind].Events {
+			switch e := event.(type) {
+			case events.FunctionCall:
+				node := &Node{ID: count, Function: e.Name, Loc: e.Loc, Arguments: e.Arguments, Depth: len(stack), StepFrom: ind - stepBegin}
+				count++
+				if len(stack) == 0 {
+					if root != nil {
+						return nil, 0, fmt.Errorf("second top-level call %s at step %d", e.Name, ind-stepBegin)
+					}
+					root = node
+				} else {
+					parent := stack[len(stack)-1]
+					parent.Children = append(parent.Children, node)
+				}
+				stack = append(stack, node)
+			case events.FunctionReturn:
+				if len(stack) == 0 {
+					return nil, 0, fmt.Errorf("return from %s without a call at step %d", e.Name, ind-stepBegin)
+				}
+				node := stack[len(stack)-1]
+				node.ReturnValue, node.Returned, node.StepTo = e.ReturnValue, true, ind-stepBegin
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	// вызовы, прерванные ошибкой или exit, продолжаются до последнего шага
+	for _, node := range stack {
+		node.StepTo = len(steps) - 1 - stepBegin
+	}
+	return root, count, nil
+}
@@ -0,0 +1,102 @@
+package calltree
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+func run(t *testing.T, code string) ([]step.Step, int) {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	_, steps, stepBegin, _ := interpreter.NewInterpreter().ExecuteProgram(program)
+	return steps, stepBegin
+}
+
+// format записывает дерево в виде fib(n=3)=2 [ ... ]
+func format(node *Node) string {
+	var args []string
+	for _, arg := range node.Arguments {
+		args = append(args, fmt.Sprintf("%s=%d", arg.Name, arg.Value.Int))
+	}
+	s := fmt.Sprintf("%s(%s)", node.Function, strings.Join(args, ","))
+	if node.ReturnValue != nil {
+		s += fmt.Sprintf("=%d", node.ReturnValue.Int)
+	}
+	if len(node.Children) > 0 {
+		var children []string
+		for _, child := range node.Children {
+			children = append(children, format(child))
+		}
+		s += " [" + strings.Join(children, " ") + "]"
+	}
+	return s
+}
+
+func TestBuild_Fibonacci(t *testing.T) {
+	steps, stepBegin := run(t, `int g = 1;
+int fib(int n) {
+	if (n < 2) {
+		return n;
+	}
+	return fib(n - 1) + fib(n - 2);
+}
+int main() {
+	int r = fib(3);
+	return r;
+}`)
+
+	root, count, err := Build(steps, stepBegin)
+	require.NoError(t, err)
+	require.NotNil(t, root)
+
+	assert.Equal(t, "main()=2 [fib(n=3)=2 [fib(n=2)=1 [fib(n=1)=1 fib(n=0)=0] fib(n=1)=1]]", format(root))
+	assert.Equal(t, 6, count)
+
+	assert.Equal(t, 0, root.StepFrom)
+	assert.Equal(t, len(steps)-1-stepBegin, root.StepTo)
+	assert.Nil(t, root.Loc)
+
+	call := root.Children[0]
+	require.NotNil(t, call.Loc)
+	assert.Equal(t, uint32(9), call.Loc.Line)
+	assert.Equal(t, 1, call.Depth)
+	assert.Equal(t, 2, call.Children[1].Depth)
+	assert.Equal(t, 3, call.Children[0].Children[0].Depth)
+	assert.True(t, call.Returned)
+
+	// шаги вложенных вызовов лежат внутри шагов родителя, соседние вызовы не пересекаются
+	left, right := call.Children[0], call.Children[1]
+	assert.Less(t, call.StepFrom, left.StepFrom)
+	assert.LessOrEqual(t, left.StepTo, right.StepFrom)
+	assert.Less(t, right.StepTo, call.StepTo)
+	assert.Equal(t, 9, steps[stepBegin+call.StepTo].Line)
+}
+
+func TestBuild_InterruptedCall(t *testing.T) {
+	steps, stepBegin := run(t, `int div(int a, int b) {
+	return a / b;
+}
+int main() {
+	return div(4, 0);
+}`)
+
+	root, _, err := Build(steps, stepBegin)
+	require.NoError(t, err)
+
+	assert.Equal(t, "main() [div(a=4,b=0)]", format(root))
+	last := len(steps) - 1 - stepBegin
+	for _, node := range []*Node{root, root.Children[0]} {
+		assert.False(t, node.Returned)
+		assert.Equal(t, last, node.StepTo)
+	}
+}
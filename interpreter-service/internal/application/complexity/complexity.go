@@ -0,0 +1,226 @@
+package complexity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+)
+
+// Placeholder - место в коде программы, куда подставляется размер входных данных
+const Placeholder = "{{n}}"
+
+// Class - класс роста числа операций в зависимости от размера n
+type Class string
+
+const (
+	ClassConstant     Class = "1"
+	ClassLogarithmic  Class = "log n"
+	ClassLinear       Class = "n"
+	ClassLinearithmic Class = "n log n"
+	ClassQuadratic    Class = "n^2"
+	ClassExponential  Class = "2^n"
+)
+
+// classes - классы роста от простого к сложному; при равном качестве приближения выбирается более простой
+var classes = []struct {
+	class Class
+	f     func(n float64) float64
+}{
+	{ClassConstant, func(float64) float64 { return 1 }},
+	{ClassLogarithmic, math.Log2},
+	{ClassLinear, func(n float64) float64 { return n }},
+	{ClassLinearithmic, func(n float64) float64 { return n * math.Log2(n) }},
+	{ClassQuadratic, func(n float64) float64 { return n * n }},
+	{ClassExponential, math.Exp2},
+}
+
+// Metric - измеряемая величина выполнения
+type Metric string
+
+const (
+	MetricTotal         Metric = "total"
+	MetricComparisons   Metric = "comparisons"
+	MetricAssignments   Metric = "assignments"
+	MetricArrayAccesses Metric = "array_accesses"
+	MetricCalls         Metric = "calls"
+	MetricSteps         Metric = "steps"
+)
+
+// ParseMetric проверяет имя метрики, пустое имя означает MetricTotal
+func ParseMetric(name string) (Metric, error) {
+	switch m := Metric(name); m {
+	case "":
+		return MetricTotal, nil
+	case MetricTotal, MetricComparisons, MetricAssignments, MetricArrayAccesses, MetricCalls, MetricSteps:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown metric: %s", name)
+	}
+}
+
+// Run - выполнение программы для размера N: число операций и шагов.
+// Truncated - выполнение прервано лимитом, числа операций неполные и в приближении не участвуют
+type Run struct {
+	N          int                         `json:"n"`
+	Operations interpreter.OperationCounts `json:"operations"`
+	Total      int                         `json:"total"`
+	Steps      int                         `json:"steps"`
+	Truncated  bool                        `json:"truncated,omitempty"`
+}
+
+// Value возвращает значение метрики m выполнения
+func (r Run) Value(m Metric) int {
+	switch m {
+	case MetricComparisons:
+		return r.Operations.Comparisons
+	case MetricAssignments:
+		return r.Operations.Assignments
+	case MetricArrayAccesses:
+		return r.Operations.ArrayAccesses
+	case MetricCalls:
+		return r.Operations.Calls
+	case MetricSteps:
+		return r.Steps
+	default:
+		return r.Total
+	}
+}
+
+// Fit - приближение метрики функцией A*f(n) + B класса Class методом наименьших квадратов.
+// R2 - коэффициент детерминации: 1 - точное совпадение
+type Fit struct {
+	Class Class   `json:"class"`
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	R2    float64 `json:"r2"`
+}
+
+// Substitute подставляет размер n вместо всех вхождений Placeholder в код программы
+func Substitute(code string, n int) string {
+	return strings.ReplaceAll(code, Placeholder, strconv.Itoa(n))
+}
+
+// ValidateSizes проверяет размеры входных данных: не меньше трех различных положительных значений
+func ValidateSizes(sizes []int) error {
+	if len(sizes) < 3 {
+		return errors.New("at least 3 sizes are required")
+	}
+	for ind, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("size must be positive, got %d", n)
+		}
+		if slices.Contains(sizes[:ind], n) {
+			return fmt.Errorf("duplicate size: %d", n)
+		}
+	}
+	return nil
+}
+
+// ValidateBounds проверяет размеры по лимитам сервиса: не больше maxSizes размеров, каждый не больше maxN
+func ValidateBounds(sizes []int, maxSizes, maxN int) error {
+	if len(sizes) > maxSizes {
+		return fmt.Errorf("too many sizes: %d, at most %d are allowed", len(sizes), maxSizes)
+	}
+	for _, n := range sizes {
+		if n > maxN {
+			return fmt.Errorf("size %d exceeds the limit %d", n, maxN)
+		}
+	}
+	return nil
+}
+
+// Estimate приближает метрику m завершенных выполнений runs каждым классом роста и возвращает приближения
+// и лучший класс: с наибольшим R2 среди классов с неотрицательным коэффициентом A
+func Estimate(runs []Run, m Metric) ([]Fit, Class, error) {
+	complete := make([]Run, 0, len(runs))
+	for _, r := range runs {
+		if !r.Truncated {
+			complete = append(complete, r)
+		}
+	}
+	if truncated := len(runs) - len(complete); truncated > 0 && len(complete) < 3 {
+		return nil, "", fmt.Errorf("at least 3 complete runs are required, %d of %d runs hit the limits", truncated, len(runs))
+	}
+	runs = complete
+
+	sizes := make([]int, len(runs))
+	for ind, r := range runs {
+		sizes[ind] = r.N
+	}
+	if err := ValidateSizes(sizes); err != nil {
+		return nil, "", err
+	}
+
+	ys := make([]float64, len(runs))
+	for ind, r := range runs {
+		ys[ind] = float64(r.Value(m))
+	}
+
+	var fits []Fit
+	best := -1
+	for _, c := range classes {
+		xs := make([]float64, len(runs))
+		finite := true
+		for ind, r := range runs {
+			xs[ind] = c.f(float64(r.N))
+			finite = finite && !math.IsInf(xs[ind], 0)
+		}
+		if !finite {
+			continue
+		}
+
+		fit := leastSquares(xs, ys)
+		fit.Class = c.class
+		fits = append(fits, fit)
+		if c.class != ClassConstant && fit.A < 0 {
+			continue
+		}
+		// точность сравнения отбрасывает ошибки округления, чтобы при точном приближении выбирался простой класс
+		if best == -1 || fit.R2 > fits[best].R2+1e-9 {
+			best = len(fits) - 1
+		}
+	}
+	return fits, fits[best].Class, nil
+}
+
+// leastSquares приближает ys функцией A*xs + B. Если все xs равны (класс 1), A = 0, B - среднее ys
+func leastSquares(xs, ys []float64) Fit {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for ind := range xs {
+		sumX += xs[ind]
+		sumY += ys[ind]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy, tss float64
+	for ind := range xs {
+		sxx += (xs[ind] - meanX) * (xs[ind] - meanX)
+		sxy += (xs[ind] - meanX) * (ys[ind] - meanY)
+		tss += (ys[ind] - meanY) * (ys[ind] - meanY)
+	}
+
+	fit := Fit{B: meanY}
+	if sxx > 0 {
+		fit.A = sxy / sxx
+		fit.B = meanY - fit.A*meanX
+	}
+
+	var rss float64
+	for ind := range xs {
+		d := ys[ind] - (fit.A*xs[ind] + fit.B)
+		rss += d * d
+	}
+	switch {
+	case tss > 0:
+		fit.R2 = 1 - rss/tss
+	case rss == 0:
+		fit.R2 = 1
+	}
+	return fit
+}
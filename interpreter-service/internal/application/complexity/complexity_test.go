@@ -0,0 +1,152 @@
+package complexity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+)
+
+func measure(t *testing.T, code string, sizes ...int) []Run {
+	t.Helper()
+
+	runs := make([]Run, len(sizes))
+	for ind, n := range sizes {
+		program, convErr := converter.New().ParseToAST(Substitute(code, n))
+		require.Nil(t, convErr)
+		runner := interpreter.NewInterpreterWithLimits(100000, 100000)
+		_, steps, stepBegin, err := runner.ExecuteProgram(program)
+		require.NoError(t, err)
+		ops := runner.Operations()
+		runs[ind] = Run{N: n, Operations: ops, Total: ops.Total(), Steps: len(steps) - stepBegin}
+	}
+	return runs
+}
+
+func TestEstimate_BubbleSort(t *testing.T) {
+	runs := measure(t, `int a[{{n}}];
+int main() {
+	int n = {{n}};
+	for (int i = 0; i < n; i++) {
+		a[i] = n - i;
+	}
+	for (int i = 0; i < n - 1; i++) {
+		for (int j = 0; j < n - 1 - i; j++) {
+			if (a[j] > a[j + 1]) {
+				int t = a[j];
+				a[j] = a[j + 1];
+				a[j + 1] = t;
+			}
+		}
+	}
+	return a[0];
+}`, 4, 8, 12, 16, 20)
+
+	// a[j] > a[j + 1]: n(n-1)/2; условия циклов: n + 1 при заполнении, n во внешнем и n(n+1)/2 - 1 во внутреннем
+	for _, r := range runs {
+		assert.Equal(t, r.N*r.N+2*r.N, r.Operations.Comparisons)
+	}
+
+	fits, best, err := Estimate(runs, MetricComparisons)
+	require.NoError(t, err)
+	assert.Equal(t, ClassQuadratic, best)
+	require.Len(t, fits, 6)
+	assert.Equal(t, ClassQuadratic, fits[4].Class)
+	assert.Greater(t, fits[4].R2, fits[3].R2)
+	assert.Greater(t, fits[4].R2, 0.999)
+
+	_, best, err = Estimate(runs, MetricTotal)
+	require.NoError(t, err)
+	assert.Equal(t, ClassQuadratic, best)
+}
+
+func TestEstimate_BinarySearch(t *testing.T) {
+	runs := measure(t, `int main() {
+	int lo = 0;
+	int hi = {{n}};
+	while (hi - lo > 1) {
+		int mid = (lo + hi) / 2;
+		if (mid <= 0) {
+			lo = mid;
+		} else {
+			hi = mid;
+		}
+	}
+	return lo;
+}`, 2, 8, 32, 128, 512, 2048)
+
+	_, best, err := Estimate(runs, MetricSteps)
+	require.NoError(t, err)
+	assert.Equal(t, ClassLogarithmic, best)
+}
+
+func TestEstimate_RecursionCalls(t *testing.T) {
+	runs := measure(t, `int count(int n) {
+	if (n == 0) {
+		return 1;
+	}
+	return count(n - 1) + count(n - 1);
+}
+int main() {
+	return count({{n}});
+}`, 2, 4, 6, 8)
+
+	_, best, err := Estimate(runs, MetricCalls)
+	require.NoError(t, err)
+	assert.Equal(t, ClassExponential, best)
+}
+
+func TestEstimate_ConstantAndLinear(t *testing.T) {
+	constant := []Run{{N: 1, Total: 5}, {N: 10, Total: 5}, {N: 100, Total: 5}}
+	fits, best, err := Estimate(constant, MetricTotal)
+	require.NoError(t, err)
+	assert.Equal(t, ClassConstant, best)
+	assert.Equal(t, 5.0, fits[0].B)
+
+	linear := []Run{{N: 1, Total: 7}, {N: 10, Total: 34}, {N: 100, Total: 304}}
+	_, best, err = Estimate(linear, MetricTotal)
+	require.NoError(t, err)
+	assert.Equal(t, ClassLinear, best)
+
+	// 2^2000 не представимо, экспоненциальный класс пропускается
+	fits, _, err = Estimate([]Run{{N: 10}, {N: 1000}, {N: 2000}}, MetricTotal)
+	require.NoError(t, err)
+	for _, fit := range fits {
+		assert.NotEqual(t, ClassExponential, fit.Class)
+		assert.False(t, math.IsNaN(fit.R2))
+	}
+}
+
+func TestEstimate_SkipsTruncatedRuns(t *testing.T) {
+	runs := []Run{{N: 1, Total: 7}, {N: 10, Total: 34}, {N: 100, Total: 304}, {N: 1000, Total: 500, Truncated: true}}
+	fits, best, err := Estimate(runs, MetricTotal)
+	require.NoError(t, err)
+	assert.Equal(t, ClassLinear, best)
+	assert.InDelta(t, 1.0, fits[2].R2, 1e-9)
+
+	runs[2].Truncated = true
+	_, _, err = Estimate(runs, MetricTotal)
+	assert.ErrorContains(t, err, "2 of 4 runs hit the limits")
+}
+
+func TestValidation(t *testing.T) {
+	assert.Error(t, ValidateSizes([]int{1, 2}))
+	assert.Error(t, ValidateSizes([]int{1, 2, 0}))
+	assert.ErrorContains(t, ValidateSizes([]int{1, 2, 2}), "duplicate")
+	assert.NoError(t, ValidateSizes([]int{3, 1, 2}))
+	assert.ErrorContains(t, ValidateBounds([]int{1, 2, 3, 4}, 3, 10), "too many sizes: 4, at most 3 are allowed")
+	assert.ErrorContains(t, ValidateBounds([]int{1, 2, 11}, 3, 10), "size 11 exceeds the limit 10")
+	assert.NoError(t, ValidateBounds([]int{1, 2, 10}, 3, 10))
+
+	_, err := ParseMetric("swaps")
+	assert.Error(t, err)
+	m, err := ParseMetric("")
+	require.NoError(t, err)
+	assert.Equal(t, MetricTotal, m)
+
+	assert.Equal(t, "int a[7];", Substitute("int a[{{n}}];", 7))
+}
@@ -0,0 +1,215 @@
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+// Line - строка с инструкцией и число переходов выполнения к инструкциям этой строки
+type Line struct {
+	Line int `json:"line"`
+	Hits int `json:"hits"`
+}
+
+// Branch - условие if или цикла и число его истинных и ложных исходов.
+// Для цикла истинный исход - очередная итерация, ложный - выход из цикла
+type Branch struct {
+	Construct events.BranchConstruct `json:"construct"`
+	Loc       converter.Location     `json:"loc"`
+	True      int                    `json:"true"`
+	False     int                    `json:"false"`
+}
+
+// Function - функция программы и число ее вызовов
+type Function struct {
+	Name  string `json:"name"`
+	Line  int    `json:"line"`
+	Calls int    `json:"calls"`
+}
+
+// Report - покрытие кода одним запуском программы
+type Report struct {
+	Lines         []Line     `json:"lines"`
+	Uncovered     []int      `json:"uncovered,omitempty"` // строки, инструкции которых не выполнялись
+	Branches      []Branch   `json:"branches,omitempty"`
+	Functions     []Function `json:"functions"`
+	LinesHit      int        `json:"lines_hit"`
+	BranchesFound int        `json:"branches_found"` // исходов условий: по два на условие
+	BranchesHit   int        `json:"branches_hit"`
+}
+
+// Build строит отчет о покрытии по трассе steps программы program. Строки и условия берутся из AST,
+// попадания - из событий LineChanged шагов на инструкциях, исходы условий - из событий BranchEvaluated
+func Build(program *converter.Program, steps []step.Step) (*Report, error) {
+	if program == nil {
+		return nil, errors.New("program is nil")
+	}
+
+	c := collector{hits: map[int]int{}, branches: map[converter.Location]int{}}
+	for _, decl := range program.Declarations {
+		c.collectStmt(decl)
+	}
+
+	calls := map[string]int{}
+	for _, st := range steps {
+		statement := isStatementStep(st.Kind)
+		for _, event := range st.Events {
+			switch e := event.(type) {
+			case events.LineChanged:
+				if statement {
+					c.hits[e.Line]++
+				}
+			case events.BranchEvaluated:
+				ind, ok := c.branches[e.Loc]
+				if !ok {
+					return nil, fmt.Errorf("condition at line %d is not in the program", e.Loc.Line)
+				}
+				if e.Taken {
+					c.report.Branches[ind].True++
+				} else {
+					c.report.Branches[ind].False++
+				}
+			case events.FunctionCall:
+				calls[e.Name]++
+			}
+		}
+	}
+
+	report := &c.report
+	lines := make([]int, 0, len(c.hits))
+	for line := range c.hits {
+		lines = append(lines, line)
+	}
+	slices.Sort(lines)
+	for _, line := range lines {
+		hits := c.hits[line]
+		report.Lines = append(report.Lines, Line{Line: line, Hits: hits})
+		if hits > 0 {
+			report.LinesHit++
+		} else {
+			report.Uncovered = append(report.Uncovered, line)
+		}
+	}
+	for _, branch := range report.Branches {
+		report.BranchesFound += 2
+		report.BranchesHit += min(branch.True, 1) + min(branch.False, 1)
+	}
+	for ind := range report.Functions {
+		report.Functions[ind].Calls = calls[report.Functions[ind].Name]
+	}
+	return report, nil
+}
+
+// isStatementStep сообщает, начинает ли шаг вида kind выполнение инструкции. Возврат в строку вызова,
+// подвыражения и завершение программы не считаются повторным выполнением строки
+func isStatementStep(kind step.Kind) bool {
+	switch kind {
+	case step.KindDeclaration, step.KindExpression, step.KindCondition, step.KindReturn, step.KindBreak, step.KindContinue:
+		return true
+	}
+	return false
+}
+
+// collector собирает из AST строки инструкций, условия и функции программы
+type collector struct {
+	report   Report
+	hits     map[int]int                // строка инструкции -> число попаданий
+	branches map[converter.Location]int // участок условия -> индекс в report.Branches
+}
+
+func (c *collector) line(loc converter.Location) {
+	if _, ok := c.hits[int(loc.Line)]; !ok {
+		c.hits[int(loc.Line)] = 0
+	}
+}
+
+func (c *collector) branch(construct events.BranchConstruct, cond converter.Expr) {
+	loc := cond.GetLocation()
+	c.line(loc)
+	c.branches[loc] = len(c.report.Branches)
+	c.report.Branches = append(c.report.Branches, Branch{Construct: construct, Loc: loc})
+}
+
+func (c *collector) collectStmt(stmt converter.Stmt) {
+	switch s := stmt.(type) {
+	case *converter.FunctionDecl:
+		if s.Body == nil {
+			return
+		}
+		c.report.Functions = append(c.report.Functions, Function{Name: s.Name, Line: int(s.Loc.Line)})
+		c.collectStmt(s.Body)
+	case *converter.BlockStmt:
+		for _, inner := range s.Statements {
+			c.collectStmt(inner)
+		}
+	case *converter.VariableDecl, *converter.ExprStmt, *converter.ReturnStmt, *converter.BreakStmt, *converter.ContinueStmt:
+		c.line(s.GetLocation())
+	case *converter.IfStmt:
+		c.branch(events.BranchIf, s.Condition)
+		c.collectStmt(s.ThenBlock)
+		if s.ElseBlock != nil {
+			c.collectStmt(s.ElseBlock)
+		}
+	case *converter.WhileStmt:
+		c.branch(events.BranchWhile, s.Condition)
+		c.collectStmt(s.Body)
+	case *converter.DoWhileStmt:
+		c.collectStmt(s.Body)
+		c.branch(events.BranchDoWhile, s.Condition)
+	case *converter.ForStmt:
+		if s.Init != nil {
+			c.collectStmt(s.Init)
+		}
+		if s.Condition != nil {
+			c.branch(events.BranchFor, s.Condition)
+		}
+		if s.Post != nil {
+			c.collectStmt(s.Post)
+		}
+		c.collectStmt(s.Body)
+	}
+}
+
+// LCOV записывает отчет в формате LCOV (tracefile) для файла source. Каждое условие - блок
+// из двух ветвей: 0 - истинный исход, 1 - ложный; "-" означает, что условие не вычислялось
+func (r *Report) LCOV(source string) string {
+	var b strings.Builder
+	b.WriteString("TN:\n")
+	fmt.Fprintf(&b, "SF:%s\n", source)
+
+	called := 0
+	for _, fn := range r.Functions {
+		fmt.Fprintf(&b, "FN:%d,%s\n", fn.Line, fn.Name)
+	}
+	for _, fn := range r.Functions {
+		fmt.Fprintf(&b, "FNDA:%d,%s\n", fn.Calls, fn.Name)
+		if fn.Calls > 0 {
+			called++
+		}
+	}
+	fmt.Fprintf(&b, "FNF:%d\nFNH:%d\n", len(r.Functions), called)
+
+	for ind, branch := range r.Branches {
+		for outcome, taken := range []int{branch.True, branch.False} {
+			count := "-"
+			if branch.True+branch.False > 0 {
+				count = fmt.Sprint(taken)
+			}
+			fmt.Fprintf(&b, "BRDA:%d,%d,%d,%s\n", branch.Loc.Line, ind, outcome, count)
+		}
+	}
+	fmt.Fprintf(&b, "BRF:%d\nBRH:%d\n", r.BranchesFound, r.BranchesHit)
+
+	for _, line := range r.Lines {
+		fmt.Fprintf(&b, "DA:%d,%d\n", line.Line, line.Hits)
+	}
+	fmt.Fprintf(&b, "LF:%d\nLH:%d\n", len(r.Lines), r.LinesHit)
+	b.WriteString("end_of_record\n")
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+)
+
+const coverageCode = `int sign(int x) {
+	if (x > 0) {
+		return 1;
+	} else if (x < 0) {
+		return -1;
+	}
+	return 0;
+}
+int unused() {
+	return 7;
+}
+int main() {
+	int s = 0;
+	for (int i = 1; i <= 3; i++) {
+		s += sign(i);
+	}
+	while (s > 10) {
+		s--;
+	}
+	return s;
+}`
+
+func build(t *testing.T, code string) *Report {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	runner := interpreter.NewInterpreter()
+	runner.SetBranchEvents(true)
+	_, steps, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+
+	report, err := Build(program, steps)
+	require.NoError(t, err)
+	return report
+}
+
+func hitsOf(report *Report) map[int]int {
+	hits := map[int]int{}
+	for _, line := range report.Lines {
+		hits[line.Line] = line.Hits
+	}
+	return hits
+}
+
+func TestBuild_LinesAndFunctions(t *testing.T) {
+	report := build(t, coverageCode)
+	hits := hitsOf(report)
+
+	assert.Equal(t, 3, hits[2])
+	assert.Equal(t, 3, hits[3])
+	// ветвь else if и return 0 не достигаются, тело while не выполняется
+	assert.Equal(t, []int{4, 5, 7, 10, 18}, report.Uncovered)
+	assert.Equal(t, 3, hits[15])
+	assert.Equal(t, 1, hits[17])
+	assert.Equal(t, len(report.Lines)-len(report.Uncovered), report.LinesHit)
+
+	assert.Equal(t, []Function{
+		{Name: "sign", Line: 1, Calls: 3},
+		{Name: "unused", Line: 9, Calls: 0},
+		{Name: "main", Line: 12, Calls: 1},
+	}, report.Functions)
+}
+
+func TestBuild_Branches(t *testing.T) {
+	report := build(t, coverageCode)
+
+	type outcome struct {
+		construct   events.BranchConstruct
+		line        int
+		true, false int
+	}
+	var got []outcome
+	for _, b := range report.Branches {
+		got = append(got, outcome{b.Construct, int(b.Loc.Line), b.True, b.False})
+	}
+	assert.Equal(t, []outcome{
+		{events.BranchIf, 2, 3, 0},
+		{events.BranchIf, 4, 0, 0},
+		{events.BranchFor, 14, 3, 1},
+		{events.BranchWhile, 17, 0, 1},
+	}, got)
+	assert.Equal(t, 8, report.BranchesFound)
+	assert.Equal(t, 4, report.BranchesHit)
+}
+
+func TestBuild_DoWhileCondition(t *testing.T) {
+	report := build(t, `int main() {
+	int n = 0;
+	do {
+		n++;
+	} while (n < 4);
+	return n;
+}`)
+
+	require.Len(t, report.Branches, 1)
+	assert.Equal(t, events.BranchDoWhile, report.Branches[0].Construct)
+	assert.Equal(t, 3, report.Branches[0].True)
+	assert.Equal(t, 1, report.Branches[0].False)
+	assert.Equal(t, 4, hitsOf(report)[4])
+	assert.Empty(t, report.Uncovered)
+}
+
+func TestReport_LCOV(t *testing.T) {
+	lcov := build(t, coverageCode).LCOV("sign.c")
+	lines := strings.Split(strings.TrimSuffix(lcov, "\n"), "\n")
+
+	assert.Equal(t, "TN:", lines[0])
+	assert.Equal(t, "SF:sign.c", lines[1])
+	assert.Equal(t, "end_of_record", lines[len(lines)-1])
+	for _, expected := range []string{
+		"FN:9,unused", "FNDA:0,unused", "FNDA:3,sign", "FNF:3", "FNH:2",
+		"BRDA:2,0,0,3", "BRDA:2,0,1,0", "BRDA:4,1,0,-", "BRDA:4,1,1,-", "BRDA:14,2,1,1",
+		"BRF:8", "BRH:4",
+		"DA:3,3", "DA:5,0", "DA:15,3",
+	} {
+		assert.Contains(t, lines, expected)
+	}
+}
+
+func TestBuild_WithoutBranchEvents(t *testing.T) {
+	program, convErr := converter.New().ParseToAST(coverageCode)
+	require.Nil(t, convErr)
+	_, steps, _, err := interpreter.NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	report, err := Build(program, steps)
+	require.NoError(t, err)
+	assert.Len(t, report.Branches, 4)
+	assert.Zero(t, report.BranchesHit)
+	assert.Equal(t, 3, hitsOf(report)[3])
+
+	_, err = Build(nil, steps)
+	assert.Error(t, err)
+}
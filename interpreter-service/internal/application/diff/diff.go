@@ -0,0 +1,332 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+)
+
+// Location - положение области видимости в снимке
+type Location struct {
+	Frame    int    `json:"frame"` // индекс кадра стека, 0 - глобальный кадр
+	Function string `json:"function"`
+	Scope    int    `json:"scope"` // индекс области видимости в кадре, 0 - глобальная
+}
+
+// DeclarationKind - вид объявленного объекта
+type DeclarationKind string
+
+const (
+	KindVariable DeclarationKind = "variable"
+	KindArray    DeclarationKind = "array"
+	KindArray2D  DeclarationKind = "array2d"
+)
+
+// Declaration - объявление, появившееся или исчезнувшее между шагами
+type Declaration struct {
+	Location
+	Name string          `json:"name"`
+	Kind DeclarationKind `json:"kind"`
+	Type runtime.Type    `json:"type"`
+}
+
+// ValueChange - изменение переменной, элемента массива (Index) или элемента блока кучи (Address и Index).
+// Old - значение на шаге From, New - на шаге To; nil означает неинициализированное значение
+type ValueChange struct {
+	Location    *Location      `json:"location,omitempty"` // nil для элемента блока кучи
+	Name        string         `json:"name,omitempty"`
+	Address     int            `json:"address,omitempty"`
+	Index       []int          `json:"index,omitempty"`
+	Old         *runtime.Value `json:"old,omitempty"`
+	New         *runtime.Value `json:"new,omitempty"`
+	StepChanged int            `json:"step_changed"` // шаг последнего изменения на более позднем из двух шагов
+}
+
+// Frame - кадр стека, в который выполнение вошло или из которого вышло
+type Frame struct {
+	Frame    int    `json:"frame"`
+	Function string `json:"function"`
+}
+
+// HeapBlock - блок кучи, выделенный или освобожденный между шагами
+type HeapBlock struct {
+	Address int          `json:"address"`
+	Size    int          `json:"size"`
+	Type    runtime.Type `json:"type,omitempty"`
+	Line    int          `json:"line"`
+}
+
+// LineMove - строки выполнения на шагах From и To
+type LineMove struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Diff - различия снимков на шагах From и To (нумерация от начала main). Шаг To может быть раньше From,
+// тогда вход и выход, появление и исчезновение описываются в направлении от From к To
+type Diff struct {
+	From          int           `json:"from"`
+	To            int           `json:"to"`
+	Line          LineMove      `json:"line"`
+	Changed       []ValueChange `json:"changed,omitempty"`
+	Declared      []Declaration `json:"declared,omitempty"`
+	Removed       []Declaration `json:"removed,omitempty"`
+	FramesEntered []Frame       `json:"frames_entered,omitempty"`
+	FramesLeft    []Frame       `json:"frames_left,omitempty"`
+	ScopesEntered []Location    `json:"scopes_entered,omitempty"`
+	ScopesLeft    []Location    `json:"scopes_left,omitempty"`
+	HeapAllocated []HeapBlock   `json:"heap_allocated,omitempty"`
+	HeapFreed     []HeapBlock   `json:"heap_freed,omitempty"`
+	ErrorAppeared string        `json:"error_appeared,omitempty"`
+	ErrorCleared  string        `json:"error_cleared,omitempty"`
+}
+
+// Compare восстанавливает шаги from и to диспетчером ed и сравнивает снимки.
+// Кадры и области видимости, которые существовали на обоих шагах, определяются по событиям шагов между ними,
+// измененные значения - по номеру шага последнего изменения (StepChanged)
+func Compare(ed *eventdispatcher.EventDispatcher, stepBegin, from, to int) (Diff, error) {
+	lo, hi := min(from, to), max(from, to)
+	if err := ed.ApplyStep(lo); err != nil {
+		return Diff{}, err
+	}
+	before := ed.GetSnapshot().Clone()
+	if err := ed.ApplyStep(hi); err != nil {
+		return Diff{}, err
+	}
+	after := ed.GetSnapshot()
+
+	common, err := commonStructure(ed, stepBegin, lo, hi, before)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	d := Diff{From: lo, To: hi, Line: LineMove{From: before.Line, To: after.Line}}
+	d.compareFrames(before, after, common, lo)
+	d.compareHeap(before, after, lo)
+	if before.Error != after.Error {
+		d.ErrorCleared, d.ErrorAppeared = before.Error, after.Error
+	}
+
+	if from > to {
+		d.reverse()
+	}
+	return d, nil
+}
+
+// common - части стека вызовов, существовавшие без перерыва на всех шагах между двумя сравниваемыми:
+// frames первых кадров и scopes[k] первых областей видимости кадра k
+type common struct {
+	frames int
+	scopes []int
+}
+
+// commonStructure проходит события шагов lo+1..hi и находит наименьшую глубину стека
+// и наименьшее число областей видимости каждого кадра, достигнутые между шагами
+func commonStructure(ed *eventdispatcher.EventDispatcher, stepBegin, lo, hi int, before *snapshot.Snapshot) (common, error) {
+	scopes := make([]int, len(before.CallStack.Frames))
+	for k, frame := range before.CallStack.Frames {
+		scopes[k] = len(frame.Scopes)
+	}
+	c := common{frames: len(scopes), scopes: append([]int(nil), scopes...)}
+
+	for ind := lo + 1; ind <= hi; ind++ {
+		st, err := ed.GetStep(stepBegin + ind)
+		if err != nil {
+			return common{}, err
+		}
+		for _, event := range st.Events {
+			switch event.(type) {
+			case events.FunctionCall:
+				scopes = append(scopes, 1)
+			case events.FunctionReturn:
+				if len(scopes) <= 1 {
+					return common{}, fmt.Errorf("function return without a frame at step %d", ind)
+				}
+				scopes = scopes[:len(scopes)-1]
+			case events.EnterScope:
+				scopes[len(scopes)-1]++
+			case events.ExitScope:
+				scopes[len(scopes)-1]--
+			default:
+				continue
+			}
+			c.frames = min(c.frames, len(scopes))
+			if top := len(scopes) - 1; top < len(c.scopes) {
+				c.scopes[top] = min(c.scopes[top], scopes[top])
+			}
+		}
+	}
+	return c, nil
+}
+
+// compareFrames сравнивает стеки вызовов: общие объявления по значениям,
+// остальные кадры, области видимости и объявления - как вошедшие или покинутые
+func (d *Diff) compareFrames(before, after *snapshot.Snapshot, c common, lo int) {
+	for k, frame := range before.CallStack.Frames {
+		if k >= c.frames {
+			d.FramesLeft = append(d.FramesLeft, Frame{Frame: k, Function: frame.FuncName})
+		}
+	}
+	for k, frame := range after.CallStack.Frames {
+		if k >= c.frames {
+			d.FramesEntered = append(d.FramesEntered, Frame{Frame: k, Function: frame.FuncName})
+		}
+	}
+
+	for k, frame := range before.CallStack.Frames {
+		for s, scope := range frame.Scopes {
+			if skipScope(k, s) {
+				continue
+			}
+			loc := Location{Frame: k, Function: frame.FuncName, Scope: s}
+			if k < c.frames && s < c.scopes[k] {
+				continue
+			}
+			if k < c.frames {
+				d.ScopesLeft = append(d.ScopesLeft, loc)
+			}
+			d.Removed = append(d.Removed, declarations(loc, scope.Declarations.Declarations)...)
+		}
+	}
+
+	for k, frame := range after.CallStack.Frames {
+		for s, scope := range frame.Scopes {
+			if skipScope(k, s) {
+				continue
+			}
+			loc := Location{Frame: k, Function: frame.FuncName, Scope: s}
+			if k < c.frames && s < c.scopes[k] {
+				// область видимости не закрывалась между шагами: объявления только добавлялись в ее конец
+				old := before.CallStack.Frames[k].Scopes[s].Declarations.Declarations
+				d.compareDeclarations(loc, old, scope.Declarations.Declarations[:len(old)], lo)
+				d.Declared = append(d.Declared, declarations(loc, scope.Declarations.Declarations[len(old):])...)
+				continue
+			}
+			if k < c.frames {
+				d.ScopesEntered = append(d.ScopesEntered, loc)
+			}
+			d.Declared = append(d.Declared, declarations(loc, scope.Declarations.Declarations)...)
+		}
+	}
+}
+
+// skipScope сообщает, что область видимости - глобальная в кадре функции (она уже учтена в глобальном кадре)
+func skipScope(frame, scope int) bool {
+	return frame > 0 && scope == 0
+}
+
+func (d *Diff) compareDeclarations(loc Location, old, cur []runtime.Declared, lo int) {
+	for ind := range cur {
+		switch v := cur[ind].(type) {
+		case *runtime.Variable:
+			prev, ok := old[ind].(*runtime.Variable)
+			if !ok || v.StepChanged <= lo || sameValue(prev.Value, v.Value) {
+				continue
+			}
+			d.Changed = append(d.Changed, ValueChange{Location: &loc, Name: v.Name, Old: prev.Value, New: v.Value, StepChanged: v.StepChanged})
+		case *runtime.Array:
+			prev, ok := old[ind].(*runtime.Array)
+			if !ok {
+				continue
+			}
+			for _, ch := range changedElements(prev.Values, v.Values, lo) {
+				ch.Location, ch.Name = &loc, v.Name
+				d.Changed = append(d.Changed, ch)
+			}
+		case *runtime.Array2D:
+			prev, ok := old[ind].(*runtime.Array2D)
+			if !ok {
+				continue
+			}
+			for row := range v.Values {
+				if row >= len(prev.Values) {
+					break
+				}
+				for _, ch := range changedElements(prev.Values[row].Values, v.Values[row].Values, lo) {
+					ch.Location, ch.Name, ch.Index = &loc, v.Name, append([]int{row}, ch.Index...)
+					d.Changed = append(d.Changed, ch)
+				}
+			}
+		}
+	}
+}
+
+// changedElements возвращает элементы, измененные после шага lo и отличающиеся от прежних значений
+func changedElements(old, cur []runtime.ArrayElement, lo int) []ValueChange {
+	var changes []ValueChange
+	for ind, el := range cur {
+		var prev *runtime.Value
+		if ind < len(old) {
+			prev = old[ind].Value
+		}
+		if el.StepChanged <= lo || sameValue(prev, el.Value) {
+			continue
+		}
+		changes = append(changes, ValueChange{Index: []int{ind}, Old: prev, New: el.Value, StepChanged: el.StepChanged})
+	}
+	return changes
+}
+
+func sameValue(a, b *runtime.Value) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func declarations(loc Location, decls []runtime.Declared) []Declaration {
+	var result []Declaration
+	for _, decl := range decls {
+		switch v := decl.(type) {
+		case *runtime.Variable:
+			result = append(result, Declaration{Location: loc, Name: v.Name, Kind: KindVariable, Type: v.Type})
+		case *runtime.Array:
+			result = append(result, Declaration{Location: loc, Name: v.Name, Kind: KindArray, Type: v.Type})
+		case *runtime.Array2D:
+			result = append(result, Declaration{Location: loc, Name: v.Name, Kind: KindArray2D, Type: v.Type})
+		}
+	}
+	return result
+}
+
+// compareHeap сравнивает блоки кучи по адресам (адреса не переиспользуются).
+// Блок, выделенный и освобожденный между шагами, не попадает в различия
+func (d *Diff) compareHeap(before, after *snapshot.Snapshot, lo int) {
+	for _, block := range after.Heap.AllBlocks() {
+		old, existed := before.Heap.Find(block.Address)
+		switch {
+		case !existed && !block.Freed:
+			d.HeapAllocated = append(d.HeapAllocated, heapBlock(block))
+		case !existed:
+			continue
+		case !old.Freed && block.Freed:
+			d.HeapFreed = append(d.HeapFreed, heapBlock(old))
+		case !block.Freed:
+			for _, ch := range changedElements(old.Values, block.Values, lo) {
+				ch.Address = block.Address
+				d.Changed = append(d.Changed, ch)
+			}
+		}
+	}
+}
+
+func heapBlock(block *runtime.HeapBlock) HeapBlock {
+	return HeapBlock{Address: block.Address, Size: block.Size, Type: block.Type, Line: block.Line}
+}
+
+// reverse описывает различия в направлении от более позднего шага к более раннему
+func (d *Diff) reverse() {
+	d.From, d.To = d.To, d.From
+	d.Line.From, d.Line.To = d.Line.To, d.Line.From
+	for ind := range d.Changed {
+		d.Changed[ind].Old, d.Changed[ind].New = d.Changed[ind].New, d.Changed[ind].Old
+	}
+	d.Declared, d.Removed = d.Removed, d.Declared
+	d.FramesEntered, d.FramesLeft = d.FramesLeft, d.FramesEntered
+	d.ScopesEntered, d.ScopesLeft = d.ScopesLeft, d.ScopesEntered
+	d.HeapAllocated, d.HeapFreed = d.HeapFreed, d.HeapAllocated
+	d.ErrorAppeared, d.ErrorCleared = d.ErrorCleared, d.ErrorAppeared
+}
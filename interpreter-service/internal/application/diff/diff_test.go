@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+const diffCode = `int total = 0;
+
+int square(int x) {
+	int r = x * x;
+	return r;
+}
+
+int main() {
+	int a[3] = {1, 2, 3};
+	int *p = (int*)malloc(2 * sizeof(int));
+	for (int i = 0; i < 3; i++) {
+		a[i] = square(a[i]);
+		total += a[i];
+	}
+	p[0] = total;
+	free(p);
+	return 0;
+}`
+
+type trace struct {
+	steps     []step.Step
+	stepBegin int
+}
+
+func run(t *testing.T, code string) trace {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	_, steps, stepBegin, err := interpreter.NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+	return trace{steps: steps, stepBegin: stepBegin}
+}
+
+// onLine возвращает номера шагов (от начала main), на которых начинается выполнение строки line
+func (tr trace) onLine(line int) []int {
+	var result []int
+	for ind := tr.stepBegin; ind < len(tr.steps); ind++ {
+		if tr.steps[ind].Line == line && tr.steps[ind].Kind != step.KindCallReturn {
+			result = append(result, ind-tr.stepBegin)
+		}
+	}
+	return result
+}
+
+func (tr trace) compare(t *testing.T, from, to int) Diff {
+	t.Helper()
+
+	ed := eventdispatcher.NewEventDispatcher(tr.stepBegin)
+	ed.Steps = tr.steps
+	d, err := Compare(ed, tr.stepBegin, from, to)
+	require.NoError(t, err)
+	return d
+}
+
+func TestCompare_LoopIteration(t *testing.T) {
+	tr := run(t, diffCode)
+	lines := tr.onLine(13)
+	require.Len(t, lines, 3)
+
+	d := tr.compare(t, lines[0], lines[1])
+	assert.Equal(t, LineMove{From: 13, To: 13}, d.Line)
+	assert.Empty(t, d.FramesEntered)
+	assert.Empty(t, d.FramesLeft)
+	assert.Empty(t, d.Declared)
+	assert.Empty(t, d.Removed)
+
+	// тело цикла - новая область видимости на каждой итерации
+	body := Location{Frame: 1, Function: "main", Scope: 4}
+	assert.Equal(t, []Location{body}, d.ScopesLeft)
+	assert.Equal(t, []Location{body}, d.ScopesEntered)
+
+	changed := map[string]ValueChange{}
+	for _, ch := range d.Changed {
+		changed[ch.Name] = ch
+	}
+	require.Len(t, changed, 3)
+	assert.Equal(t, 0, changed["total"].Old.Int)
+	assert.Equal(t, 1, changed["total"].New.Int)
+	assert.Equal(t, 0, changed["total"].Location.Frame)
+	assert.Equal(t, []int{1}, changed["a"].Index)
+	assert.Equal(t, 2, changed["a"].Old.Int)
+	assert.Equal(t, 4, changed["a"].New.Int)
+	assert.Equal(t, 0, changed["i"].Old.Int)
+	assert.Equal(t, 1, changed["i"].New.Int)
+	assert.Greater(t, changed["i"].StepChanged, lines[0])
+}
+
+func TestCompare_FramesAndDeclarations(t *testing.T) {
+	tr := run(t, diffCode)
+	inSquare := tr.onLine(5)[0]
+	after := tr.onLine(15)[0]
+
+	d := tr.compare(t, inSquare, after)
+	assert.Equal(t, []Frame{{Frame: 2, Function: "square"}}, d.FramesLeft)
+	assert.Empty(t, d.FramesEntered)
+
+	removed := map[string]Declaration{}
+	for _, decl := range d.Removed {
+		removed[decl.Name] = decl
+	}
+	assert.Len(t, removed, 3)
+	assert.Equal(t, KindVariable, removed["x"].Kind)
+	assert.Equal(t, "square", removed["r"].Function)
+	assert.Equal(t, "main", removed["i"].Function)
+	assert.Contains(t, d.ScopesLeft, Location{Frame: 1, Function: "main", Scope: 3})
+
+	// в обратном направлении кадр и объявления появляются
+	back := tr.compare(t, after, inSquare)
+	assert.Equal(t, after, back.From)
+	assert.Equal(t, inSquare, back.To)
+	assert.Equal(t, d.FramesLeft, back.FramesEntered)
+	assert.ElementsMatch(t, d.Removed, back.Declared)
+	assert.Equal(t, LineMove{From: 15, To: 5}, back.Line)
+}
+
+func TestCompare_Heap(t *testing.T) {
+	tr := run(t, diffCode)
+	beforeAlloc := tr.onLine(10)[0]
+	beforeWrite := tr.onLine(15)[0]
+	beforeFree := tr.onLine(16)[0]
+	end := tr.onLine(17)[0]
+
+	d := tr.compare(t, beforeAlloc, beforeWrite)
+	require.Len(t, d.HeapAllocated, 1)
+	assert.Equal(t, 8, d.HeapAllocated[0].Size)
+	assert.Equal(t, 10, d.HeapAllocated[0].Line)
+	assert.Equal(t, "p", d.Declared[0].Name)
+
+	d = tr.compare(t, beforeWrite, beforeFree)
+	require.Len(t, d.Changed, 1)
+	address := d.Changed[0].Address
+	assert.NotZero(t, address)
+	assert.Equal(t, []int{0}, d.Changed[0].Index)
+	assert.Nil(t, d.Changed[0].Old)
+	assert.Equal(t, 14, d.Changed[0].New.Int)
+
+	d = tr.compare(t, beforeFree, end)
+	require.Len(t, d.HeapFreed, 1)
+	assert.Equal(t, address, d.HeapFreed[0].Address)
+
+	d = tr.compare(t, end, beforeFree)
+	assert.Empty(t, d.HeapFreed)
+	require.Len(t, d.HeapAllocated, 1)
+	assert.Equal(t, address, d.HeapAllocated[0].Address)
+}
+
+func TestCompare_SameStep(t *testing.T) {
+	tr := run(t, diffCode)
+	ind := tr.onLine(13)[1]
+
+	d := tr.compare(t, ind, ind)
+	assert.Equal(t, Diff{From: ind, To: ind, Line: LineMove{From: 13, To: 13}}, d)
+}
+
+func TestCompare_InvalidStep(t *testing.T) {
+	tr := run(t, diffCode)
+
+	ed := eventdispatcher.NewEventDispatcher(tr.stepBegin)
+	ed.Steps = tr.steps
+	_, err := Compare(ed, tr.stepBegin, 0, len(tr.steps))
+	assert.Error(t, err)
+}
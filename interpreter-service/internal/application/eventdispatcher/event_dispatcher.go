@@ -2,7 +2,10 @@ package eventdispatcher
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
@@ -12,6 +15,19 @@ type EventDispatcher struct {
 	Steps            []step.Step
 	currentStepIndex int
 	stepBegin        int
+	checkpoints      []snapshot.Checkpoint // упорядочены по номеру шага
+	history          []appliedStep         // последние примененные шаги, заканчивая текущим
+}
+
+// appliedStep - примененный шаг: его события с записанным предыдущим состоянием,
+// результат возврата из функции, чтения, маркеры индексов и уничтоженные переменные, которые снимок показывал до шага
+type appliedStep struct {
+	events       []events.Event
+	functionName string
+	returnValue  *runtime.Value
+	accessed     []snapshot.Access
+	indexMarkers []snapshot.IndexMarker
+	destroyed    []snapshot.Destroyed
 }
 
 func NewEventDispatcher(stepBegin int) *EventDispatcher {
@@ -30,25 +46,106 @@ func (ed *EventDispatcher) ApplyStep(stepIndex int) error {
 		return fmt.Errorf("invalid step index: %d (total steps: %d)", stepIndex, len(ed.Steps))
 	}
 
-	if stepIndex < ed.currentStepIndex {
+	checkpoint, hasCheckpoint := ed.nearestCheckpoint(stepIndex)
+	if stepIndex < ed.currentStepIndex && ed.currentStepIndex-stepIndex <= len(ed.history) &&
+		(!hasCheckpoint || ed.currentStepIndex-stepIndex <= stepIndex-checkpoint.Step-ed.stepBegin) {
+		// шаги назад отменяются, если это не дольше восстановления с ближайшего сохраненного состояния
+		return ed.unapplySteps(stepIndex)
+	}
+
+	if hasCheckpoint && (stepIndex < ed.currentStepIndex || checkpoint.Step+ed.stepBegin > ed.currentStepIndex) {
+		// копия не дает изменить сохраненное состояние при применении следующих шагов
+		ed.Snapshot = checkpoint.Snapshot.Clone()
+		ed.currentStepIndex = checkpoint.Step + ed.stepBegin
+		ed.history = nil
+	} else if stepIndex < ed.currentStepIndex {
+		// шаги применяются заново с абсолютного шага 0, включая объявления глобальных переменных до main
 		ed.Snapshot.Reset()
-		ed.currentStepIndex = ed.stepBegin - 1
+		ed.currentStepIndex = -1
+		ed.history = nil
 	}
 
 	startIndex := ed.currentStepIndex + 1
 	for i := startIndex; i <= stepIndex; i++ {
+		applied := appliedStep{
+			events:       make([]events.Event, 0, len(ed.Steps[i].Events)),
+			functionName: ed.Snapshot.FunctionName,
+			returnValue:  ed.Snapshot.ReturnValue,
+			accessed:     ed.Snapshot.Accessed,
+			indexMarkers: ed.Snapshot.IndexMarkers,
+			destroyed:    ed.Snapshot.Destroyed,
+		}
 		ed.Snapshot.NewStep()
 		for _, event := range ed.Steps[i].Events {
-			if err := ed.Snapshot.Apply(event, i-ed.stepBegin); err != nil {
+			recorded, err := ed.Snapshot.Record(event, i-ed.stepBegin)
+			if err != nil {
+				ed.history = nil
 				return err
 			}
+			applied.events = append(applied.events, recorded)
 		}
+		ed.history = append(ed.history, applied)
 		ed.currentStepIndex = i
 	}
 
 	return nil
 }
 
+// unapplySteps отменяет примененные шаги после шага stepIndex (абсолютный индекс) в обратном порядке
+func (ed *EventDispatcher) unapplySteps(stepIndex int) error {
+	for ed.currentStepIndex > stepIndex {
+		applied := ed.history[len(ed.history)-1]
+		for k := len(applied.events) - 1; k >= 0; k-- {
+			if err := ed.Snapshot.Unapply(applied.events[k]); err != nil {
+				// состояние частично отменено, следующий переход восстановит его заново
+				ed.Snapshot.Reset()
+				ed.currentStepIndex = -1
+				ed.history = nil
+				return err
+			}
+		}
+		ed.Snapshot.FunctionName, ed.Snapshot.ReturnValue = applied.functionName, applied.returnValue
+		ed.Snapshot.Accessed, ed.Snapshot.IndexMarkers = applied.accessed, applied.indexMarkers
+		ed.Snapshot.Destroyed = applied.destroyed
+		ed.history = ed.history[:len(ed.history)-1]
+		ed.currentStepIndex--
+	}
+	return nil
+}
+
+// SetCheckpoints задает сохраненные состояния, с которых восстанавливаются шаги вместо применения всех шагов с начала
+func (ed *EventDispatcher) SetCheckpoints(checkpoints []snapshot.Checkpoint) {
+	ed.checkpoints = checkpoints
+}
+
+// nearestCheckpoint возвращает последнее сохраненное состояние не позже шага stepIndex (абсолютный индекс)
+func (ed *EventDispatcher) nearestCheckpoint(stepIndex int) (snapshot.Checkpoint, bool) {
+	ind := sort.Search(len(ed.checkpoints), func(i int) bool { return ed.checkpoints[i].Step+ed.stepBegin > stepIndex })
+	if ind == 0 {
+		return snapshot.Checkpoint{}, false
+	}
+	return ed.checkpoints[ind-1], true
+}
+
+// BuildCheckpoints применяет шаги по порядку и сохраняет копию состояния после каждого interval-го шага,
+// начиная с шага 0 (начало main). При ошибке применения шага возвращаются состояния, сохраненные до нее
+func BuildCheckpoints(steps []step.Step, stepBegin, interval int) ([]snapshot.Checkpoint, error) {
+	if interval <= 0 {
+		return nil, nil
+	}
+
+	ed := NewEventDispatcher(stepBegin)
+	ed.Steps = steps
+	var checkpoints []snapshot.Checkpoint
+	for ind := 0; ind < len(steps)-stepBegin; ind += interval {
+		if err := ed.ApplyStep(ind); err != nil {
+			return checkpoints, err
+		}
+		checkpoints = append(checkpoints, snapshot.Checkpoint{Step: ind, Snapshot: ed.GetSnapshot().Clone()})
+	}
+	return checkpoints, nil
+}
+
 func (ed *EventDispatcher) GetCurrentStep() int {
 	return ed.currentStepIndex
 }
@@ -1,6 +1,7 @@
 package eventdispatcher_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -11,7 +12,9 @@ import (
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
 
@@ -61,7 +64,7 @@ func assertSnapshotState(t *testing.T, ed *eventdispatcher.EventDispatcher, expe
 		require.True(t, exists, "variable %s should exist", name)
 		val, err := v.GetValue()
 		require.NoError(t, err)
-		assert.Equal(t, expVar.Value, val)
+		assert.Equal(t, runtime.NewIntValue(expVar.Value), val)
 	}
 
 	for name, expArr := range expected.Arrays {
@@ -80,7 +83,7 @@ func assertSnapshotState(t *testing.T, ed *eventdispatcher.EventDispatcher, expe
 			require.NoError(t, err)
 			value, err := element.GetValue()
 			require.NoError(t, err)
-			assert.Equal(t, expectedValue, value)
+			assert.Equal(t, runtime.NewIntValue(expectedValue), value)
 		}
 	}
 
@@ -100,7 +103,7 @@ func assertSnapshotState(t *testing.T, ed *eventdispatcher.EventDispatcher, expe
 			for j, expectedValue := range expArr2D.Values[i] {
 				value, err := arr2D.GetElement(i, j)
 				require.NoError(t, err)
-				assert.Equal(t, expectedValue, value)
+				assert.Equal(t, runtime.NewIntValue(expectedValue), value)
 			}
 		}
 	}
@@ -985,7 +988,7 @@ func TestEventDispatcher_ApplyStepStopsOnError(t *testing.T) {
 	baseStepsCount := len(ed.Steps)
 	badStepIndex := baseStepsCount - stepBegin
 	ed.Steps = append(ed.Steps, step.Step{Events: []events.Event{
-		events.VarChanged{Name: "missing", Value: 1},
+		events.VarChanged{Name: "missing", Value: runtime.NewIntValue(1)},
 	}})
 
 	err := ed.ApplyStep(badStepIndex)
@@ -1106,7 +1109,7 @@ func TestEventDispatcher_GetStep(t *testing.T) {
 }
 
 func TestEventDispatcher_ApplyStepIdempotent(t *testing.T) {
-	value := 10
+	value := runtime.NewIntValue(10)
 	ed := eventdispatcher.NewEventDispatcher(0)
 	ed.Steps = []step.Step{
 		{Events: []events.Event{events.LineChanged{Line: 1}}},
@@ -1161,12 +1164,12 @@ func TestEventDispatcher_ApplyStepEmptyStepAdvancesIndex(t *testing.T) {
 }
 
 func TestEventDispatcher_ApplyStepPartialFailureAndRollbackRecovery(t *testing.T) {
-	initial := 1
+	initial := runtime.NewIntValue(1)
 	ed := eventdispatcher.NewEventDispatcher(0)
 	ed.Steps = []step.Step{
 		{Events: []events.Event{events.DeclareVar{Name: "x", Value: &initial}, events.LineChanged{Line: 1}}},
 		{Events: []events.Event{events.LineChanged{Line: 2}}},
-		{Events: []events.Event{events.VarChanged{Name: "x", Value: 5}, events.VarChanged{Name: "missing", Value: 1}}},
+		{Events: []events.Event{events.VarChanged{Name: "x", Value: runtime.NewIntValue(5)}, events.VarChanged{Name: "missing", Value: runtime.NewIntValue(1)}}},
 	}
 
 	require.NoError(t, ed.ApplyStep(1))
@@ -1206,6 +1209,32 @@ func TestEventDispatcher_ApplyStepPartialFailureAndRollbackRecovery(t *testing.T
 	})
 }
 
+func TestEventDispatcher_ReplayWithoutCheckpointsKeepsGlobals(t *testing.T) {
+	global := runtime.NewIntValue(7)
+	ed := eventdispatcher.NewEventDispatcher(1)
+	ed.Steps = []step.Step{
+		{Events: []events.Event{events.DeclareVar{Name: "g", Value: &global, IsGlobal: true}, events.LineChanged{Line: 1}}},
+		{Events: []events.Event{events.LineChanged{Line: 3}}},
+		{Events: []events.Event{events.LineChanged{Line: 4}}},
+		{Events: []events.Event{events.VarChanged{Name: "missing", Value: runtime.NewIntValue(1)}}},
+	}
+
+	require.NoError(t, ed.ApplyStep(1))
+	// ошибка применения сбрасывает историю, поэтому шаг назад восстанавливается повторным применением шагов
+	require.Error(t, ed.ApplyStep(2))
+
+	require.NoError(t, ed.ApplyStep(0))
+	assert.Equal(t, 1, ed.GetCurrentStep())
+	assertSnapshotState(t, ed, expectedSnapshotState{
+		FramesCount:       1,
+		CurrentLine:       3,
+		CurrentFrameScope: 1,
+		Variables: map[string]expectedVariable{
+			"g": {Exists: true, Value: 7},
+		},
+	})
+}
+
 func TestEventDispatcher_StepBeginNegativeExternalIndex(t *testing.T) {
 	ed := eventdispatcher.NewEventDispatcher(2)
 	ed.Steps = []step.Step{
@@ -1333,3 +1362,115 @@ func TestEventDispatcher_RollbackRuntimeError(t *testing.T) {
 		Error:             "runtime error: division by zero",
 	})
 }
+
+const checkpointCode = `enum Color { RED, GREEN };
+
+int fact(int n) {
+	if (n <= 1) {
+		return 1;
+	}
+	return n * fact(n - 1);
+}
+
+int main() {
+	enum Color c = GREEN;
+	int a[3] = {1, 2, 3};
+	int m[2][2] = {{1, 2}, {3, 4}};
+	int *p = (int*)malloc(3 * sizeof(int));
+	int *q = (int*)malloc(2 * sizeof(int));
+	for (int i = 0; i < 3; i++) {
+		p[i] = fact(a[i]);
+		m[1][1] = m[1][1] + p[i];
+	}
+	free(p);
+	q[0] = 5;
+	return 0;
+}`
+
+// snapshotJSON восстанавливает шаг ind отдельным диспетчером без сохраненных состояний
+func snapshotJSON(t *testing.T, steps []step.Step, stepBegin, ind int) string {
+	t.Helper()
+
+	ed := eventdispatcher.NewEventDispatcher(stepBegin)
+	ed.Steps = steps
+	require.NoError(t, ed.ApplyStep(ind))
+	data, err := json.Marshal(ed.GetSnapshot())
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestEventDispatcher_CheckpointsMatchReplay(t *testing.T) {
+	program, convErr := converter.New().ParseToAST(checkpointCode)
+	require.Nil(t, convErr)
+	_, steps, stepBegin, err := interpreter.NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+	count := len(steps) - stepBegin
+
+	checkpoints, err := eventdispatcher.BuildCheckpoints(steps, stepBegin, 4)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, (count+3)/4)
+
+	// сохраненные состояния восстанавливаются из сериализованного представления (как из кэша)
+	restored := make([]snapshot.Checkpoint, len(checkpoints))
+	for ind, checkpoint := range checkpoints {
+		dto, err := snapshot.MarshalCheckpoint(checkpoint)
+		require.NoError(t, err)
+		data, err := json.Marshal(dto)
+		require.NoError(t, err)
+		var decoded snapshot.CheckpointDTO
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		restored[ind], err = snapshot.UnmarshalCheckpoint(decoded)
+		require.NoError(t, err)
+	}
+
+	for _, cps := range [][]snapshot.Checkpoint{checkpoints, restored} {
+		ed := eventdispatcher.NewEventDispatcher(stepBegin)
+		ed.Steps = steps
+		ed.SetCheckpoints(cps)
+
+		// произвольный порядок: вперед, назад и повторно, чтобы проверить, что сохраненные состояния не меняются
+		order := []int{count - 1, 0, 5, 3, count / 2, 2, count - 2, 7, 6, count - 1, 1}
+		for _, ind := range order {
+			require.NoError(t, ed.ApplyStep(ind))
+			data, err := json.Marshal(ed.GetSnapshot())
+			require.NoError(t, err)
+			assert.JSONEq(t, snapshotJSON(t, steps, stepBegin, ind), string(data), "step %d", ind)
+			assert.Equal(t, stepBegin+ind, ed.GetCurrentStep())
+		}
+	}
+}
+
+func TestEventDispatcher_StepBackwardMatchesReplay(t *testing.T) {
+	modes := []struct{ expressionSteps, readEvents, indexMarkers bool }{
+		{false, false, false}, {true, false, false}, {false, true, true},
+	}
+	for _, mode := range modes {
+		program, convErr := converter.New().ParseToAST(checkpointCode)
+		require.Nil(t, convErr)
+		i := interpreter.NewInterpreter()
+		i.SetExpressionStepping(mode.expressionSteps)
+		i.SetReadEvents(mode.readEvents)
+		i.SetIndexMarkers(mode.indexMarkers)
+		_, steps, stepBegin, err := i.ExecuteProgram(program)
+		require.NoError(t, err)
+		count := len(steps) - stepBegin
+
+		ed := eventdispatcher.NewEventDispatcher(stepBegin)
+		ed.Steps = steps
+		require.NoError(t, ed.ApplyStep(count-1))
+
+		// шаги назад отменяются по одному, затем часть пути проходится вперед и снова назад
+		order := make([]int, 0, count+8)
+		for ind := count - 2; ind >= 0; ind-- {
+			order = append(order, ind)
+		}
+		order = append(order, count/2, count/2-3, count-1, count/3)
+		for _, ind := range order {
+			require.NoError(t, ed.ApplyStep(ind))
+			data, err := json.Marshal(ed.GetSnapshot())
+			require.NoError(t, err)
+			assert.JSONEq(t, snapshotJSON(t, steps, stepBegin, ind), string(data), "mode %+v, step %d", mode, ind)
+			assert.Equal(t, stepBegin+ind, ed.GetCurrentStep())
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/watch"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+)
+
+// Point - значение выражения начиная с шага Step (нумерация от начала main).
+// Если выражение на шаге вычислить нельзя (например, переменная вне области видимости), задана ошибка
+type Point struct {
+	Step  int            `json:"step"`
+	Line  int            `json:"line"` // строка, выполнение которой привело к состоянию шага (строка предыдущего шага)
+	Value *runtime.Value `json:"value,omitempty"`
+	Error string         `json:"error,omitempty"`
+	Write bool           `json:"write,omitempty"` // объект (переменная, элемент массива) записан на этом шаге
+}
+
+// Tracker восстанавливает шаги трассы и вычисляет на них выражения
+type Tracker struct {
+	ed        *eventdispatcher.EventDispatcher
+	stepBegin int
+}
+
+func NewTracker(ed *eventdispatcher.EventDispatcher, stepBegin int) *Tracker {
+	return &Tracker{ed: ed, stepBegin: stepBegin}
+}
+
+// StepsCount возвращает число шагов от начала main
+func (t *Tracker) StepsCount() int {
+	return max(t.ed.GetStepsCount()-t.stepBegin, 0)
+}
+
+// Changes возвращает историю значения выражения на шагах from..to: первая точка - значение на шаге from,
+// следующие - шаги, на которых значение изменилось или выражение стало (не)вычислимым.
+// Для переменной, элемента массива или *p в историю попадает и запись того же значения
+func (t *Tracker) Changes(source string, from, to int) ([]Point, error) {
+	expr, err := watch.ParseExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkRange(from, to); err != nil {
+		return nil, err
+	}
+
+	location := watch.IsLocation(expr)
+	var points []Point
+	var prev Point
+	for ind := from; ind <= to; ind++ {
+		sn, err := t.apply(ind)
+		if err != nil {
+			return nil, err
+		}
+
+		cur := evaluate(sn, expr, location, ind)
+		cur.Line = t.lineBefore(ind)
+		if ind == from || cur.Write || !sameResult(prev, cur) {
+			points = append(points, cur)
+		}
+		prev = cur
+	}
+	return points, nil
+}
+
+// First возвращает первый шаг из from..to, на котором условие истинно, или nil, если такого шага нет.
+// Условие, которое на шаге вычислить нельзя, считается ложным
+func (t *Tracker) First(condition string, from, to int) (*Point, error) {
+	expr, err := watch.ParseExpression(condition)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkRange(from, to); err != nil {
+		return nil, err
+	}
+
+	for ind := from; ind <= to; ind++ {
+		sn, err := t.apply(ind)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := interpreter.Inspect(sn, expr)
+		if err != nil {
+			continue
+		}
+		if truth, err := value.Truth(); err == nil && truth {
+			return &Point{Step: ind, Line: t.lineBefore(ind), Value: &value}, nil
+		}
+	}
+	return nil, nil
+}
+
+// LastWrite возвращает последнюю запись переменной, элемента массива или *p не позже шага before.
+// Шаг записи берется из снимка на шаге before (StepChanged), поэтому шаги до него не восстанавливаются
+func (t *Tracker) LastWrite(target string, before int) (*Point, error) {
+	expr, err := watch.ParseExpression(target)
+	if err != nil {
+		return nil, err
+	}
+	if !watch.IsLocation(expr) {
+		return nil, fmt.Errorf("%s: expected a variable or an array element", target)
+	}
+	if err := t.checkRange(before, before); err != nil {
+		return nil, err
+	}
+
+	sn, err := t.apply(before)
+	if err != nil {
+		return nil, err
+	}
+	value, stepChanged, err := interpreter.InspectLocation(sn, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Point{Step: stepChanged, Line: t.lineBefore(stepChanged), Value: &value, Write: true}, nil
+}
+
+// lineBefore возвращает строку, выполнение которой привело к шагу ind: события шага - результат
+// выполнения строки предыдущего шага. Для первого шага трассы возвращается его собственная строка
+func (t *Tracker) lineBefore(ind int) int {
+	if st, err := t.ed.GetStep(t.stepBegin + ind - 1); err == nil {
+		return st.Line
+	}
+	if st, err := t.ed.GetStep(t.stepBegin + ind); err == nil {
+		return st.Line
+	}
+	return 0
+}
+
+func (t *Tracker) checkRange(from, to int) error {
+	count := t.StepsCount()
+	if from < 0 || to >= count || from > to {
+		return fmt.Errorf("invalid step range: %d..%d (total steps: %d)", from, to, count)
+	}
+	return nil
+}
+
+func (t *Tracker) apply(ind int) (*snapshot.Snapshot, error) {
+	if err := t.ed.ApplyStep(ind); err != nil {
+		return nil, err
+	}
+	return t.ed.GetSnapshot(), nil
+}
+
+// evaluate вычисляет выражение на снимке шага ind. Для изменяемого объекта отмечается запись на этом шаге
+func evaluate(sn *snapshot.Snapshot, expr converter.Expr, location bool, ind int) Point {
+	point := Point{Step: ind}
+
+	var value runtime.Value
+	var err error
+	if location {
+		var stepChanged int
+		value, stepChanged, err = interpreter.InspectLocation(sn, expr)
+		point.Write = err == nil && stepChanged == ind
+	} else {
+		value, err = interpreter.Inspect(sn, expr)
+	}
+
+	if err != nil {
+		point.Error = err.Error()
+		return point
+	}
+	point.Value = &value
+	return point
+}
+
+func sameResult(a, b Point) bool {
+	if a.Value == nil || b.Value == nil {
+		return a.Value == nil && b.Value == nil && a.Error == b.Error
+	}
+	return *a.Value == *b.Value
+}
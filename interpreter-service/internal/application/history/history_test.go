@@ -0,0 +1,178 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+)
+
+const historyCode = `int main() {
+	int a[5] = {3, 9, 4, 11, 1};
+	int max = a[0];
+	for (int i = 1; i < 5; i++) {
+		if (a[i] > max) {
+			max = a[i];
+		}
+	}
+	return max;
+}`
+
+func newTracker(t *testing.T, code string) *Tracker {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	_, steps, stepBegin, err := interpreter.NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	ed := eventdispatcher.NewEventDispatcher(stepBegin)
+	ed.Steps = steps
+	return NewTracker(ed, stepBegin)
+}
+
+func writes(points []Point) []int {
+	var values []int
+	for _, p := range points {
+		if p.Write {
+			values = append(values, p.Value.Int)
+		}
+	}
+	return values
+}
+
+func TestChanges_Variable(t *testing.T) {
+	tr := newTracker(t, historyCode)
+	last := tr.StepsCount() - 1
+
+	points, err := tr.Changes("max", 0, last)
+	require.NoError(t, err)
+	require.NotEmpty(t, points)
+
+	// до объявления и после выхода из main переменная не видна
+	assert.Equal(t, 0, points[0].Step)
+	assert.Contains(t, points[0].Error, "not declared")
+	assert.NotEmpty(t, points[len(points)-1].Error)
+
+	assert.Equal(t, []int{3, 9, 11}, writes(points))
+	for _, p := range points {
+		if p.Write && p.Value.Int != 3 {
+			assert.Equal(t, 6, p.Line)
+		}
+	}
+	for ind := 1; ind < len(points); ind++ {
+		assert.Greater(t, points[ind].Step, points[ind-1].Step)
+	}
+}
+
+func TestChanges_Expression(t *testing.T) {
+	tr := newTracker(t, historyCode)
+
+	all, err := tr.Changes("a[i] > max", 0, tr.StepsCount()-1)
+	require.NoError(t, err)
+
+	var values []int
+	for _, p := range all {
+		if p.Value != nil {
+			values = append(values, p.Value.Int)
+		}
+		assert.False(t, p.Write)
+	}
+	// 9 > 3, затем max = 9; 4 > 9 ложно; 11 > 9, затем max = 11; 1 > 11 ложно
+	assert.Equal(t, []int{1, 0, 1, 0}, values)
+}
+
+func TestChanges_Subrange(t *testing.T) {
+	tr := newTracker(t, historyCode)
+	all, err := tr.Changes("i", 0, tr.StepsCount()-1)
+	require.NoError(t, err)
+
+	var from int
+	for _, p := range all {
+		if p.Value != nil && p.Value.Int == 3 {
+			from = p.Step
+			break
+		}
+	}
+	require.NotZero(t, from)
+
+	points, err := tr.Changes("i", from, tr.StepsCount()-1)
+	require.NoError(t, err)
+	require.NotEmpty(t, points)
+	assert.Equal(t, from, points[0].Step)
+	assert.Equal(t, 3, points[0].Value.Int)
+	assert.Equal(t, []int{3, 4, 5}, writes(points))
+}
+
+func TestFirst(t *testing.T) {
+	tr := newTracker(t, historyCode)
+
+	match, err := tr.First("i == 3", 0, tr.StepsCount()-1)
+	require.NoError(t, err)
+	require.NotNil(t, match)
+	assert.Equal(t, 1, match.Value.Int)
+
+	points, err := tr.Changes("i", 0, match.Step)
+	require.NoError(t, err)
+	assert.Equal(t, 3, points[len(points)-1].Value.Int)
+	assert.Equal(t, match.Step, points[len(points)-1].Step)
+
+	match, err = tr.First("max > 100", 0, tr.StepsCount()-1)
+	require.NoError(t, err)
+	assert.Nil(t, match)
+}
+
+func TestLastWrite(t *testing.T) {
+	tr := newTracker(t, historyCode)
+	last := tr.StepsCount() - 1
+
+	points, err := tr.Changes("max", 0, last)
+	require.NoError(t, err)
+	var lastWrite Point
+	for _, p := range points {
+		if p.Write {
+			lastWrite = p
+		}
+	}
+
+	// на последнем шаге main уже завершена, поэтому запрос на предпоследнем
+	match, err := tr.LastWrite("max", last-1)
+	require.NoError(t, err)
+	require.NotNil(t, match)
+	assert.Equal(t, lastWrite.Step, match.Step)
+	assert.Equal(t, 6, match.Line)
+	assert.Equal(t, 11, match.Value.Int)
+
+	match, err = tr.LastWrite("max", lastWrite.Step-1)
+	require.NoError(t, err)
+	assert.Equal(t, 9, match.Value.Int)
+	assert.Less(t, match.Step, lastWrite.Step)
+
+	match, err = tr.LastWrite("a[3]", last-1)
+	require.NoError(t, err)
+	assert.Equal(t, 11, match.Value.Int)
+	assert.Equal(t, 2, match.Line)
+}
+
+func TestTrackerErrors(t *testing.T) {
+	tr := newTracker(t, historyCode)
+
+	_, err := tr.Changes("max", 0, tr.StepsCount())
+	assert.Error(t, err)
+
+	_, err = tr.Changes("max = 1", 0, 1)
+	assert.Error(t, err)
+
+	_, err = tr.First("i == 3", 5, 2)
+	assert.Error(t, err)
+
+	_, err = tr.LastWrite("max + 1", 3)
+	assert.ErrorContains(t, err, "expected a variable")
+
+	_, err = tr.LastWrite("max", 0)
+	assert.ErrorContains(t, err, "not declared")
+}
@@ -1,18 +1,72 @@
 package interpreter
 
-import "github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+	runtimeinterfaces "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/interfaces"
+)
 
 type VariableDecl struct {
 	converter.VariableDecl
 	IsGlobal bool
 }
 
-type VariableExpr struct {
-	converter.VariableExpr
-	IsLvalue bool
+// lvalue - вычисленная левая часть присваивания: изменяемый объект, его тип,
+// имя и индексы, по которым строится событие изменения. Для элемента кучи задан block
+type lvalue struct {
+	target    runtimeinterfaces.Changeable
+	valueType runtime.Type
+	name      string
+	id        int // идентификатор объявления переменной или массива (0 для элемента кучи)
+	indices   []int
+	block     *runtime.HeapBlock
+}
+
+func (lv lvalue) changedEvent(value runtime.Value) events.Event {
+	if lv.block != nil {
+		return events.HeapElementChanged{Address: lv.block.Address, Ind: lv.indices[0], Value: value}
+	}
+	switch len(lv.indices) {
+	case 0:
+		return events.VarChanged{ID: lv.id, Name: lv.name, Value: value}
+	case 1:
+		return events.ArrayElementChanged{ID: lv.id, Name: lv.name, Ind: lv.indices[0], Value: value}
+	default:
+		return events.Array2DElementChanged{ID: lv.id, Name: lv.name, Ind1: lv.indices[0], Ind2: lv.indices[1], Value: value}
+	}
+}
+
+// readEvent возвращает событие чтения значения lvalue операцией use
+func (lv lvalue) readEvent(value runtime.Value, use events.ReadUse) events.Event {
+	if lv.block != nil {
+		return events.HeapElementRead{Address: lv.block.Address, Ind: lv.indices[0], Value: value, Use: use}
+	}
+	switch len(lv.indices) {
+	case 0:
+		return events.VarRead{ID: lv.id, Name: lv.name, Value: value, Use: use}
+	case 1:
+		return events.ArrayElementRead{ID: lv.id, Name: lv.name, Ind: lv.indices[0], Value: value, Use: use}
+	default:
+		return events.Array2DElementRead{ID: lv.id, Name: lv.name, Ind1: lv.indices[0], Ind2: lv.indices[1], Value: value, Use: use}
+	}
+}
+
+// readValue читает значение lvalue. Для элемента кучи чтение неинициализированного значения
+// сообщается с указанием строки выделения блока
+func (lv lvalue) readValue() (runtime.Value, error) {
+	value, err := lv.target.GetValue()
+	if err != nil && lv.block != nil {
+		return runtime.Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("reading uninitialized element %d of heap block allocated at line %d", lv.indices[0], lv.block.Line))
+	}
+	return value, err
 }
 
-type ArrayAccessExpr struct {
-	converter.ArrayAccessExpr
-	IsLvalue bool
+// typeOf возвращает тип времени выполнения для типа из AST (с учетом уровня указателя)
+func typeOf(t converter.Type) runtime.Type {
+	return runtime.Type(t.BaseType + strings.Repeat("*", t.PointerLevel))
 }
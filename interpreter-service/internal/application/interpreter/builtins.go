@@ -0,0 +1,109 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/builtins"
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+// builtinFunc - реализация встроенной функции. args - значения аргументов, приведенные к типам
+// параметров; аргументы-массивы (char[]) не вычисляются, реализация получает их из выражения вызова
+type builtinFunc func(i *Interpreter, call *converter.CallExpr, args []runtime.Value) (runtime.Value, error)
+
+// builtin - встроенная функция: сигнатура и заголовок из общего реестра и реализация
+type builtin struct {
+	builtins.Signature
+	impl builtinFunc
+}
+
+// builtinImpls - реализации встроенных функций по именам из реестра builtins.
+// Соответствие реестру проверяется тестом, функция без сигнатуры в реестре недоступна программе
+var builtinImpls = map[string]builtinFunc{}
+
+// реализации ссылаются на вычисление выражений, поэтому таблица заполняется в init
+func init() {
+	builtinImpls["strlen"] = (*Interpreter).builtinStrlen
+	builtinImpls["strcpy"] = (*Interpreter).builtinStrcpy
+	builtinImpls["strcmp"] = (*Interpreter).builtinStrcmp
+	builtinImpls["strcat"] = (*Interpreter).builtinStrcat
+
+	builtinImpls["malloc"] = (*Interpreter).builtinMalloc
+	builtinImpls["calloc"] = (*Interpreter).builtinCalloc
+	builtinImpls["realloc"] = (*Interpreter).builtinRealloc
+	builtinImpls["free"] = (*Interpreter).builtinFree
+
+	builtinImpls["abs"] = (*Interpreter).builtinAbs
+	builtinImpls["min"] = (*Interpreter).builtinMin
+	builtinImpls["max"] = (*Interpreter).builtinMax
+	builtinImpls["rand"] = (*Interpreter).builtinRand
+	builtinImpls["srand"] = (*Interpreter).builtinSrand
+	builtinImpls["assert"] = (*Interpreter).builtinAssert
+	builtinImpls["exit"] = (*Interpreter).builtinExit
+}
+
+// lookupBuiltin связывает сигнатуру из реестра builtins с реализацией
+func lookupBuiltin(name string) (builtin, bool) {
+	signature, ok := builtins.Lookup(name)
+	if !ok {
+		return builtin{}, false
+	}
+	impl, ok := builtinImpls[name]
+	if !ok {
+		return builtin{}, false
+	}
+	return builtin{Signature: signature, impl: impl}, true
+}
+
+// executeBuiltinCall вычисляет аргументы встроенной функции, фиксирует вызов событием BuiltinCall
+// и выполняет ее реализацию
+func (i *Interpreter) executeBuiltinCall(expr *converter.CallExpr, fn builtin) (runtime.Value, error) {
+	if len(expr.Arguments) != len(fn.Params) {
+		return runtime.Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("function %s expects %d arguments, got %d", fn.Name, len(fn.Params), len(expr.Arguments)))
+	}
+
+	args := make([]runtime.Value, len(expr.Arguments))
+	eventArgs := make([]*runtime.Value, len(expr.Arguments))
+	for ind, arg := range expr.Arguments {
+		param := fn.Params[ind]
+		if param == builtins.CharArray {
+			continue
+		}
+
+		value, err := i.executeExpression(arg)
+		if err != nil {
+			return runtime.Value{}, err
+		}
+		if param == builtins.Scalar {
+			if value.IsVoid() {
+				return runtime.Value{}, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
+			}
+		} else {
+			value, err = i.convertValue(value, runtime.Type(param))
+			if err != nil {
+				return runtime.Value{}, err
+			}
+		}
+
+		args[ind] = value
+		eventArgs[ind] = runtime.CloneValue(&value)
+	}
+
+	i.addEvents(events.BuiltinCall{Name: fn.Name, Header: fn.Header, Arguments: eventArgs})
+	i.operations.Calls++
+
+	return fn.impl(i, expr, args)
+}
+
+// exitSignal - завершение программы вызовом exit. Передается вверх как ошибка
+// и обрабатывается в ExecuteProgram
+type exitSignal struct {
+	status int
+}
+
+func (e exitSignal) Error() string {
+	return fmt.Sprintf("program exited with status %d", e.status)
+}
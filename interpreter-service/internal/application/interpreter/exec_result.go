@@ -1,5 +1,7 @@
 package interpreter
 
+import "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+
 type ExecSignal int
 
 const (
@@ -11,7 +13,7 @@ const (
 
 type ExecResult struct {
 	Signal ExecSignal
-	Value  *int
+	Value  *runtime.Value
 }
 
 func BreakResult() ExecResult {
@@ -26,6 +28,6 @@ func NormalResult() ExecResult {
 	return ExecResult{Signal: SignalNormal}
 }
 
-func ReturnResult(val *int) ExecResult {
+func ReturnResult(val *runtime.Value) ExecResult {
 	return ExecResult{Signal: SignalReturn, Value: val}
 }
@@ -2,525 +2,684 @@ package interpreter
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
-	runtimeinterfaces "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/interfaces"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
 
-func (i *Interpreter) executeExpression(expr converter.Expr) (interface{}, error) {
+// executeExpression вычисляет выражение. В режиме пошагового вычисления выражений
+// значение каждого подвыражения, кроме литералов, фиксируется отдельным шагом с событием ExprEvaluated
+func (i *Interpreter) executeExpression(expr converter.Expr) (runtime.Value, error) {
+	use := i.readUse
+	i.readUse = ""
+	value, err := i.evaluateExpression(expr, use)
+	if err != nil || !i.exprStepping || isLiteral(expr) {
+		return value, err
+	}
+
+	i.addEvents(events.ExprEvaluated{Loc: expr.GetLocation(), Value: value})
+	i.currentLine, i.stepKind = int(expr.GetLocation().Line), step.KindSubexpression
+	if err := i.addStep(); err != nil {
+		return runtime.Value{}, err
+	}
+
+	return value, nil
+}
+
+// isLiteral сообщает, является ли выражение литералом: его значение видно в исходном коде
+func isLiteral(expr converter.Expr) bool {
+	switch expr.(type) {
+	case *converter.IntLiteral, *converter.FloatLiteral, *converter.CharLiteral, *converter.StringLiteral, *converter.NullLiteral:
+		return true
+	default:
+		return false
+	}
+}
+
+// executeOperand вычисляет операнд операции use: чтение переменной или элемента массива,
+// которым является операнд, в режиме событий чтения отмечается этой операцией
+func (i *Interpreter) executeOperand(expr converter.Expr, use events.ReadUse) (runtime.Value, error) {
+	i.readUse = use
+	return i.executeExpression(expr)
+}
+
+// evaluateExpression вычисляет выражение, значение которого передается операции use
+func (i *Interpreter) evaluateExpression(expr converter.Expr, use events.ReadUse) (runtime.Value, error) {
 	switch e := expr.(type) {
 	case *converter.IntLiteral:
-		return e.Value, nil
+		return runtime.NewIntValue(e.Value), nil
+	case *converter.FloatLiteral:
+		if e.IsFloat {
+			return runtime.NewDoubleValue(e.Value).ConvertTo(runtime.TypeFloat)
+		}
+		return runtime.NewDoubleValue(e.Value), nil
+	case *converter.CharLiteral:
+		return runtime.NewCharValue(e.Value), nil
+	case *converter.StringLiteral:
+		return runtime.Value{}, runtimeerrors.NewErrRuntime("string literal can only initialize a char array or be passed to a string function")
+	case *converter.NullLiteral:
+		return runtime.NullValue(), nil
 	case *converter.VariableExpr:
-		return i.executeExpression(&VariableExpr{VariableExpr: *e, IsLvalue: false})
+		return i.executeVariableExpr(e, use)
 	case *converter.ArrayAccessExpr:
-		return i.executeExpression(&ArrayAccessExpr{ArrayAccessExpr: *e, IsLvalue: false})
-
-	case *VariableExpr:
-		v, err := i.resolveVariable(e.Name)
-		if err != nil {
-			return nil, err
-		}
-		if e.IsLvalue {
-			return v, nil
-		}
-		val, ok := v.(*runtime.Variable)
-		if !ok {
-			return nil, runtimeerrors.NewErrUnexpectedInternalError("no variable as rvalue")
-		}
-		value, err := val.GetValue()
+		lv, err := i.executeArrayAccessExpr(e)
 		if err != nil {
-			return nil, err
+			return runtime.Value{}, err
 		}
-		return value, nil
-	case *ArrayAccessExpr:
-		return i.executeArrayAccessExpr(e)
+		return i.readLvalue(lv, use)
 	case *converter.BinaryExpr:
 		return i.executeBinaryExpr(e)
 	case *converter.AssignmentExpr:
 		return i.executeAssignmentExpr(e)
-	case *converter.ArrayInitExpr:
-		return i.executeArrayInitExpr(e)
 	case *converter.UnaryExpr:
-		return i.executeUnaryExpr(e)
+		return i.executeUnaryExpr(e, use)
 	case *converter.CallExpr:
 		return i.executeCallExpr(e)
+	case *converter.CastExpr:
+		return i.executeCastExpr(e)
+	case *converter.SizeofExpr:
+		return i.executeSizeofExpr(e)
+	case *converter.ArrayInitExpr:
+		return runtime.Value{}, runtimeerrors.NewErrRuntime("array initializer used outside of array declaration")
 	default:
-		return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown expression type %T", expr))
+		return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown expression type %T", expr))
 	}
 }
 
-func (i *Interpreter) executeArrayAccessExpr(a *ArrayAccessExpr) (interface{}, error) {
-	arrayLvalue, err := i.convertToLvalue(a.Array)
+func (i *Interpreter) executeVariableExpr(e *converter.VariableExpr, use events.ReadUse) (runtime.Value, error) {
+	v, err := i.resolveVariable(e.Name)
 	if err != nil {
-		return nil, err
+		if value, ok := i.CallStack.GetCurrentFrame().GetEnumConstant(e.Name); ok {
+			return runtime.NewIntValue(value), nil
+		}
+		return runtime.Value{}, err
 	}
 
-	array, err := i.executeExpression(arrayLvalue)
+	variable, ok := v.(*runtime.Variable)
+	if !ok {
+		return runtime.Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("array %s used as a value", e.Name))
+	}
 
-	if err != nil {
-		return nil, err
+	return i.readLvalue(lvalue{target: variable, valueType: variable.Type, name: e.Name, id: variable.ID}, use)
+}
+
+// readLvalue читает значение lvalue. В режиме событий чтения чтение фиксируется событием
+// с операцией use, которой передается значение
+func (i *Interpreter) readLvalue(lv lvalue, use events.ReadUse) (runtime.Value, error) {
+	value, err := lv.readValue()
+	if err != nil || !i.readEvents {
+		return value, err
+	}
+
+	if use == "" {
+		use = events.ReadValue
+	}
+	i.addEvents(lv.readEvent(value, use))
+	return value, nil
+}
+
+// executeLvalue вычисляет выражение, стоящее слева от присваивания или под ++/--
+func (i *Interpreter) executeLvalue(expr converter.Expr) (lvalue, error) {
+	switch e := expr.(type) {
+	case *converter.VariableExpr:
+		v, err := i.resolveVariable(e.Name)
+		if err != nil {
+			if _, ok := i.CallStack.GetCurrentFrame().GetEnumConstant(e.Name); ok {
+				return lvalue{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("enumeration constant %s is not assignable", e.Name))
+			}
+			return lvalue{}, err
+		}
+		variable, ok := v.(*runtime.Variable)
+		if !ok {
+			return lvalue{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("array %s is not assignable", e.Name))
+		}
+		return lvalue{target: variable, valueType: variable.Type, name: e.Name, id: variable.ID}, nil
+	case *converter.ArrayAccessExpr:
+		return i.executeArrayAccessExpr(e)
+	case *converter.UnaryExpr:
+		if e.Operator != "*" {
+			return lvalue{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("expression with unary %s is not assignable", e.Operator))
+		}
+		pointer, err := i.executeExpression(e.Operand)
+		if err != nil {
+			return lvalue{}, err
+		}
+		return i.heapElement(pointer, 0)
+	default:
+		return lvalue{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("expression %T is not assignable", expr))
 	}
+}
 
-	index, err := i.executeExpression(a.Index)
+func (i *Interpreter) executeArrayAccessExpr(a *converter.ArrayAccessExpr) (lvalue, error) {
+	pointer, isPointer, err := i.executePointerOperand(a.Array)
+	if err != nil {
+		return lvalue{}, err
+	}
+	if isPointer {
+		ind, err := i.executeIndex(a.Index)
+		if err != nil {
+			return lvalue{}, err
+		}
+		lv, err := i.heapElement(pointer, ind)
+		if err != nil {
+			return lvalue{}, err
+		}
+		i.markIndex(a.Index, events.ArrayIndexed{Address: lv.block.Address, Index: lv.indices[0]})
+		i.operations.ArrayAccesses++
+		return lv, nil
+	}
 
+	operand, err := i.executeArrayOperand(a.Array)
 	if err != nil {
-		return nil, err
+		return lvalue{}, err
 	}
 
-	ind, ok := index.(int)
+	ind, err := i.executeIndex(a.Index)
+	if err != nil {
+		return lvalue{}, err
+	}
 
-	if !ok {
-		return nil, runtimeerrors.NewErrUnexpectedInternalError("index is not an intenger")
+	element, err := operand.array.GetElement(ind)
+	if err != nil {
+		return lvalue{}, err
 	}
+	i.markIndex(a.Index, events.ArrayIndexed{Name: operand.name, Dimension: len(operand.indices), Index: ind})
+	i.operations.ArrayAccesses++
+
+	return operand.element(element, ind), nil
+}
+
+// arrayOperand - массив, к элементу которого происходит обращение: одномерный массив или строка
+// двумерного массива name с индексом indices[0]. id - идентификатор объявления массива
+type arrayOperand struct {
+	array   *runtime.Array
+	name    string
+	id      int
+	indices []int
+}
 
-	switch arr := array.(type) {
-	case *runtime.Array:
-		val, err := arr.GetElement(ind)
+// element возвращает lvalue элемента ind массива
+func (op arrayOperand) element(element *runtime.ArrayElement, ind int) lvalue {
+	indices := append(append([]int{}, op.indices...), ind)
+	return lvalue{target: element, valueType: op.array.Type, name: op.name, id: op.id, indices: indices}
+}
+
+// executeArrayOperand вычисляет массив, к элементу которого происходит обращение:
+// сам одномерный массив или строку двумерного массива
+func (i *Interpreter) executeArrayOperand(expr converter.Expr) (arrayOperand, error) {
+	switch e := expr.(type) {
+	case *converter.VariableExpr:
+		v, err := i.resolveVariable(e.Name)
 		if err != nil {
-			return nil, err
+			return arrayOperand{}, err
 		}
-
-		if a.IsLvalue {
-			return val, nil
-		} else {
-			intVal, err := val.GetValue()
-			if err != nil {
-				return nil, err
-			}
-			return intVal, nil
+		switch arr := v.(type) {
+		case *runtime.Array:
+			return arrayOperand{array: arr, name: e.Name, id: arr.ID}, nil
+		case *runtime.Array2D:
+			return arrayOperand{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("array2d %s requires two indices", e.Name))
+		default:
+			return arrayOperand{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("subscripted value %s is not an array", e.Name))
 		}
-	case *runtime.Array2D:
-		val, err := arr.GetArray(ind)
+	case *converter.ArrayAccessExpr:
+		base, ok := e.Array.(*converter.VariableExpr)
+		if !ok {
+			return arrayOperand{}, runtimeerrors.NewErrRuntime("arrays with more than two dimensions are not supported")
+		}
+		v, err := i.resolveVariable(base.Name)
 		if err != nil {
-			return nil, err
+			return arrayOperand{}, err
 		}
-
-		if a.IsLvalue {
-			return val, nil
-		} else {
-			return nil, runtimeerrors.NewErrUnexpectedInternalError("array as rvalue")
+		arr, ok := v.(*runtime.Array2D)
+		if !ok {
+			return arrayOperand{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("subscripted value %s is not a 2d array", base.Name))
+		}
+		ind, err := i.executeIndex(e.Index)
+		if err != nil {
+			return arrayOperand{}, err
 		}
+		row, err := arr.GetArray(ind)
+		if err != nil {
+			return arrayOperand{}, err
+		}
+		i.markIndex(e.Index, events.ArrayIndexed{Name: base.Name, Index: ind})
+		return arrayOperand{array: row, name: base.Name, id: arr.ID, indices: []int{ind}}, nil
 	default:
-		return nil, runtimeerrors.NewErrUnexpectedInternalError("array type mismatch")
+		return arrayOperand{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("expression %T is not an array", expr))
 	}
 }
 
-func (i *Interpreter) executeBinaryExpr(expr *converter.BinaryExpr) (int, error) {
-	leftRvalue, err := i.convertToRvalue(expr.Left)
-
+func (i *Interpreter) executeIndex(expr converter.Expr) (int, error) {
+	index, err := i.executeOperand(expr, events.ReadIndex)
 	if err != nil {
 		return 0, err
 	}
+	return index.AsInt()
+}
 
-	leftValRaw, err := i.executeExpression(leftRvalue)
+func (i *Interpreter) executeBinaryExpr(expr *converter.BinaryExpr) (runtime.Value, error) {
+	use := binaryReadUse(expr.Operator)
+	left, err := i.executeOperand(expr.Left, use)
 	if err != nil {
-		return 0, err
+		return runtime.Value{}, err
 	}
 
-	leftVal, ok := leftValRaw.(int)
-	if !ok {
-		return 0, runtimeerrors.NewErrUnexpectedInternalError("left expression is not int")
+	switch expr.Operator {
+	case "&&", "||":
+		return i.executeLogicalExpr(expr, left)
 	}
 
-	var result int
-	var was bool
+	right, err := i.executeOperand(expr.Right, use)
+	if err != nil {
+		return runtime.Value{}, err
+	}
 
-	switch expr.Operator {
-	case "&&":
-		if leftVal == 0 {
-			result = 0
-			was = true
-		}
-	case "||":
-		if leftVal == 1 {
-			result = 1
-			was = true
-		}
+	if use == events.ReadCompare {
+		i.operations.Comparisons++
 	}
+	return applyBinaryOperator(expr.Operator, left, right)
+}
 
-	if was {
-		return result, nil
+// binaryReadUse возвращает операцию, которой передаются операнды бинарного оператора
+func binaryReadUse(operator string) events.ReadUse {
+	switch operator {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return events.ReadCompare
+	case "&&", "||":
+		return events.ReadValue
+	default:
+		return events.ReadArithmetic
 	}
+}
 
-	rightRvalue, err := i.convertToRvalue(expr.Right)
+// executeLogicalExpr вычисляет && и || с сокращенным вычислением правого операнда
+func (i *Interpreter) executeLogicalExpr(expr *converter.BinaryExpr, left runtime.Value) (runtime.Value, error) {
+	leftTrue, err := left.Truth()
+	if err != nil {
+		return runtime.Value{}, err
+	}
 
+	if expr.Operator == "&&" && !leftTrue {
+		return runtime.NewIntValue(0), nil
+	}
+	if expr.Operator == "||" && leftTrue {
+		return runtime.NewIntValue(1), nil
+	}
+
+	right, err := i.executeExpression(expr.Right)
 	if err != nil {
-		return 0, err
+		return runtime.Value{}, err
 	}
 
-	rightValRaw, err := i.executeExpression(rightRvalue)
+	rightTrue, err := right.Truth()
 	if err != nil {
-		return 0, err
+		return runtime.Value{}, err
 	}
 
-	rightVal, ok := rightValRaw.(int)
-	if !ok {
-		return 0, runtimeerrors.NewErrUnexpectedInternalError("right expression is not int")
+	return boolValue(rightTrue), nil
+}
+
+func applyBinaryOperator(operator string, left, right runtime.Value) (runtime.Value, error) {
+	if left.Type.IsPointer() || right.Type.IsPointer() {
+		return applyPointerOperator(operator, left, right)
+	}
+	if left.Type.IsFloating() || right.Type.IsFloating() {
+		return applyFloatingOperator(operator, runtime.CommonType(left.Type, right.Type), left, right)
 	}
 
-	switch expr.Operator {
+	leftVal, err := left.AsInt()
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	rightVal, err := right.AsInt()
+	if err != nil {
+		return runtime.Value{}, err
+	}
+
+	switch operator {
 	case "+":
-		result = leftVal + rightVal
+		return runtime.NewIntValue(leftVal + rightVal), nil
 	case "-":
-		result = leftVal - rightVal
+		return runtime.NewIntValue(leftVal - rightVal), nil
 	case "*":
-		result = leftVal * rightVal
+		return runtime.NewIntValue(leftVal * rightVal), nil
 	case "/":
 		if rightVal == 0 {
-			return 0, runtimeerrors.NewErrRuntime("division by zero")
+			return runtime.Value{}, runtimeerrors.NewErrRuntime("division by zero")
 		}
-		result = leftVal / rightVal
+		return runtime.NewIntValue(leftVal / rightVal), nil
 	case "%":
 		if rightVal == 0 {
-			return 0, runtimeerrors.NewErrRuntime("modulo by zero")
+			return runtime.Value{}, runtimeerrors.NewErrRuntime("modulo by zero")
 		}
-		result = leftVal % rightVal
+		return runtime.NewIntValue(leftVal % rightVal), nil
 	case "==":
-		if leftVal == rightVal {
-			result = 1
-		} else {
-			result = 0
-		}
+		return boolValue(leftVal == rightVal), nil
 	case "!=":
-		if leftVal != rightVal {
-			result = 1
-		} else {
-			result = 0
-		}
+		return boolValue(leftVal != rightVal), nil
 	case "<":
-		if leftVal < rightVal {
-			result = 1
-		} else {
-			result = 0
-		}
+		return boolValue(leftVal < rightVal), nil
 	case "<=":
-		if leftVal <= rightVal {
-			result = 1
-		} else {
-			result = 0
-		}
+		return boolValue(leftVal <= rightVal), nil
 	case ">":
-		if leftVal > rightVal {
-			result = 1
-		} else {
-			result = 0
-		}
+		return boolValue(leftVal > rightVal), nil
 	case ">=":
-		if leftVal >= rightVal {
-			result = 1
-		} else {
-			result = 0
-		}
-	case "&&":
-		if leftVal != 0 && rightVal != 0 {
-			result = 1
-		} else {
-			result = 0
-		}
-	case "||":
-		if leftVal != 0 || rightVal != 0 {
-			result = 1
-		} else {
-			result = 0
-		}
+		return boolValue(leftVal >= rightVal), nil
 	default:
-		return 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown binary operator: %s", expr.Operator))
+		return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown binary operator: %s", operator))
 	}
-
-	return result, nil
 }
 
-func (i *Interpreter) executeAssignmentExpr(expr *converter.AssignmentExpr) (interface{}, error) {
-	leftLvalue, err := i.convertToLvalue(expr.Left)
-
+// applyFloatingOperator вычисляет бинарный оператор над операндами,
+// приведенными к вещественному типу resultType
+func applyFloatingOperator(operator string, resultType runtime.Type, left, right runtime.Value) (runtime.Value, error) {
+	leftVal, err := left.AsFloat()
 	if err != nil {
-		return 0, err
+		return runtime.Value{}, err
 	}
-
-	leftValRaw, err := i.executeExpression(leftLvalue)
+	rightVal, err := right.AsFloat()
 	if err != nil {
-		return nil, err
+		return runtime.Value{}, err
 	}
 
-	leftVal, ok := leftValRaw.(runtimeinterfaces.Changeable)
-	if !ok {
-		return nil, runtimeerrors.NewErrUnexpectedInternalError("left expression is not lvalue")
+	switch operator {
+	case "+":
+		return floatingResult(leftVal+rightVal, resultType)
+	case "-":
+		return floatingResult(leftVal-rightVal, resultType)
+	case "*":
+		return floatingResult(leftVal*rightVal, resultType)
+	case "/":
+		if rightVal == 0 {
+			return runtime.Value{}, runtimeerrors.NewErrRuntime("division by zero")
+		}
+		return floatingResult(leftVal/rightVal, resultType)
+	case "%":
+		return runtime.Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("invalid operands to binary %% (have %s and %s)", left.Type, right.Type))
+	case "==":
+		return boolValue(leftVal == rightVal), nil
+	case "!=":
+		return boolValue(leftVal != rightVal), nil
+	case "<":
+		return boolValue(leftVal < rightVal), nil
+	case "<=":
+		return boolValue(leftVal <= rightVal), nil
+	case ">":
+		return boolValue(leftVal > rightVal), nil
+	case ">=":
+		return boolValue(leftVal >= rightVal), nil
+	default:
+		return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown binary operator: %s", operator))
 	}
+}
 
-	rightValRaw, err := i.executeExpression(expr.Right)
+// floatingResult приводит результат вещественной операции к типу resultType. Переполнение до бесконечности
+// считается неопределенным поведением: такие значения не представимы в снимках
+func floatingResult(f float64, resultType runtime.Type) (runtime.Value, error) {
+	if resultType == runtime.TypeFloat {
+		f = float64(float32(f))
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return runtime.Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("floating-point overflow: result is out of range of %s", resultType))
+	}
+	return runtime.NewDoubleValue(f).ConvertTo(resultType)
+}
+
+// compoundOperators сопоставляет составное присваивание с бинарным оператором
+var compoundOperators = map[string]string{
+	"+=": "+",
+	"-=": "-",
+	"*=": "*",
+	"/=": "/",
+	"%=": "%",
+}
+
+func (i *Interpreter) executeAssignmentExpr(expr *converter.AssignmentExpr) (runtime.Value, error) {
+	lv, err := i.executeLvalue(expr.Left)
 	if err != nil {
-		return nil, err
+		return runtime.Value{}, err
 	}
 
-	rightVal, ok := rightValRaw.(int)
-	if !ok {
-		return nil, runtimeerrors.NewErrUnexpectedInternalError("right expression is not int")
+	use := events.ReadArithmetic
+	if expr.Operator == "=" {
+		use = events.ReadValue
+	}
+	right, err := i.executeOperand(expr.Right, use)
+	if err != nil {
+		return runtime.Value{}, err
 	}
 
-	var newValue int
-	switch expr.Operator {
-	case "=":
-		newValue = rightVal
-		leftVal.ChangeValue(newValue, i.currentStepNumber)
-	case "+=":
-		curVal, err := leftVal.GetValue()
-		if err != nil {
-			return nil, err
-		}
-		newValue = curVal + rightVal
-		leftVal.ChangeValue(newValue, i.currentStepNumber)
-	case "-=":
-		curVal, err := leftVal.GetValue()
-		if err != nil {
-			return nil, err
-		}
-		newValue = curVal - rightVal
-		leftVal.ChangeValue(newValue, i.currentStepNumber)
-	case "*=":
-		curVal, err := leftVal.GetValue()
-		if err != nil {
-			return nil, err
+	var newValue runtime.Value
+	if expr.Operator == "=" {
+		newValue = right
+	} else {
+		operator, ok := compoundOperators[expr.Operator]
+		if !ok {
+			return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown assignment operator: %s", expr.Operator))
 		}
-		newValue = curVal * rightVal
-		leftVal.ChangeValue(newValue, i.currentStepNumber)
-	case "/=":
-		curVal, err := leftVal.GetValue()
+		current, err := i.readLvalue(lv, events.ReadArithmetic)
 		if err != nil {
-			return nil, err
-		}
-		if rightVal == 0 {
-			return nil, runtimeerrors.NewErrRuntime("division by zero")
+			return runtime.Value{}, err
 		}
-		newValue = curVal / rightVal
-		leftVal.ChangeValue(newValue, i.currentStepNumber)
-	case "%=":
-		curVal, err := leftVal.GetValue()
+		newValue, err = applyBinaryOperator(operator, current, right)
 		if err != nil {
-			return nil, err
-		}
-		if rightVal == 0 {
-			return nil, runtimeerrors.NewErrRuntime("division by zero")
+			return runtime.Value{}, err
 		}
-		newValue = curVal % rightVal
-		leftVal.ChangeValue(newValue, i.currentStepNumber)
-	default:
-		return nil, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown assignment operator: %s", expr.Operator))
 	}
 
-	switch lhs := expr.Left.(type) {
-	case *converter.VariableExpr:
-		i.addEvents(events.VarChanged{Name: lhs.Name, Value: newValue})
-	case *converter.ArrayAccessExpr:
+	return i.assignLvalue(lv, newValue)
+}
 
-		indices := []int{}
-		current := lhs
+// assignLvalue записывает значение в lvalue, неявно приводя его к типу lvalue
+func (i *Interpreter) assignLvalue(lv lvalue, value runtime.Value) (runtime.Value, error) {
+	converted, err := i.convertValue(value, lv.valueType)
+	if err != nil {
+		return runtime.Value{}, err
+	}
 
-		for {
-			indexVal, err := i.executeExpression(current.Index)
-			if err != nil {
-				break
-			}
-			if idx, ok := indexVal.(int); ok {
-				indices = append([]int{idx}, indices...)
-			}
+	lv.target.ChangeValue(converted, i.currentStepNumber)
+	i.addEvents(lv.changedEvent(converted))
+	i.operations.Assignments++
 
-			switch arr := current.Array.(type) {
-			case *converter.ArrayAccessExpr:
-				current = arr
-			default:
-				goto addEvent
-			}
-		}
+	return converted, nil
+}
 
-	addEvent:
-		arrayName := getArrayName(lhs.Array)
-		switch len(indices) {
-		case 1:
-			i.addEvents(events.ArrayElementChanged{Name: arrayName, Ind: indices[0], Value: newValue})
-		case 2:
-			i.addEvents(events.Array2DElementChanged{Name: arrayName, Ind1: indices[0], Ind2: indices[1], Value: newValue})
-		}
+// convertValue неявно приводит значение к типу t (присваивание, передача аргумента, return).
+// Преобразование фиксируется событием ImplicitConversion, если меняется вид типа
+// (целый <-> вещественный) или само значение (усечение, потеря точности)
+func (i *Interpreter) convertValue(value runtime.Value, t runtime.Type) (runtime.Value, error) {
+	if value.IsVoid() {
+		return runtime.Value{}, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
+	}
+	if value.Type == t {
+		return value, nil
+	}
+
+	converted, err := i.convert(value, t)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+
+	from, _ := value.AsFloat()
+	to, _ := converted.AsFloat()
+	if value.Type.IsFloating() != t.IsFloating() || from != to {
+		i.addEvents(events.ImplicitConversion{From: value, To: converted})
 	}
 
-	return nil, nil
+	return converted, nil
 }
 
-func (i *Interpreter) executeArrayInitExpr(expr *converter.ArrayInitExpr) (interface{}, error) {
-	interfaceSlice := make([]interface{}, len(expr.Elements))
+// executeArrayInitExpr вычисляет элементы списка инициализации одномерного массива
+func (i *Interpreter) executeArrayInitExpr(expr *converter.ArrayInitExpr) ([]runtime.Value, error) {
+	values := make([]runtime.Value, len(expr.Elements))
 	for ind, element := range expr.Elements {
+		if _, nested := element.(*converter.ArrayInitExpr); nested {
+			return nil, runtimeerrors.NewErrRuntime("unexpected nested initializer list")
+		}
 		val, err := i.executeExpression(element)
 		if err != nil {
 			return nil, err
 		}
-		interfaceSlice[ind] = val
-	}
-
-	switch interfaceSlice[0].(type) {
-	case int:
-		arrayElementSlice := make([]runtime.ArrayElement, len(expr.Elements))
-		for ind, element := range interfaceSlice {
-			val, ok := element.(int)
-			if !ok {
-				valPtr, ok := element.(*int)
-				if !ok {
-					return nil, runtimeerrors.NewErrUnexpectedInternalError("array element is not int")
-				}
-				arrayElementSlice[ind] = *runtime.NewArrayElement(valPtr, 0, false)
-			} else {
-				arrayElementSlice[ind] = *runtime.NewArrayElement(&val, 0, false)
-			}
+		if val.IsVoid() {
+			return nil, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
 		}
-		return arrayElementSlice, nil
-	case []runtime.ArrayElement:
-		arraySlice := make([]runtime.Array, len(expr.Elements))
-		for ind, element := range interfaceSlice {
-			val, ok := element.([]runtime.ArrayElement)
-			if !ok {
-				return nil, runtimeerrors.NewErrUnexpectedInternalError("array2d element is not slice of array elements")
-			} else {
-				arraySlice[ind] = *runtime.NewArray("", len(val), val, 0, false)
-			}
-		}
-		return arraySlice, nil
-	default:
-		return nil, runtimeerrors.NewErrUnexpectedInternalError("unexpected array element type")
+		values[ind] = val
 	}
+	return values, nil
 }
 
-func (i *Interpreter) executeUnaryExpr(expr *converter.UnaryExpr) (int, error) {
-	if expr.Operator == "!" || expr.Operator == "+" || expr.Operator == "-" {
-		operandRvalue, err := i.convertToRvalue(expr.Operand)
-		if err != nil {
-			return 0, err
+func (i *Interpreter) executeUnaryExpr(expr *converter.UnaryExpr, use events.ReadUse) (runtime.Value, error) {
+	switch expr.Operator {
+	case "!", "+", "-":
+		operandUse := events.ReadArithmetic
+		if expr.Operator == "!" {
+			operandUse = events.ReadValue
 		}
-
-		operand, err := i.executeExpression(operandRvalue)
+		operand, err := i.executeOperand(expr.Operand, operandUse)
 		if err != nil {
-			return 0, err
-		}
-
-		v, ok := operand.(int)
-
-		if !ok {
-			return 0, runtimeerrors.NewErrUnexpectedInternalError("unary operator ! get non int value")
+			return runtime.Value{}, err
 		}
 
 		switch expr.Operator {
 		case "!":
-			if v == 0 {
-				return 1, nil
+			truth, err := operand.Truth()
+			if err != nil {
+				return runtime.Value{}, err
 			}
-			return 0, nil
+			return boolValue(!truth), nil
 		case "-":
-			return -v, nil
-		case "+":
-			return v, nil
+			if operand.Type.IsPointer() {
+				return runtime.Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("wrong type argument to unary minus (have %s)", operand.Type))
+			}
+			return applyBinaryOperator("-", runtime.ZeroValue(operand.Type), operand)
 		default:
-			return 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown unary operator: %s", expr.Operator))
+			if _, err := operand.AsFloat(); err != nil {
+				return runtime.Value{}, err
+			}
+			return operand, nil
 		}
-	} else {
-		operandLvalue, err := i.convertToLvalue(expr.Operand)
+	case "*":
+		lv, err := i.executeLvalue(expr)
+		if err != nil {
+			return runtime.Value{}, err
+		}
+		return i.readLvalue(lv, use)
+	case "++", "--":
+		lv, err := i.executeLvalue(expr.Operand)
 		if err != nil {
-			return 0, err
+			return runtime.Value{}, err
 		}
 
-		operand, err := i.executeExpression(operandLvalue)
+		oldValue, err := i.readLvalue(lv, events.ReadArithmetic)
 		if err != nil {
-			return 0, err
+			return runtime.Value{}, err
 		}
 
-		operandVal, ok := operand.(runtimeinterfaces.Changeable)
+		operator := "+"
+		if expr.Operator == "--" {
+			operator = "-"
+		}
 
-		if !ok {
-			return 0, runtimeerrors.NewErrUnexpectedInternalError("unary operator ++ or -- get non lvalue")
+		newValue, err := applyBinaryOperator(operator, oldValue, runtime.NewIntValue(1))
+		if err != nil {
+			return runtime.Value{}, err
 		}
 
-		old, err := operandVal.GetValue()
+		newValue, err = i.assignLvalue(lv, newValue)
 		if err != nil {
-			return 0, err
+			return runtime.Value{}, err
 		}
 
-		switch expr.Operator {
-		case "++":
-			newValue := old + 1
-			operandVal.ChangeValue(newValue, i.currentStepNumber)
-			i.addUnaryOpChangeEvent(expr.Operand, newValue)
-			if expr.IsPostfix {
-				return old, nil
-			} else {
-				return newValue, nil
-			}
-		case "--":
-			newValue := old - 1
-			operandVal.ChangeValue(newValue, i.currentStepNumber)
-			i.addUnaryOpChangeEvent(expr.Operand, newValue)
-			if expr.IsPostfix {
-				return old, nil
-			} else {
-				return newValue, nil
-			}
-		default:
-			return 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown unary operator: %s", expr.Operator))
+		if expr.IsPostfix {
+			return oldValue, nil
 		}
+		return newValue, nil
+	default:
+		return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown unary operator: %s", expr.Operator))
 	}
 }
 
-func (i *Interpreter) executeCallExpr(expr *converter.CallExpr) (interface{}, error) {
+func (i *Interpreter) executeCallExpr(expr *converter.CallExpr) (runtime.Value, error) {
 	declNode, ok := i.Functions[expr.FunctionName]
 
 	if !ok {
-		return nil, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown function named: %s", expr.FunctionName))
+		if fn, builtin := lookupBuiltin(expr.FunctionName); builtin {
+			return i.executeBuiltinCall(expr, fn)
+		}
+		return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown function named: %s", expr.FunctionName))
 	}
 
 	if len(expr.Arguments) != len(declNode.Parameters) {
-		return nil, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("function %s expects %d arguments, got %d", expr.FunctionName, len(declNode.Parameters), len(expr.Arguments)))
+		return runtime.Value{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("function %s expects %d arguments, got %d", expr.FunctionName, len(declNode.Parameters), len(expr.Arguments)))
 	}
 
-	argumentValues := make([]int, len(expr.Arguments))
-	for ind, val := range expr.Arguments {
-		val, err := i.executeExpression(val)
+	argumentValues := make([]runtime.Value, len(expr.Arguments))
+	for ind, arg := range expr.Arguments {
+		value, err := i.executeExpression(arg)
 		if err != nil {
-			return nil, err
+			return runtime.Value{}, err
 		}
-
-		value, ok := val.(int)
-		if !ok {
-			return nil, runtimeerrors.NewErrUnexpectedInternalError("function argument is not an integer")
+		if value.IsVoid() {
+			return runtime.Value{}, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
 		}
 		argumentValues[ind] = value
 	}
 
-	i.addEvents(events.FunctionCall{Name: expr.FunctionName})
-	i.addEvents(events.EnterScope{})
-
+	call := events.FunctionCall{Name: expr.FunctionName}
+	if expr.Loc.Line != 0 {
+		loc := expr.Loc
+		call.Loc = &loc
+	}
+	for ind, param := range declNode.Parameters {
+		call.Arguments = append(call.Arguments, events.Argument{Name: param.Name, Value: argumentValues[ind]})
+	}
+	i.addEvents(call)
+	if len(i.callLines) > 0 {
+		// вызов main из ExecuteProgram не считается операцией программы
+		i.operations.Calls++
+	}
 	i.CallStack.PushFrame(runtime.NewStackFrame(expr.FunctionName, i.GlobalScope))
-	i.CallStack.GetCurrentFrame().EnterScope()
-
-	parameters := make([]*runtime.Variable, len(declNode.Parameters))
+	i.callLines = append(i.callLines, int(expr.Loc.Line))
+	i.enterScope(scopeInfo(runtime.ScopeFunction, declNode.Loc, declNode.Body.Loc))
 
-	for ind, val := range declNode.Parameters {
+	for ind, param := range declNode.Parameters {
 		if err := i.LimitManager.AllocateVariable(); err != nil {
-			return nil, err
+			return runtime.Value{}, err
+		}
+
+		enum, err := i.resolveEnum(param.Type)
+		if err != nil {
+			return runtime.Value{}, err
 		}
 
-		variable := runtime.NewVariable(val.Name, nil, i.currentStepNumber, false)
-		parameters[ind] = variable
-		i.addEvents(events.DeclareVar{Name: val.Name, IsGlobal: false})
+		paramType := typeOf(param.Type)
+		variable := runtime.NewVariable(param.Name, paramType, nil, i.currentStepNumber, false)
+		variable.ID = i.nextDeclarationID()
+		variable.Enum = enum
+		i.addEvents(events.DeclareVar{ID: variable.ID, Name: param.Name, Type: paramType, IsGlobal: false, Enum: enum})
 
 		frame := i.CallStack.GetCurrentFrame()
 		frame.GetCurrentScope().Declare(variable)
 
-		parameters[ind].ChangeValue(argumentValues[ind], i.currentStepNumber)
-		i.addEvents(events.VarChanged{Name: val.Name, Value: argumentValues[ind]})
+		if _, err := i.assignLvalue(lvalue{target: variable, valueType: paramType, name: param.Name, id: variable.ID}, argumentValues[ind]); err != nil {
+			return runtime.Value{}, err
+		}
 	}
 
-	res, err := i.executeStatement(declNode.Body)
+	res, err := i.executeBlock(declNode.Body, runtime.ScopeBody)
 	if err != nil {
-		return nil, err
+		return runtime.Value{}, err
+	}
+	i.callLines = i.callLines[:len(i.callLines)-1]
+	if res.Signal != SignalReturn {
+		// функция завершилась без return: закрывается область видимости параметров
+		if err := i.exitFrameScopes(); err != nil {
+			return runtime.Value{}, err
+		}
 	}
 
 	line := int(expr.Loc.Line)
@@ -529,91 +688,35 @@ func (i *Interpreter) executeCallExpr(expr *converter.CallExpr) (interface{}, er
 	}
 
 	i.addEvents(events.LineChanged{Line: line})
+	i.currentLine, i.stepKind = line, step.KindCallReturn
+	if line != -1 {
+		i.addEvents(events.RangeChanged{Loc: expr.Loc})
+	} else {
+		i.stepKind = step.KindProgramEnd
+	}
 	i.CallStack.PopFrame()
 
 	if res.Signal == SignalReturn {
 		if err := i.addStep(); err != nil {
-			return nil, err
+			return runtime.Value{}, err
 		}
 		if res.Value == nil {
-			return nil, nil
+			return runtime.VoidValue(), nil
 		}
 		return *res.Value, nil
-	} else {
-		i.addEvents(events.FunctionReturn{Name: expr.FunctionName, ReturnValue: nil})
-		if err := i.addStep(); err != nil {
-			return nil, err
-		}
 	}
 
-	return nil, nil
-}
-
-func (i *Interpreter) convertToLvalue(expr converter.Expr) (converter.Expr, error) {
-	switch e := expr.(type) {
-	case *converter.VariableExpr:
-		return &VariableExpr{*e, true}, nil
-	case *converter.ArrayAccessExpr:
-		return &ArrayAccessExpr{*e, true}, nil
-	default:
-		return nil, runtimeerrors.NewErrUnexpectedInternalError("unexpected lvalue type")
+	i.addEvents(events.FunctionReturn{Name: expr.FunctionName, ReturnValue: nil, CallerLine: int(expr.Loc.Line)})
+	if err := i.addStep(); err != nil {
+		return runtime.Value{}, err
 	}
-}
 
-func (i *Interpreter) convertToRvalue(expr converter.Expr) (converter.Expr, error) {
-	switch e := expr.(type) {
-	case *converter.VariableExpr:
-		return &VariableExpr{*e, false}, nil
-	case *converter.ArrayAccessExpr:
-		return &ArrayAccessExpr{*e, false}, nil
-	default:
-		return e, nil
-	}
+	return runtime.VoidValue(), nil
 }
 
-func getArrayName(expr converter.Expr) string {
-	switch e := expr.(type) {
-	case *converter.VariableExpr:
-		return e.Name
-	case *converter.ArrayAccessExpr:
-		return getArrayName(e.Array)
-	default:
-		return ""
-	}
-}
-
-func (i *Interpreter) addUnaryOpChangeEvent(expr converter.Expr, newValue int) {
-	switch e := expr.(type) {
-	case *converter.VariableExpr:
-		i.addEvents(events.VarChanged{Name: e.Name, Value: newValue})
-	case *converter.ArrayAccessExpr:
-		indices := []int{}
-		current := e
-
-		for {
-			indexVal, err := i.executeExpression(current.Index)
-			if err != nil {
-				break
-			}
-			if idx, ok := indexVal.(int); ok {
-				indices = append([]int{idx}, indices...)
-			}
-
-			switch arr := current.Array.(type) {
-			case *converter.ArrayAccessExpr:
-				current = arr
-			default:
-				goto addEvent
-			}
-		}
-
-	addEvent:
-		arrayName := getArrayName(e.Array)
-		switch len(indices) {
-		case 1:
-			i.addEvents(events.ArrayElementChanged{Name: arrayName, Ind: indices[0], Value: newValue})
-		case 2:
-			i.addEvents(events.Array2DElementChanged{Name: arrayName, Ind1: indices[0], Ind2: indices[1], Value: newValue})
-		}
+func boolValue(b bool) runtime.Value {
+	if b {
+		return runtime.NewIntValue(1)
 	}
+	return runtime.NewIntValue(0)
 }
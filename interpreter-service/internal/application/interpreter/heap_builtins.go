@@ -0,0 +1,126 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+// builtinMalloc выделяет неинициализированный блок; аргументы приведены к int сигнатурой из реестра
+func (i *Interpreter) builtinMalloc(call *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	return i.allocateHeap(args[0].Int, int(call.Loc.Line), false)
+}
+
+// builtinCalloc выделяет блок из count элементов размера size, заполненный нулями
+func (i *Interpreter) builtinCalloc(call *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	count, size := args[0].Int, args[1].Int
+	if count <= 0 || size <= 0 || count > runtime.MaxHeapBlockSize/size {
+		return runtime.NullValue(), nil
+	}
+	return i.allocateHeap(count*size, int(call.Loc.Line), true)
+}
+
+func (i *Interpreter) builtinRealloc(call *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	return i.reallocateHeap(call.FunctionName, args[0], args[1].Int, int(call.Loc.Line))
+}
+
+func (i *Interpreter) builtinFree(call *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	return runtime.VoidValue(), i.freeHeap(call.FunctionName, args[0])
+}
+
+// allocateHeap выделяет нетипизированный блок и возвращает указатель void* на него.
+// Как и настоящий malloc, при невозможности выделить блок возвращается NULL
+func (i *Interpreter) allocateHeap(size, line int, zeroed bool) (runtime.Value, error) {
+	if size <= 0 || size > runtime.MaxHeapBlockSize {
+		return runtime.NullValue(), nil
+	}
+	if err := i.LimitManager.AllocateHeap(size); err != nil {
+		return runtime.Value{}, err
+	}
+
+	address := i.Heap.NextAddress()
+	i.Heap.Allocate(address, size, line, zeroed)
+	i.addEvents(events.HeapAlloc{Address: address, Size: size, Line: line, Zeroed: zeroed})
+
+	return runtime.NewPointerValue(runtime.TypeVoidPtr, address), nil
+}
+
+// reallocateHeap выделяет новый блок того же типа, копирует в него значения и освобождает старый
+func (i *Interpreter) reallocateHeap(fn string, pointer runtime.Value, size, line int) (runtime.Value, error) {
+	old, err := i.heapBlockStart(fn, pointer)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	if old == nil {
+		return i.allocateHeap(size, line, false)
+	}
+	if size <= 0 || size > runtime.MaxHeapBlockSize {
+		return runtime.NullValue(), nil
+	}
+	if err := i.LimitManager.AllocateHeap(size); err != nil {
+		return runtime.Value{}, err
+	}
+
+	address := i.Heap.NextAddress()
+	block := i.Heap.Allocate(address, size, line, false)
+	if old.Type != "" {
+		if err := block.SetType(old.Type, i.currentStepNumber); err != nil {
+			return runtime.Value{}, err
+		}
+		block.CopyFrom(old, i.currentStepNumber)
+	}
+	i.addEvents(events.HeapAlloc{Address: address, Size: size, Line: line, Type: old.Type, CopyFrom: old.Address})
+
+	i.Heap.Free(old)
+	i.addEvents(events.HeapFree{Address: old.Address})
+
+	return runtime.NewPointerValue(runtime.TypeVoidPtr, address), nil
+}
+
+func (i *Interpreter) freeHeap(fn string, pointer runtime.Value) error {
+	block, err := i.heapBlockStart(fn, pointer)
+	if err != nil || block == nil {
+		return err
+	}
+
+	i.Heap.Free(block)
+	i.addEvents(events.HeapFree{Address: block.Address})
+
+	return nil
+}
+
+// heapBlockStart возвращает блок, началом которого является указатель, или nil для NULL.
+// Повторное освобождение и указатель не на начало блока - неопределенное поведение
+func (i *Interpreter) heapBlockStart(fn string, pointer runtime.Value) (*runtime.HeapBlock, error) {
+	if pointer.Int == 0 {
+		return nil, nil
+	}
+
+	block, ok := i.Heap.Find(pointer.Int)
+	if !ok || block.Address != pointer.Int {
+		return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("%s: pointer %s was not returned by malloc", fn, runtime.FormatAddress(pointer.Int)))
+	}
+	if block.Freed {
+		return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("%s: double free of heap block allocated at line %d", fn, block.Line))
+	}
+
+	return block, nil
+}
+
+// reportLeaks добавляет шаг со списком блоков, не освобожденных к завершению main
+func (i *Interpreter) reportLeaks() error {
+	if len(i.Heap.Blocks) == 0 {
+		return nil
+	}
+
+	for _, block := range i.Heap.Blocks {
+		i.addEvents(events.HeapLeak{Address: block.Address, Size: block.Size, Line: block.Line})
+	}
+	i.stepKind = step.KindProgramEnd
+
+	return i.addStep()
+}
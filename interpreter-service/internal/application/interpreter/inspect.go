@@ -0,0 +1,134 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+)
+
+// Inspect вычисляет выражение на состоянии программы из снимка (в текущем кадре стека).
+// Выражение не должно менять состояние: присваивания, ++/-- и вызовы функций запрещены
+func Inspect(sn *snapshot.Snapshot, expr converter.Expr) (runtime.Value, error) {
+	if err := CheckInspectable(expr); err != nil {
+		return runtime.Value{}, err
+	}
+
+	i := inspector(sn)
+	if err := i.checkDeclared(expr); err != nil {
+		return runtime.Value{}, err
+	}
+	return i.executeExpression(expr)
+}
+
+// InspectLocation вычисляет изменяемый объект (переменную, элемент массива или кучи) на состоянии
+// из снимка и возвращает его значение и номер шага, на котором объект был изменен последний раз
+func InspectLocation(sn *snapshot.Snapshot, expr converter.Expr) (runtime.Value, int, error) {
+	if err := CheckInspectable(expr); err != nil {
+		return runtime.Value{}, 0, err
+	}
+
+	i := inspector(sn)
+	if err := i.checkDeclared(expr); err != nil {
+		return runtime.Value{}, 0, err
+	}
+
+	lv, err := i.executeLvalue(expr)
+	if err != nil {
+		return runtime.Value{}, 0, err
+	}
+
+	var stepChanged int
+	switch target := lv.target.(type) {
+	case *runtime.Variable:
+		stepChanged = target.StepChanged
+	case *runtime.ArrayElement:
+		stepChanged = target.StepChanged
+	default:
+		return runtime.Value{}, 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown location type %T", lv.target))
+	}
+
+	value, err := lv.readValue()
+	return value, stepChanged, err
+}
+
+// CheckInspectable проверяет, что выражение можно вычислить, не меняя состояние программы
+func CheckInspectable(expr converter.Expr) error {
+	switch e := expr.(type) {
+	case *converter.AssignmentExpr:
+		return runtimeerrors.NewErrRuntime("assignment is not allowed in an inspected expression")
+	case *converter.CallExpr:
+		return runtimeerrors.NewErrRuntime(fmt.Sprintf("call of %s is not allowed in an inspected expression", e.FunctionName))
+	case *converter.UnaryExpr:
+		if e.Operator == "++" || e.Operator == "--" {
+			return runtimeerrors.NewErrRuntime(fmt.Sprintf("operator %s is not allowed in an inspected expression", e.Operator))
+		}
+		return CheckInspectable(e.Operand)
+	case *converter.BinaryExpr:
+		if err := CheckInspectable(e.Left); err != nil {
+			return err
+		}
+		return CheckInspectable(e.Right)
+	case *converter.ArrayAccessExpr:
+		if err := CheckInspectable(e.Array); err != nil {
+			return err
+		}
+		return CheckInspectable(e.Index)
+	case *converter.CastExpr:
+		// приведение void* к указателю задает тип блока кучи
+		if e.TargetType.PointerLevel > 0 {
+			return runtimeerrors.NewErrRuntime("pointer cast is not allowed in an inspected expression")
+		}
+		return CheckInspectable(e.Operand)
+	case *converter.SizeofExpr:
+		return nil
+	case *converter.ArrayInitExpr:
+		return runtimeerrors.NewErrRuntime("array initializer is not allowed in an inspected expression")
+	default:
+		return nil
+	}
+}
+
+// checkDeclared проверяет, что все имена в выражении видны в текущем кадре стека
+func (i *Interpreter) checkDeclared(expr converter.Expr) error {
+	switch e := expr.(type) {
+	case *converter.VariableExpr:
+		if _, err := i.resolveVariable(e.Name); err == nil {
+			return nil
+		}
+		if _, ok := i.CallStack.GetCurrentFrame().GetEnumConstant(e.Name); ok {
+			return nil
+		}
+		return runtimeerrors.NewErrRuntime(fmt.Sprintf("%s is not declared in the current scope", e.Name))
+	case *converter.UnaryExpr:
+		return i.checkDeclared(e.Operand)
+	case *converter.BinaryExpr:
+		if err := i.checkDeclared(e.Left); err != nil {
+			return err
+		}
+		return i.checkDeclared(e.Right)
+	case *converter.ArrayAccessExpr:
+		if err := i.checkDeclared(e.Array); err != nil {
+			return err
+		}
+		return i.checkDeclared(e.Index)
+	case *converter.CastExpr:
+		return i.checkDeclared(e.Operand)
+	case *converter.SizeofExpr:
+		if e.Operand == nil {
+			return nil
+		}
+		return i.checkDeclared(e.Operand)
+	default:
+		return nil
+	}
+}
+
+// inspector возвращает интерпретатор, работающий с состоянием снимка
+func inspector(sn *snapshot.Snapshot) *Interpreter {
+	i := NewInterpreter()
+	i.CallStack, i.GlobalScope, i.Heap = sn.CallStack, sn.GlobalScope, sn.Heap
+	return i
+}
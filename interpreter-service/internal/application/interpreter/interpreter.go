@@ -19,6 +19,7 @@ const (
 type Interpreter struct {
 	CallStack         *runtime.CallStack
 	GlobalScope       *runtime.Scope
+	Heap              *runtime.Heap
 	Functions         map[string]*converter.FunctionDecl
 	LimitManager      limitations.LimitManager
 	currentStepNumber int
@@ -27,6 +28,18 @@ type Interpreter struct {
 	Steps             []step.Step
 	maxAllocated      int
 	maxSteps          int
+	randState         uint32         // состояние генератора rand
+	exprStepping      bool           // пошаговое вычисление выражений
+	readEvents        bool           // события чтения переменных и элементов массивов
+	indexMarkers      bool           // маркеры переменных, входящих в индексы массивов
+	branchEvents      bool           // события исходов условий if и циклов
+	readUse           events.ReadUse // операция, которой передается значение следующего вычисляемого выражения
+	lastDeclarationID int            // идентификатор последнего объявления переменной или массива
+	callLines         []int          // строки вызовов функций, кадры которых на стеке (0 для main)
+	operations        OperationCounts
+	stepKind          step.Kind
+	mainStepBegin     int // номер первого шага main, -1 до вызова main
+	digests           *elementDigests
 }
 
 func NewInterpreter() *Interpreter {
@@ -52,6 +65,26 @@ func NewInterpreterWithLimits(maxAllocatedElements int, maxSteps int) *Interpret
 	return interpreter
 }
 
+// SetExpressionStepping включает режим, в котором вычисление каждого подвыражения становится отдельным шагом
+func (i *Interpreter) SetExpressionStepping(enabled bool) {
+	i.exprStepping = enabled
+}
+
+// SetReadEvents включает события чтения (VarRead, ArrayElementRead, ...) при вычислении выражений
+func (i *Interpreter) SetReadEvents(enabled bool) {
+	i.readEvents = enabled
+}
+
+// SetIndexMarkers включает события ArrayIndexed: какие переменные входят в индексы вычисляемых обращений к массивам
+func (i *Interpreter) SetIndexMarkers(enabled bool) {
+	i.indexMarkers = enabled
+}
+
+// SetBranchEvents включает события BranchEvaluated с исходом каждого вычисленного условия if и цикла
+func (i *Interpreter) SetBranchEvents(enabled bool) {
+	i.branchEvents = enabled
+}
+
 func (i *Interpreter) incrementStep() {
 	i.currentStepNumber++
 }
@@ -79,6 +112,7 @@ func (i *Interpreter) resolveVariable(name string) (interface{}, error) {
 
 func (i *Interpreter) addEvents(events ...events.Event) {
 	i.CurrentStep.Events = append(i.CurrentStep.Events, events...)
+	i.digests.observe(events)
 }
 
 func (i *Interpreter) addStep() error {
@@ -87,12 +121,26 @@ func (i *Interpreter) addStep() error {
 	}
 
 	defer i.incrementStep()
+	i.CurrentStep.StepNumber = i.currentStepNumber
+	i.CurrentStep.Depth = i.CallStack.FramesCount() - 1
+	if i.CurrentStep.Depth > 0 {
+		i.CurrentStep.Function = i.CallStack.GetCurrentFrame().FuncName
+	}
+	i.CurrentStep.Line = i.currentLine
+	i.CurrentStep.Kind = i.stepKind
 	i.Steps = append(i.Steps, i.CurrentStep)
 	i.CurrentStep = step.Step{}
 
 	return nil
 }
 
+// stepTo начинает новый шаг на конструкции вида kind, занимающей участок loc исходного кода
+func (i *Interpreter) stepTo(loc converter.Location, kind step.Kind) error {
+	i.addEvents(events.LineChanged{Line: int(loc.Line)}, events.RangeChanged{Loc: loc})
+	i.currentLine, i.stepKind = int(loc.Line), kind
+	return i.addStep()
+}
+
 func (i *Interpreter) resetLimitManager() {
 	i.LimitManager = limitations.LimitManager{
 		AllocatedElementsRemained: i.maxAllocated,
@@ -100,12 +148,28 @@ func (i *Interpreter) resetLimitManager() {
 	}
 }
 
+// nextDeclarationID возвращает новый идентификатор объявления: каждый экземпляр переменной
+// (в том числе параметр рекурсивного вызова) получает свой идентификатор
+func (i *Interpreter) nextDeclarationID() int {
+	i.lastDeclarationID++
+	return i.lastDeclarationID
+}
+
 func (i *Interpreter) resetExecutionState() {
-	i.GlobalScope = runtime.NewScope(nil)
+	i.GlobalScope = runtime.NewGlobalScope()
 	i.CallStack = runtime.NewCallStack(i.GlobalScope)
+	i.Heap = runtime.NewHeap()
+	i.randState = defaultRandSeed
 	i.Functions = make(map[string]*converter.FunctionDecl)
 	i.currentStepNumber = 0
 	i.currentLine = -1
+	i.stepKind = ""
+	i.readUse = ""
+	i.lastDeclarationID = 0
+	i.callLines = nil
+	i.operations = OperationCounts{}
+	i.mainStepBegin = -1
+	i.digests = newElementDigests()
 	i.CurrentStep = step.Step{}
 	i.Steps = nil
 	i.resetLimitManager()
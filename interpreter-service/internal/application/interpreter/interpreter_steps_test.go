@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
 
@@ -82,11 +84,12 @@ func compactDuplicateLineChangedSteps(steps []step.Step, stepBegin int) ([]step.
 }
 
 func extractSingleLineChanged(step step.Step) (int, bool) {
-	if len(step.Events) != 1 {
+	stepEvents := withoutRanges(step.Events)
+	if len(stepEvents) != 1 {
 		return 0, false
 	}
 
-	lineChanged, ok := step.Events[0].(events.LineChanged)
+	lineChanged, ok := stepEvents[0].(events.LineChanged)
 	if !ok {
 		return 0, false
 	}
@@ -110,6 +113,8 @@ func normalizeEvent(event events.Event) string {
 		return "EnterScope"
 	case events.ExitScope:
 		return "ExitScope"
+	case events.VariableDestroyed:
+		return fmt.Sprintf("VariableDestroyed(name=%s)", e.Name)
 	case events.DeclareVar:
 		return fmt.Sprintf("DeclareVar(name=%s,global=%t)", e.Name, e.IsGlobal)
 	case events.DeclareArray:
@@ -117,20 +122,59 @@ func normalizeEvent(event events.Event) string {
 	case events.DeclareArray2D:
 		return fmt.Sprintf("DeclareArray2D(name=%s,size1=%d,size2=%d,global=%t)", e.Name, e.Size1, e.Size2, e.IsGlobal)
 	case events.VarChanged:
-		return fmt.Sprintf("VarChanged(name=%s,value=%d)", e.Name, e.Value)
+		return fmt.Sprintf("VarChanged(name=%s,value=%s)", e.Name, e.Value)
 	case events.ArrayElementChanged:
-		return fmt.Sprintf("ArrayElementChanged(name=%s,ind=%d,value=%d)", e.Name, e.Ind, e.Value)
+		return fmt.Sprintf("ArrayElementChanged(name=%s,ind=%d,value=%s)", e.Name, e.Ind, e.Value)
 	case events.Array2DElementChanged:
-		return fmt.Sprintf("Array2DElementChanged(name=%s,ind1=%d,ind2=%d,value=%d)", e.Name, e.Ind1, e.Ind2, e.Value)
+		return fmt.Sprintf("Array2DElementChanged(name=%s,ind1=%d,ind2=%d,value=%s)", e.Name, e.Ind1, e.Ind2, e.Value)
+	case events.ImplicitConversion:
+		return fmt.Sprintf("ImplicitConversion(from=%s:%s,to=%s:%s)", e.From.Type, e.From, e.To.Type, e.To)
 	case events.FunctionCall:
 		return fmt.Sprintf("FunctionCall(name=%s)", e.Name)
 	case events.FunctionReturn:
 		if e.ReturnValue == nil {
 			return fmt.Sprintf("FunctionReturn(name=%s,value=nil)", e.Name)
 		}
-		return fmt.Sprintf("FunctionReturn(name=%s,value=%d)", e.Name, *e.ReturnValue)
+		return fmt.Sprintf("FunctionReturn(name=%s,value=%s)", e.Name, *e.ReturnValue)
+	case events.BuiltinCall:
+		args := make([]string, len(e.Arguments))
+		for ind, arg := range e.Arguments {
+			args[ind] = "_"
+			if arg != nil {
+				args[ind] = arg.String()
+			}
+		}
+		return fmt.Sprintf("BuiltinCall(name=%s,args=%s)", e.Name, strings.Join(args, ","))
+	case events.AssertionFailed:
+		return fmt.Sprintf("AssertionFailed(message=%s,line=%d)", e.Message, e.Line)
+	case events.InfiniteLoop:
+		return fmt.Sprintf("InfiniteLoop(construct=%s,line=%d,cycle=%d,unchanged=%s)", e.Construct, e.Loc.Line, e.Cycle, strings.Join(e.Unchanged, ","))
+	case events.HeapAlloc:
+		return fmt.Sprintf("HeapAlloc(address=%#x,size=%d,line=%d)", e.Address, e.Size, e.Line)
+	case events.HeapBlockTyped:
+		return fmt.Sprintf("HeapBlockTyped(address=%#x,type=%s)", e.Address, e.Type)
+	case events.HeapFree:
+		return fmt.Sprintf("HeapFree(address=%#x)", e.Address)
+	case events.HeapElementChanged:
+		return fmt.Sprintf("HeapElementChanged(address=%#x,ind=%d,value=%s)", e.Address, e.Ind, e.Value)
+	case events.HeapLeak:
+		return fmt.Sprintf("HeapLeak(address=%#x,size=%d,line=%d)", e.Address, e.Size, e.Line)
+	case events.ExprEvaluated:
+		return fmt.Sprintf("ExprEvaluated(loc=%d:%d-%d:%d,value=%s)", e.Loc.Line, e.Loc.Column, e.Loc.EndLine, e.Loc.EndColumn, e.Value)
+	case events.VarRead:
+		return fmt.Sprintf("VarRead(name=%s,value=%s,use=%s)", e.Name, e.Value, e.Use)
+	case events.ArrayElementRead:
+		return fmt.Sprintf("ArrayElementRead(name=%s,ind=%d,value=%s,use=%s)", e.Name, e.Ind, e.Value, e.Use)
+	case events.Array2DElementRead:
+		return fmt.Sprintf("Array2DElementRead(name=%s,ind1=%d,ind2=%d,value=%s,use=%s)", e.Name, e.Ind1, e.Ind2, e.Value, e.Use)
+	case events.HeapElementRead:
+		return fmt.Sprintf("HeapElementRead(address=%#x,ind=%d,value=%s,use=%s)", e.Address, e.Ind, e.Value, e.Use)
+	case events.ArrayIndexed:
+		return fmt.Sprintf("ArrayIndexed(variable=%s,name=%s,address=%#x,dim=%d,index=%d,offset=%d)", e.Variable, e.Name, e.Address, e.Dimension, e.Index, e.Offset)
 	case events.LineChanged:
 		return fmt.Sprintf("LineChanged(line=%d)", e.Line)
+	case events.RangeChanged:
+		return fmt.Sprintf("RangeChanged(loc=%d:%d-%d:%d)", e.Loc.Line, e.Loc.Column, e.Loc.EndLine, e.Loc.EndColumn)
 	case events.UndefinedBehavior:
 		return fmt.Sprintf("UndefinedBehavior(message=%s)", e.Message)
 	case events.RuntimeError:
@@ -140,11 +184,23 @@ func normalizeEvent(event events.Event) string {
 	}
 }
 
+// withoutRanges убирает события RangeChanged: тесты шагов проверяют строки,
+// участки кода проверяются отдельно
+func withoutRanges(stepEvents []events.Event) []events.Event {
+	filtered := make([]events.Event, 0, len(stepEvents))
+	for _, event := range stepEvents {
+		if _, ok := event.(events.RangeChanged); !ok {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
 func normalizeSteps(steps []step.Step) []normalizedStep {
 	normalized := make([]normalizedStep, 0, len(steps))
 	for _, step := range steps {
 		normalizedEvents := make([]string, 0, len(step.Events))
-		for _, event := range step.Events {
+		for _, event := range withoutRanges(step.Events) {
 			normalizedEvents = append(normalizedEvents, normalizeEvent(event))
 		}
 		normalized = append(normalized, normalizedStep{Events: normalizedEvents})
@@ -173,6 +229,8 @@ func TestInterpreterSteps_MainLifecycleEvents(t *testing.T) {
 			"LineChanged(line=2)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=1)",
 			"LineChanged(line=-1)",
 		}},
@@ -210,6 +268,9 @@ func TestInterpreterSteps_AssignmentEmitsVarChanged(t *testing.T) {
 			"LineChanged(line=4)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=x)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=2)",
 			"LineChanged(line=-1)",
 		}},
@@ -259,6 +320,9 @@ func TestInterpreterSteps_ArraysAndElementChangesWithCondition(t *testing.T) {
 			"LineChanged(line=7)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=arr)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=6)",
 			"LineChanged(line=-1)",
 		}},
@@ -305,7 +369,7 @@ func TestInterpreterSteps_WhileLoopWithIfAndVarChanges(t *testing.T) {
 		{Events: []string{"LineChanged(line=8)"}},
 		{Events: []string{"VarChanged(name=i,value=4)", "ExitScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=10)"}},
-		{Events: []string{"FunctionReturn(name=main,value=2)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=sum)", "VariableDestroyed(name=i)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=2)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -339,10 +403,14 @@ int main() {
 			"LineChanged(line=2)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=foo,value=7)",
 			"LineChanged(line=5)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=7)",
 			"LineChanged(line=-1)",
 		}},
@@ -364,20 +432,23 @@ func TestInterpreterSteps_ForLoopWithVarChanges(t *testing.T) {
 
 	require.NotNil(t, result)
 	assert.Equal(t, 3, *result)
-	require.Len(t, steps, 11)
+	require.Len(t, steps, 14)
 
 	expectedSteps := []normalizedStep{
 		{Events: []string{"FunctionCall(name=main)", "EnterScope", "EnterScope", "LineChanged(line=2)"}},
-		{Events: []string{"DeclareVar(name=sum,global=false)", "LineChanged(line=3)"}},
-		{Events: []string{"EnterScope", "LineChanged(line=3)"}},
-		{Events: []string{"DeclareVar(name=i,global=false)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"DeclareVar(name=sum,global=false)", "EnterScope", "LineChanged(line=3)"}},
+		{Events: []string{"DeclareVar(name=i,global=false)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"VarChanged(name=sum,value=0)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=1)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=1)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"VarChanged(name=sum,value=1)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=2)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=2)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"VarChanged(name=sum,value=3)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=3)", "ExitScope", "LineChanged(line=6)"}},
-		{Events: []string{"FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
+		{Events: []string{"VarChanged(name=i,value=3)", "LineChanged(line=3)"}},
+		{Events: []string{"VariableDestroyed(name=i)", "ExitScope", "LineChanged(line=6)"}},
+		{Events: []string{"VariableDestroyed(name=sum)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -407,7 +478,7 @@ func TestInterpreterSteps_DoWhileLoopWithCondition(t *testing.T) {
 		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"VarChanged(name=i,value=3)", "ExitScope", "LineChanged(line=5)"}},
 		{Events: []string{"LineChanged(line=6)"}},
-		{Events: []string{"FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=i)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -432,7 +503,7 @@ func TestInterpreterSteps_MultiVarDeclarationSingleLine(t *testing.T) {
 		{Events: []string{"DeclareVar(name=b,global=false)", "LineChanged(line=2)"}},
 		{Events: []string{"DeclareVar(name=c,global=false)", "LineChanged(line=3)"}},
 		{Events: []string{"VarChanged(name=c,value=3)", "LineChanged(line=4)"}},
-		{Events: []string{"FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=c)", "VariableDestroyed(name=b)", "VariableDestroyed(name=a)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -483,7 +554,7 @@ func TestInterpreterSteps_ContinueInWhileLoop(t *testing.T) {
 		{Events: []string{"LineChanged(line=9)"}},
 		{Events: []string{"VarChanged(name=sum,value=13)", "ExitScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=11)"}},
-		{Events: []string{"FunctionReturn(name=main,value=13)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=sum)", "VariableDestroyed(name=i)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=13)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -533,7 +604,7 @@ func TestInterpreterSteps_BreakInWhileLoop(t *testing.T) {
 		{Events: []string{"VarChanged(name=i,value=5)", "LineChanged(line=6)"}},
 		{Events: []string{"EnterScope", "LineChanged(line=7)"}},
 		{Events: []string{"ExitScope", "ExitScope", "LineChanged(line=11)"}},
-		{Events: []string{"FunctionReturn(name=main,value=10)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=sum)", "VariableDestroyed(name=i)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=10)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -558,36 +629,41 @@ func TestInterpreterSteps_ForLoopWithContinueAndBreak(t *testing.T) {
 
 	require.NotNil(t, result)
 	assert.Equal(t, 8, *result)
-	require.Len(t, steps, 27)
+	require.Len(t, steps, 32)
 
 	expectedSteps := []normalizedStep{
 		{Events: []string{"FunctionCall(name=main)", "EnterScope", "EnterScope", "LineChanged(line=2)"}},
-		{Events: []string{"DeclareVar(name=sum,global=false)", "LineChanged(line=3)"}},
-		{Events: []string{"EnterScope", "LineChanged(line=3)"}},
-		{Events: []string{"DeclareVar(name=i,global=false)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"DeclareVar(name=sum,global=false)", "EnterScope", "LineChanged(line=3)"}},
+		{Events: []string{"DeclareVar(name=i,global=false)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=7)"}},
 		{Events: []string{"LineChanged(line=10)"}},
 		{Events: []string{"VarChanged(name=sum,value=0)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=1)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=1)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=7)"}},
 		{Events: []string{"LineChanged(line=10)"}},
 		{Events: []string{"VarChanged(name=sum,value=1)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=2)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=2)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"EnterScope", "LineChanged(line=5)"}},
 		{Events: []string{"ExitScope", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=3)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=3)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=7)"}},
 		{Events: []string{"LineChanged(line=10)"}},
 		{Events: []string{"VarChanged(name=sum,value=4)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=4)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=4)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=7)"}},
 		{Events: []string{"LineChanged(line=10)"}},
 		{Events: []string{"VarChanged(name=sum,value=8)", "ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=5)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=5)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"LineChanged(line=7)"}},
 		{Events: []string{"EnterScope", "LineChanged(line=8)"}},
-		{Events: []string{"ExitScope", "ExitScope", "LineChanged(line=12)"}},
-		{Events: []string{"FunctionReturn(name=main,value=8)", "LineChanged(line=-1)"}},
+		{Events: []string{"ExitScope", "ExitScope", "VariableDestroyed(name=i)", "ExitScope", "LineChanged(line=12)"}},
+		{Events: []string{"VariableDestroyed(name=sum)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=8)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -612,12 +688,37 @@ func TestInterpreterSteps_Array2DDeclarationAndElementChanges(t *testing.T) {
 		{Events: []string{"DeclareArray2D(name=m,size1=2,size2=2,global=false)", "LineChanged(line=3)"}},
 		{Events: []string{"Array2DElementChanged(name=m,ind1=1,ind2=0,value=7)", "LineChanged(line=4)"}},
 		{Events: []string{"Array2DElementChanged(name=m,ind1=0,ind2=1,value=8)", "LineChanged(line=5)"}},
-		{Events: []string{"FunctionReturn(name=main,value=8)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=m)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=8)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
 }
 
+func TestInterpreterSteps_StrcpyEmitsElementChangesAndOverflowIsUndefinedBehavior(t *testing.T) {
+	code := `int main() {
+	char s[3];
+	strcpy(s, "hi");
+	strcat(s, "!");
+	return 0;
+}`
+
+	result, steps, _, err := runCodeWithStepsAllowError(t, code)
+
+	assert.Nil(t, result)
+	require.NotNil(t, err)
+
+	normalized := normalizeSteps(steps)
+	require.GreaterOrEqual(t, len(normalized), 3)
+	assert.Equal(t, []string{
+		"BuiltinCall(name=strcpy,args=_,_)",
+		"ArrayElementChanged(name=s,ind=0,value='h' (104))",
+		"ArrayElementChanged(name=s,ind=1,value='i' (105))",
+		"ArrayElementChanged(name=s,ind=2,value='\\0' (0))",
+		"LineChanged(line=4)",
+	}, normalized[len(normalized)-2].Events)
+	assert.Contains(t, normalized[len(normalized)-1].Events, "UndefinedBehavior(message=undefined behavior: strcat: buffer overflow, 4 bytes needed but array s has size 3)")
+}
+
 func TestInterpreterSteps_UndefinedBehaviorOnUninitializedVariableRead(t *testing.T) {
 	code := `int main() {
 	int x;
@@ -654,7 +755,7 @@ int main() {
 	expectedSteps := []normalizedStep{
 		{Events: []string{"LineChanged(line=1)"}},
 		{Events: []string{"DeclareVar(name=g,global=true)", "FunctionCall(name=main)", "EnterScope", "EnterScope", "LineChanged(line=3)"}},
-		{Events: []string{"FunctionReturn(name=main,value=5)", "LineChanged(line=-1)"}},
+		{Events: []string{"ExitScope", "ExitScope", "FunctionReturn(name=main,value=5)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -682,7 +783,7 @@ func TestInterpreterSteps_IfElseFalseBranchEvents(t *testing.T) {
 		{Events: []string{"DeclareVar(name=x,global=false)", "LineChanged(line=3)"}},
 		{Events: []string{"EnterScope", "LineChanged(line=6)"}},
 		{Events: []string{"VarChanged(name=x,value=2)", "ExitScope", "LineChanged(line=8)"}},
-		{Events: []string{"FunctionReturn(name=main,value=2)", "LineChanged(line=-1)"}},
+		{Events: []string{"VariableDestroyed(name=x)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=2)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -701,20 +802,23 @@ func TestInterpreterSteps_ForContinueRunsPostExpression(t *testing.T) {
 
 	require.NotNil(t, result)
 	assert.Equal(t, 3, *result)
-	require.Len(t, steps, 11)
+	require.Len(t, steps, 14)
 
 	expectedSteps := []normalizedStep{
 		{Events: []string{"FunctionCall(name=main)", "EnterScope", "EnterScope", "LineChanged(line=2)"}},
-		{Events: []string{"DeclareVar(name=i,global=false)", "LineChanged(line=3)"}},
-		{Events: []string{"EnterScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=0)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"DeclareVar(name=i,global=false)", "EnterScope", "LineChanged(line=3)"}},
+		{Events: []string{"VarChanged(name=i,value=0)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=1)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=1)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=2)", "EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"VarChanged(name=i,value=2)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
 		{Events: []string{"ExitScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=3)", "ExitScope", "LineChanged(line=6)"}},
-		{Events: []string{"FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
+		{Events: []string{"VarChanged(name=i,value=3)", "LineChanged(line=3)"}},
+		{Events: []string{"ExitScope", "LineChanged(line=6)"}},
+		{Events: []string{"VariableDestroyed(name=i)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=3)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -737,11 +841,11 @@ func TestInterpreterSteps_ForBreakSkipsPostExpression(t *testing.T) {
 
 	expectedSteps := []normalizedStep{
 		{Events: []string{"FunctionCall(name=main)", "EnterScope", "EnterScope", "LineChanged(line=2)"}},
-		{Events: []string{"DeclareVar(name=i,global=false)", "LineChanged(line=3)"}},
-		{Events: []string{"EnterScope", "LineChanged(line=3)"}},
-		{Events: []string{"VarChanged(name=i,value=0)", "EnterScope", "LineChanged(line=4)"}},
-		{Events: []string{"ExitScope", "LineChanged(line=6)"}},
-		{Events: []string{"FunctionReturn(name=main,value=0)", "LineChanged(line=-1)"}},
+		{Events: []string{"DeclareVar(name=i,global=false)", "EnterScope", "LineChanged(line=3)"}},
+		{Events: []string{"VarChanged(name=i,value=0)", "LineChanged(line=3)"}},
+		{Events: []string{"EnterScope", "LineChanged(line=4)"}},
+		{Events: []string{"ExitScope", "ExitScope", "LineChanged(line=6)"}},
+		{Events: []string{"VariableDestroyed(name=i)", "ExitScope", "ExitScope", "FunctionReturn(name=main,value=0)", "LineChanged(line=-1)"}},
 	}
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
@@ -828,14 +932,24 @@ int main() {
 			"LineChanged(line=3)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"ExitScope",
+			"VariableDestroyed(name=n)",
+			"ExitScope",
 			"FunctionReturn(name=factorial,value=1)",
 			"LineChanged(line=5)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"VariableDestroyed(name=n)",
+			"ExitScope",
 			"FunctionReturn(name=factorial,value=2)",
 			"LineChanged(line=5)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"VariableDestroyed(name=n)",
+			"ExitScope",
 			"FunctionReturn(name=factorial,value=6)",
 			"LineChanged(line=9)",
 		}},
@@ -844,6 +958,9 @@ int main() {
 			"LineChanged(line=10)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=result)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=6)",
 			"LineChanged(line=-1)",
 		}},
@@ -886,6 +1003,10 @@ int main() {
 			"LineChanged(line=2)",
 		}},
 		{Events: []string{
+			"ExitScope",
+			"VariableDestroyed(name=b)",
+			"VariableDestroyed(name=a)",
+			"ExitScope",
 			"FunctionReturn(name=add,value=8)",
 			"LineChanged(line=6)",
 		}},
@@ -894,6 +1015,9 @@ int main() {
 			"LineChanged(line=7)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=x)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=8)",
 			"LineChanged(line=-1)",
 		}},
@@ -902,6 +1026,62 @@ int main() {
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
 }
 
+func TestInterpreterSteps_ImplicitConversionOnAssignmentAndCall(t *testing.T) {
+	code := `double half(double x) {
+	return x / 2;
+}
+
+int main() {
+	int n = half(7);
+	return n;
+}`
+
+	result, steps, _ := runCodeWithSteps(t, code)
+
+	require.NotNil(t, result)
+	assert.Equal(t, 3, *result)
+	require.Len(t, steps, 5)
+
+	expectedSteps := []normalizedStep{
+		{Events: []string{
+			"FunctionCall(name=main)",
+			"EnterScope",
+			"EnterScope",
+			"LineChanged(line=6)",
+		}},
+		{Events: []string{
+			"FunctionCall(name=half)",
+			"EnterScope",
+			"DeclareVar(name=x,global=false)",
+			"ImplicitConversion(from=int:7,to=double:7.000000)",
+			"VarChanged(name=x,value=7.000000)",
+			"EnterScope",
+			"LineChanged(line=2)",
+		}},
+		{Events: []string{
+			"ExitScope",
+			"VariableDestroyed(name=x)",
+			"ExitScope",
+			"FunctionReturn(name=half,value=3.500000)",
+			"LineChanged(line=6)",
+		}},
+		{Events: []string{
+			"ImplicitConversion(from=double:3.500000,to=int:3)",
+			"DeclareVar(name=n,global=false)",
+			"LineChanged(line=7)",
+		}},
+		{Events: []string{
+			"VariableDestroyed(name=n)",
+			"ExitScope",
+			"ExitScope",
+			"FunctionReturn(name=main,value=3)",
+			"LineChanged(line=-1)",
+		}},
+	}
+
+	assert.Equal(t, expectedSteps, normalizeSteps(steps))
+}
+
 func TestInterpreterSteps_VoidFunction(t *testing.T) {
 	code := `void printValue(int x) {
 	x = x + 1;
@@ -941,6 +1121,8 @@ int main() {
 		{Events: []string{
 			"VarChanged(name=x,value=6)",
 			"ExitScope",
+			"VariableDestroyed(name=x)",
+			"ExitScope",
 			"LineChanged(line=7)",
 			"FunctionReturn(name=printValue,value=nil)",
 		}},
@@ -948,6 +1130,9 @@ int main() {
 			"LineChanged(line=8)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=num)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=5)",
 			"LineChanged(line=-1)",
 		}},
@@ -996,6 +1181,8 @@ int main() {
 		{Events: []string{
 			"VarChanged(name=x,value=11)",
 			"ExitScope",
+			"VariableDestroyed(name=x)",
+			"ExitScope",
 			"LineChanged(line=7)",
 			"FunctionReturn(name=increment,value=nil)",
 		}},
@@ -1013,6 +1200,8 @@ int main() {
 		{Events: []string{
 			"VarChanged(name=x,value=11)",
 			"ExitScope",
+			"VariableDestroyed(name=x)",
+			"ExitScope",
 			"LineChanged(line=8)",
 			"FunctionReturn(name=increment,value=nil)",
 		}},
@@ -1020,6 +1209,9 @@ int main() {
 			"LineChanged(line=9)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=a)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=10)",
 			"LineChanged(line=-1)",
 		}},
@@ -1101,6 +1293,12 @@ int main() {
 			"LineChanged(line=3)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=result)",
+			"ExitScope",
+			"VariableDestroyed(name=c)",
+			"VariableDestroyed(name=b)",
+			"VariableDestroyed(name=a)",
+			"ExitScope",
 			"FunctionReturn(name=multiply,value=24)",
 			"LineChanged(line=7)",
 		}},
@@ -1109,6 +1307,9 @@ int main() {
 			"LineChanged(line=8)",
 		}},
 		{Events: []string{
+			"VariableDestroyed(name=value)",
+			"ExitScope",
+			"ExitScope",
 			"FunctionReturn(name=main,value=24)",
 			"LineChanged(line=-1)",
 		}},
@@ -1116,3 +1317,696 @@ int main() {
 
 	assert.Equal(t, expectedSteps, normalizeSteps(steps))
 }
+
+func TestInterpreterSteps_HeapAllocationAndLeak(t *testing.T) {
+	code := `int main() {
+	int *p = malloc(2 * sizeof(int));
+	p[1] = 5;
+	int *q = malloc(sizeof(int));
+	free(q);
+	return 0;
+}`
+
+	result, steps, _ := runCodeWithSteps(t, code)
+
+	require.NotNil(t, result)
+	normalized := normalizeSteps(steps)
+	require.GreaterOrEqual(t, len(normalized), 5)
+
+	var all []string
+	for _, st := range normalized {
+		all = append(all, st.Events...)
+	}
+	assert.Contains(t, all, "HeapAlloc(address=0x18000,size=8,line=2)")
+	assert.Contains(t, all, "HeapBlockTyped(address=0x18000,type=int)")
+	assert.Contains(t, all, "HeapElementChanged(address=0x18000,ind=1,value=5)")
+	assert.Contains(t, all, "HeapAlloc(address=0x28000,size=4,line=4)")
+	assert.Contains(t, all, "HeapFree(address=0x28000)")
+
+	// неосвобожденный блок сообщается отдельным последним шагом
+	assert.Equal(t, []string{"HeapLeak(address=0x18000,size=8,line=2)"}, normalized[len(normalized)-1].Events)
+}
+
+func TestInterpreterSteps_UseAfterFreeIsUndefinedBehavior(t *testing.T) {
+	code := `int main() {
+	int *p = malloc(sizeof(int));
+	free(p);
+	return *p;
+}`
+
+	result, steps, _, err := runCodeWithStepsAllowError(t, code)
+
+	assert.Nil(t, result)
+	require.NotNil(t, err)
+
+	normalized := normalizeSteps(steps)
+	require.NotEmpty(t, normalized)
+	assert.Contains(t, normalized[len(normalized)-1].Events, "UndefinedBehavior(message=undefined behavior: use after free of heap block allocated at line 2)")
+}
+
+func TestInterpreterSteps_BuiltinCallsAndExit(t *testing.T) {
+	code := `int main() {
+	int a = abs(-5);
+	int *p = malloc(sizeof(int));
+	exit(a + 1);
+	return 0;
+}`
+
+	result, steps, _ := runCodeWithSteps(t, code)
+
+	require.NotNil(t, result)
+	assert.Equal(t, 6, *result)
+
+	normalized := normalizeSteps(steps)
+	var all []string
+	for _, st := range normalized {
+		all = append(all, st.Events...)
+	}
+	assert.Contains(t, all, "BuiltinCall(name=abs,args=-5)")
+	assert.Contains(t, all, "BuiltinCall(name=malloc,args=4)")
+	assert.Contains(t, all, "BuiltinCall(name=exit,args=6)")
+	assert.NotContains(t, all, "LineChanged(line=5)")
+
+	// блок, не освобожденный до exit, считается утечкой
+	assert.Equal(t, []string{"HeapLeak(address=0x18000,size=4,line=3)"}, normalized[len(normalized)-1].Events)
+}
+
+func TestInterpreterSteps_ExitUnwindsScopesAndFrames(t *testing.T) {
+	code := `void stop(int n) {
+	for (int i = 0; i < n; i++) {
+		int x = i;
+		if (x == 1) {
+			exit(3);
+		}
+	}
+}
+
+int main() {
+	int a = 2;
+	stop(a);
+	return 0;
+}`
+
+	result, steps, _ := runCodeWithSteps(t, code)
+
+	require.NotNil(t, result)
+	assert.Equal(t, 3, *result)
+
+	counts := map[string]int{}
+	var returns []string
+	for _, st := range steps {
+		for _, event := range st.Events {
+			switch e := event.(type) {
+			case events.EnterScope, events.ExitScope, events.DeclareVar, events.VariableDestroyed, events.FunctionCall:
+				counts[fmt.Sprintf("%T", e)]++
+			case events.FunctionReturn:
+				counts[fmt.Sprintf("%T", e)]++
+				returns = append(returns, e.Name)
+			}
+		}
+	}
+
+	assert.Equal(t, counts["events.EnterScope"], counts["events.ExitScope"])
+	assert.Equal(t, counts["events.DeclareVar"], counts["events.VariableDestroyed"])
+	assert.Equal(t, 2, counts["events.FunctionCall"])
+	// кадры снимаются от внутреннего к main
+	assert.Equal(t, []string{"stop", "main"}, returns)
+	assert.Equal(t, step.KindProgramEnd, steps[len(steps)-1].Kind)
+}
+
+func TestInterpreterSteps_AssertionFailed(t *testing.T) {
+	code := `int main() {
+	int x = 3;
+	assert(x > 5);
+	return 0;
+}`
+
+	result, steps, _, err := runCodeWithStepsAllowError(t, code)
+
+	assert.Nil(t, result)
+	require.Error(t, err)
+	assert.Equal(t, "assertion failed: assert at line 3", err.Error())
+
+	normalized := normalizeSteps(steps)
+	require.NotEmpty(t, normalized)
+	assert.Equal(t, []string{
+		"BuiltinCall(name=assert,args=0)",
+		"AssertionFailed(message=assertion failed: assert at line 3,line=3)",
+	}, normalized[len(normalized)-1].Events)
+}
+
+func TestInterpreterSteps_InfiniteLoop(t *testing.T) {
+	testCases := []struct {
+		name        string
+		code        string
+		expectedErr string
+		expected    string
+	}{
+		{
+			name: "while with unchanged counter",
+			code: `int main() {
+	int i = 0;
+	int s = 0;
+	while (i < 10) {
+		s = 1;
+	}
+	return s;
+}`,
+			expectedErr: "infinite loop: line 4: i is never modified in this while loop",
+			expected:    "InfiniteLoop(construct=while,line=4,cycle=1,unchanged=i)",
+		},
+		{
+			name: "for without condition",
+			code: `int main() {
+	for (;;) {
+	}
+	return 0;
+}`,
+			expectedErr: "infinite loop: line 2: condition of this for loop never changes",
+			expected:    "InfiniteLoop(construct=for,line=2,cycle=1,unchanged=)",
+		},
+		{
+			name: "do-while with toggling variable",
+			code: `int main() {
+	int x = 0;
+	do {
+		x = 1 - x;
+	} while (x < 2);
+	return 0;
+}`,
+			expectedErr: "infinite loop: line 5: program state repeats every 2 iterations of this do-while loop",
+			expected:    "InfiniteLoop(construct=do,line=5,cycle=2,unchanged=)",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, steps, _, err := runCodeWithStepsAllowError(t, tt.code)
+
+			assert.Nil(t, result)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedErr, err.Error())
+
+			normalized := normalizeSteps(steps)
+			require.NotEmpty(t, normalized)
+			last := normalized[len(normalized)-1]
+			assert.Equal(t, tt.expected, last.Events[len(last.Events)-1])
+			assert.Equal(t, step.KindError, steps[len(steps)-1].Kind)
+		})
+	}
+}
+
+func TestInterpreterSteps_TerminatingLoopsAreNotInfinite(t *testing.T) {
+	code := `int main() {
+	int s = 0;
+	for (int i = 0; i < 3; i++) {
+		int j = 0;
+		while (j < 2) {
+			j++;
+		}
+		s = s + j;
+	}
+	return s;
+}`
+
+	result, _, _, err := runCodeWithStepsAllowError(t, code)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, 6, *result)
+
+	// каждый цикл меняет только элемент массива или блока кучи
+	code = `int main() {
+	int a[3] = {0, 0, 0};
+	int m[2][3] = {{0, 0, 0}, {0, 0, 0}};
+	int *p = (int*)calloc(2, sizeof(int));
+	while (a[1] < 3) {
+		a[1] = a[1] + 1;
+	}
+	while (m[1][2] < 3) {
+		m[1][2] = m[1][2] + 1;
+	}
+	while (p[1] < 3) {
+		p[1] = p[1] + 1;
+	}
+	int s = a[1] + m[1][2] + p[1];
+	free(p);
+	return s;
+}`
+
+	result, _, _, err = runCodeWithStepsAllowError(t, code)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, 9, *result)
+}
+
+func TestInterpreterSteps_LoopStateCheckCostDoesNotDependOnArraySize(t *testing.T) {
+	const n = 20000
+	code := fmt.Sprintf(`int main() {
+	int a[%d];
+	for (int i = 0; i < %d; i++) {
+		a[i] = i;
+	}
+	return a[%d];
+}`, n, n, n-1)
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	runner := NewInterpreterWithLimits(2*n, 10*n)
+	result, _, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, n-1, *result)
+
+	// элементы хэшируются один раз при первой проверке и затем по одному на присваивание,
+	// а не целиком на каждой итерации
+	assert.LessOrEqual(t, runner.digests.hashed, 2*n)
+}
+
+func TestInterpreterSteps_ExpressionStepping(t *testing.T) {
+	code := `int twice(int n) {
+	return n * 2;
+}
+
+int main() {
+	int a[2] = {1, 2};
+	int x = a[1] * twice(3) + 1;
+	return x;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	runner := NewInterpreter()
+	runner.SetExpressionStepping(true)
+	result, steps, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, 13, *result)
+
+	var evaluated []string
+	for _, st := range normalizeSteps(steps) {
+		for _, event := range st.Events {
+			if strings.HasPrefix(event, "ExprEvaluated") {
+				// каждое подвыражение - отдельный шаг
+				assert.Equal(t, []string{event}, st.Events)
+				evaluated = append(evaluated, event)
+			}
+		}
+	}
+
+	// литералы не фиксируются, вызов twice вычисляется в собственном кадре до умножения
+	assert.Equal(t, []string{
+		"ExprEvaluated(loc=7:9-7:13,value=2)",
+		"ExprEvaluated(loc=2:8-2:9,value=3)",
+		"ExprEvaluated(loc=2:8-2:13,value=6)",
+		"ExprEvaluated(loc=7:16-7:24,value=6)",
+		"ExprEvaluated(loc=7:9-7:24,value=12)",
+		"ExprEvaluated(loc=7:9-7:28,value=13)",
+		"ExprEvaluated(loc=8:8-8:9,value=13)",
+	}, evaluated)
+}
+
+func TestInterpreterSteps_ReadEvents(t *testing.T) {
+	code := `int main() {
+	int a[3] = {5, 1, 4};
+	int m[1][2] = {{7, 8}};
+	int *p = (int*)malloc(sizeof(int));
+	int j = 0;
+	p[0] = 2;
+	if (a[j] > a[j + 1]) {
+		int t = a[j] + m[0][1];
+		j += *p;
+	}
+	free(p);
+	return j;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	plain, _, _, err := NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	runner := NewInterpreter()
+	runner.SetReadEvents(true)
+	result, steps, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+	assert.Equal(t, *plain, *result)
+
+	var reads [][]string
+	for _, st := range normalizeSteps(steps) {
+		var stepReads []string
+		for _, event := range st.Events {
+			if strings.Contains(event, "Read(") {
+				stepReads = append(stepReads, event)
+			}
+		}
+		if stepReads != nil {
+			reads = append(reads, stepReads)
+		}
+	}
+
+	// чтения шага идут в порядке вычисления; указатель при индексации и malloc/free не читаются как значения
+	assert.Equal(t, [][]string{
+		{
+			"VarRead(name=j,value=0,use=index)",
+			"ArrayElementRead(name=a,ind=0,value=5,use=compare)",
+			"VarRead(name=j,value=0,use=arithmetic)",
+			"ArrayElementRead(name=a,ind=1,value=1,use=compare)",
+		},
+		{
+			"VarRead(name=j,value=0,use=index)",
+			"ArrayElementRead(name=a,ind=0,value=5,use=arithmetic)",
+			"Array2DElementRead(name=m,ind1=0,ind2=1,value=8,use=arithmetic)",
+		},
+		{
+			"VarRead(name=p,value=0x18000,use=value)",
+			"HeapElementRead(address=0x18000,ind=0,value=2,use=arithmetic)",
+			"VarRead(name=j,value=0,use=arithmetic)",
+		},
+		{"VarRead(name=p,value=0x18000,use=value)"},
+		{"VarRead(name=j,value=2,use=value)"},
+	}, reads)
+}
+
+func TestInterpreterSteps_IndexMarkers(t *testing.T) {
+	code := `int main() {
+	int a[4] = {3, 1, 0, 2};
+	int m[2][3] = {{0, 0, 0}, {0, 0, 0}};
+	int *p = (int*)malloc(2 * sizeof(int));
+	int i = 1;
+	int j = 0;
+	if (a[j] > a[j + 1]) {
+		m[i][j + 2] = a[a[i + 1]];
+	}
+	p[i] = a[2 * i];
+	free(p);
+	return 0;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	runner := NewInterpreter()
+	runner.SetIndexMarkers(true)
+	_, steps, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+
+	var markers [][]string
+	for _, st := range normalizeSteps(steps) {
+		var stepMarkers []string
+		for _, event := range st.Events {
+			if strings.HasPrefix(event, "ArrayIndexed") {
+				stepMarkers = append(stepMarkers, event)
+			}
+		}
+		if stepMarkers != nil {
+			markers = append(markers, stepMarkers)
+		}
+	}
+
+	// в a[a[i + 1]] переменная i индексирует только внутреннее обращение
+	assert.Equal(t, [][]string{
+		{
+			"ArrayIndexed(variable=j,name=a,address=0x0,dim=0,index=0,offset=0)",
+			"ArrayIndexed(variable=j,name=a,address=0x0,dim=0,index=1,offset=1)",
+		},
+		{
+			"ArrayIndexed(variable=i,name=m,address=0x0,dim=0,index=1,offset=0)",
+			"ArrayIndexed(variable=j,name=m,address=0x0,dim=1,index=2,offset=2)",
+			"ArrayIndexed(variable=i,name=a,address=0x0,dim=0,index=2,offset=1)",
+		},
+		{
+			"ArrayIndexed(variable=i,name=,address=0x18000,dim=0,index=1,offset=0)",
+			"ArrayIndexed(variable=i,name=a,address=0x0,dim=0,index=2,offset=1)",
+		},
+	}, markers)
+
+	// без режима маркеров события не добавляются
+	_, plain, _, err := NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+	assert.Equal(t, len(normalizeSteps(plain)), len(normalizeSteps(steps)))
+	for _, st := range normalizeSteps(plain) {
+		for _, event := range st.Events {
+			assert.False(t, strings.HasPrefix(event, "ArrayIndexed"))
+		}
+	}
+}
+
+func TestInterpreterSteps_RangeChangedForLoopPhasesAndConditions(t *testing.T) {
+	code := `int main() {
+	int s = 0;
+	for (int i = 0; i < 2; i++) s += i;
+	if (s > 0) s = 5;
+	return s;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	result, steps, _, err := NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, 5, *result)
+
+	var ranges []string
+	for _, st := range steps {
+		for _, event := range st.Events {
+			if _, ok := event.(events.RangeChanged); ok {
+				ranges = append(ranges, normalizeEvent(event))
+			}
+		}
+	}
+
+	init := "RangeChanged(loc=3:6-3:16)"
+	cond := "RangeChanged(loc=3:17-3:22)"
+	body := "RangeChanged(loc=3:29-3:36)"
+	post := "RangeChanged(loc=3:24-3:27)"
+	assert.Equal(t, []string{
+		"RangeChanged(loc=2:5-2:10)",
+		init, cond, body, post, cond, body, post, cond,
+		"RangeChanged(loc=4:5-4:10)",
+		"RangeChanged(loc=4:12-4:18)",
+		"RangeChanged(loc=5:1-5:10)",
+	}, ranges)
+}
+
+func TestInterpreterSteps_StepPositions(t *testing.T) {
+	code := `int twice(int n) {
+	return n * 2;
+}
+
+int main() {
+	int x = twice(3);
+	if (x > 5) x = 0;
+	return x;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	_, steps, stepBegin, err := NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	type position struct {
+		Depth    int
+		Function string
+		Line     int
+		Kind     step.Kind
+	}
+	var positions []position
+	for _, st := range steps[stepBegin:] {
+		positions = append(positions, position{st.Depth, st.Function, st.Line, st.Kind})
+	}
+
+	assert.Equal(t, []position{
+		{1, "main", 6, step.KindDeclaration},
+		{2, "twice", 2, step.KindReturn},
+		{1, "main", 6, step.KindCallReturn},
+		{1, "main", 7, step.KindCondition},
+		{1, "main", 7, step.KindExpression},
+		{1, "main", 8, step.KindReturn},
+		{0, "", -1, step.KindProgramEnd},
+	}, positions)
+}
+
+func TestInterpreterSteps_DeclarationIDs(t *testing.T) {
+	code := `int g;
+int fact(int n) {
+	if (n <= 1) {
+		return 1;
+	}
+	return n * fact(n - 1);
+}
+int main() {
+	int a[2];
+	int x = fact(2);
+	{
+		int x = 5;
+		a[1] = x;
+	}
+	x = 7;
+	return 0;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	runner := NewInterpreter()
+	runner.SetReadEvents(true)
+	_, steps, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+
+	declared := map[int]string{}
+	var factParams, changed, arrayChanged, reads []int
+	for _, st := range steps {
+		for _, event := range st.Events {
+			switch e := event.(type) {
+			case events.DeclareVar:
+				require.NotZero(t, e.ID)
+				require.NotContains(t, declared, e.ID)
+				declared[e.ID] = e.Name
+				if e.Name == "n" {
+					factParams = append(factParams, e.ID)
+				}
+			case events.DeclareArray:
+				declared[e.ID] = e.Name
+			case events.VarChanged:
+				changed = append(changed, e.ID)
+			case events.ArrayElementChanged:
+				arrayChanged = append(arrayChanged, e.ID)
+			case events.VarRead:
+				if e.Name == "x" {
+					reads = append(reads, e.ID)
+				}
+			}
+		}
+	}
+
+	// каждый рекурсивный вызов объявляет свой экземпляр параметра
+	require.Len(t, factParams, 2)
+	assert.NotEqual(t, factParams[0], factParams[1])
+
+	ids := map[string][]int{}
+	for id, name := range declared {
+		ids[name] = append(ids[name], id)
+	}
+	require.Len(t, ids["x"], 2)
+	outer, inner := min(ids["x"][0], ids["x"][1]), max(ids["x"][0], ids["x"][1])
+
+	// события изменения ссылаются на объявление, а не на имя: внутренний x затеняет внешний
+	assert.Equal(t, []int{factParams[0], factParams[1], outer}, changed)
+	assert.Equal(t, ids["a"], arrayChanged)
+	assert.Equal(t, []int{inner}, reads)
+}
+
+func TestInterpreterSteps_ScopeKindsAndLifetimes(t *testing.T) {
+	code := `int find(int x) {
+	for (int i = 0; i < 10; i++) {
+		int sq = i * i;
+		if (sq == x) {
+			return i;
+		}
+	}
+	return -1;
+}
+int main() {
+	int r = find(4);
+	for (int k = 0; k < 5; k++) {
+		if (k == 1) {
+			break;
+		}
+	}
+	{
+		int t = r;
+	}
+	return r;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	_, steps, _, err := NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	var entered []string
+	var destroyed []string
+	depth := []int{0}
+	for _, st := range steps {
+		for _, event := range st.Events {
+			switch e := event.(type) {
+			case events.FunctionCall:
+				depth = append(depth, 0)
+			case events.EnterScope:
+				require.NotNil(t, e.Owner)
+				entered = append(entered, fmt.Sprintf("%s:%d-%d", e.Kind, e.StartLine, e.EndLine))
+				depth[len(depth)-1]++
+			case events.ExitScope:
+				depth[len(depth)-1]--
+				require.GreaterOrEqual(t, depth[len(depth)-1], 0)
+			case events.VariableDestroyed:
+				destroyed = append(destroyed, e.Name)
+			case events.FunctionReturn:
+				// к возврату из функции все ее области видимости закрыты
+				require.Equal(t, 0, depth[len(depth)-1], "function %s", e.Name)
+				depth = depth[:len(depth)-1]
+			}
+		}
+	}
+
+	assert.Equal(t, []string{
+		"function:10-21", "body:10-21",
+		"function:1-9", "body:1-9", "for:2-7", "block:2-7", "block:2-7", "block:2-7", "block:4-6",
+		"for:12-16", "block:12-16", "block:12-16", "block:13-15",
+		"block:17-19",
+	}, entered)
+	// return из цикла уничтожает переменные всех вложенных областей, break - области цикла
+	assert.Equal(t, []string{"sq", "sq", "sq", "i", "x", "k", "t", "r"}, destroyed)
+}
+
+func TestInterpreterSteps_CallEventsCarryCallSite(t *testing.T) {
+	code := `int twice(double x) {
+	return x * 2;
+}
+void noop() {
+}
+int main() {
+	int r = twice(1.5);
+	noop();
+	return r;
+}`
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+
+	_, steps, _, err := NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	var calls []events.FunctionCall
+	var returns []events.FunctionReturn
+	for _, st := range steps {
+		for _, event := range st.Events {
+			switch e := event.(type) {
+			case events.FunctionCall:
+				calls = append(calls, e)
+			case events.FunctionReturn:
+				returns = append(returns, e)
+			}
+		}
+	}
+
+	require.Len(t, calls, 3)
+	assert.Nil(t, calls[0].Loc)
+	require.NotNil(t, calls[1].Loc)
+	assert.Equal(t, uint32(7), calls[1].Loc.Line)
+	assert.Equal(t, []events.Argument{{Name: "x", Value: runtime.NewDoubleValue(1.5)}}, calls[1].Arguments)
+	assert.Empty(t, calls[2].Arguments)
+
+	require.Len(t, returns, 3)
+	assert.Equal(t, 7, returns[0].CallerLine)
+	assert.Equal(t, 8, returns[1].CallerLine)
+	assert.Equal(t, 0, returns[2].CallerLine)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/builtins"
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 )
 
@@ -110,7 +111,458 @@ func TestArithmetic(t *testing.T) {
 	}
 }
 
+// TestFloatingPoint tests float/double variables, usual arithmetic conversions and implicit conversions
+func TestFloatingPoint(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "double division keeps fraction",
+			code:     `int main() { double a = 7; double b = a / 2; return b * 10; }`,
+			expected: 35,
+		},
+		{
+			name:     "int division stays integer",
+			code:     `int main() { double a = 7 / 2; return a * 10; }`,
+			expected: 30,
+		},
+		{
+			name:     "mixed int and double arithmetic",
+			code:     `int main() { int n = 3; double total = 1 + 2 + 4; double avg = total / n; return avg * 100; }`,
+			expected: 233,
+		},
+		{
+			name:     "assignment to int truncates",
+			code:     `int main() { int x = 2.9; int y = -2.9; return x * 10 + y; }`,
+			expected: 18,
+		},
+		{
+			name:     "compound assignment with double operand",
+			code:     `int main() { int x = 5; x *= 1.5; return x; }`,
+			expected: 7,
+		},
+		{
+			name:     "float literal with suffix",
+			code:     `int main() { float f = 0.5f; f += 0.25; return f * 100; }`,
+			expected: 75,
+		},
+		{
+			name:     "double comparison",
+			code:     `int main() { double a = 0.1; if (a < 0.2 && a != 0) { return 1; } return 0; }`,
+			expected: 1,
+		},
+		{
+			name:     "double in condition",
+			code:     `int main() { double a = 0.5; int count = 0; while (a) { a = a - 0.25; count++; } return count; }`,
+			expected: 2,
+		},
+		{
+			name:     "increment of double",
+			code:     `int main() { double a = 1.5; a++; ++a; return a * 2; }`,
+			expected: 7,
+		},
+		{
+			name:     "unary minus and not on double",
+			code:     `int main() { double a = 1.5; double b = -a; return !b + (b < 0); }`,
+			expected: 1,
+		},
+		{
+			name:     "double array average",
+			code:     `int main() { double xs[4] = {1, 2.5, 3}; double sum = 0; for (int i = 0; i < 4; i++) { sum += xs[i]; } return sum / 4 * 100; }`,
+			expected: 162,
+		},
+		{
+			name:     "double 2d array",
+			code:     `int main() { double m[2][2] = {{0.5, 1}, {1.5}}; m[1][1] = m[0][0] + m[1][0]; return m[1][1]; }`,
+			expected: 2,
+		},
+		{
+			name:     "double parameter and return",
+			code:     `double area(double r) { return 3.14159 * r * r; } int main() { return area(2); }`,
+			expected: 12,
+		},
+		{
+			name:     "int function returning double expression",
+			code:     `int trunc(double x) { return x; } int main() { return trunc(9.99); }`,
+			expected: 9,
+		},
+		{
+			name:     "global double is zero initialized",
+			code:     `double g; int main() { g += 2.5; return g * 2; }`,
+			expected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCode(t, tt.code)
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+}
+
+func TestFloatingPointOverflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		expectedErr string
+	}{
+		{
+			name:        "double multiplication",
+			code:        `int main() { double d = 1e308; d = d * 10; return 0; }`,
+			expectedErr: "undefined behavior: floating-point overflow: result is out of range of double",
+		},
+		{
+			name:        "float arithmetic",
+			code:        `int main() { float f = 3e38f; f = f + f; return 0; }`,
+			expectedErr: "undefined behavior: floating-point overflow: result is out of range of float",
+		},
+		{
+			name:        "double to float conversion",
+			code:        `int main() { double d = 1e300; float f = d; return 0; }`,
+			expectedErr: "undefined behavior: value 1e+300 is out of range of float",
+		},
+		{
+			name:        "float literal",
+			code:        `int main() { float f = 1e39f; return 0; }`,
+			expectedErr: "undefined behavior: value 1e+39 is out of range of float",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCodeExpectError(t, tt.code)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+// TestCharsAndStrings tests char variables, char arrays initialized from string literals and <string.h> built-ins
+func TestCharsAndStrings(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "char literal code",
+			code:     `int main() { char c = 'a'; return c; }`,
+			expected: 97,
+		},
+		{
+			name:     "char arithmetic promotes to int",
+			code:     `int main() { char c = 'z'; return c - 'a' + 1; }`,
+			expected: 26,
+		},
+		{
+			name:     "char increment",
+			code:     `int main() { char c = 'a'; c++; c += 2; return c; }`,
+			expected: 100,
+		},
+		{
+			name:     "char wraps on assignment",
+			code:     `int main() { char c = 300; return c; }`,
+			expected: 44,
+		},
+		{
+			name:     "escape sequences",
+			code:     `int main() { char n = '\n'; char z = '\0'; return n * 10 + z; }`,
+			expected: 100,
+		},
+		{
+			name:     "string initializer has terminator",
+			code:     `int main() { char s[6] = "abc"; return s[3] == '\0' && s[5] == 0; }`,
+			expected: 1,
+		},
+		{
+			name:     "string exactly fills array without terminator",
+			code:     `int main() { char s[3] = "abc"; return s[2]; }`,
+			expected: 99,
+		},
+		{
+			name:     "manual strlen loop",
+			code:     `int main() { char s[10] = "hello"; int n = 0; while (s[n] != '\0') { n++; } return n; }`,
+			expected: 5,
+		},
+		{
+			name:     "strlen",
+			code:     `int main() { char s[10] = "hello"; return strlen(s); }`,
+			expected: 5,
+		},
+		{
+			name:     "strcpy and strcat",
+			code:     `int main() { char s[10]; strcpy(s, "ab"); strcat(s, "cde"); return strlen(s) * 100 + s[4]; }`,
+			expected: 601,
+		},
+		{
+			name:     "strcmp",
+			code:     `int main() { char a[4] = "abc"; char b[4] = "abd"; return (strcmp(a, b) < 0) + (strcmp(b, a) > 0) + (strcmp(a, "abc") == 0); }`,
+			expected: 3,
+		},
+		{
+			name:     "reverse string in place",
+			code:     `int main() { char s[6] = "hello"; int n = strlen(s); for (int i = 0; i < n / 2; i++) { char tmp = s[i]; s[i] = s[n - 1 - i]; s[n - 1 - i] = tmp; } return s[0] == 'o' && s[4] == 'h'; }`,
+			expected: 1,
+		},
+		{
+			name:     "count characters",
+			code:     `int main() { char s[12] = "abracadabra"; int count[26] = {0}; for (int i = 0; s[i]; i++) { count[s[i] - 'a']++; } return count[0]; }`,
+			expected: 5,
+		},
+		{
+			name:     "2d char array of words",
+			code:     `int main() { char words[3][6] = {"one", "three"}; strcpy(words[2], "two"); return strlen(words[1]) * 10 + strcmp(words[0], "one") + words[2][1]; }`,
+			expected: 169,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCode(t, tt.code)
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+
+	// strcpy и strcat возвращают void: результат нельзя использовать как значение
+	err := runCodeExpectError(t, `int main() { char s[4]; int r = strcpy(s, "ab"); return r; }`)
+	require.Error(t, err)
+}
+
+func TestEnumsAndTypedefs(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "enumerator values",
+			code:     `enum Color { RED, GREEN = 5, BLUE }; int main() { return RED * 100 + GREEN * 10 + BLUE; }`,
+			expected: 56,
+		},
+		{
+			name:     "enum variable",
+			code:     `enum Color { RED, GREEN, BLUE }; int main() { enum Color c = RED; c = c + 2; return c == BLUE; }`,
+			expected: 1,
+		},
+		{
+			name:     "global enum variable is zero-initialized",
+			code:     `enum State { IDLE, RUNNING }; enum State s; int main() { return s == IDLE; }`,
+			expected: 1,
+		},
+		{
+			name:     "enum parameter and return",
+			code:     `enum Dir { UP, DOWN }; enum Dir flip(enum Dir d) { if (d == UP) return DOWN; return UP; } int main() { return flip(flip(DOWN)) == DOWN; }`,
+			expected: 1,
+		},
+		{
+			name:     "local enum",
+			code:     `int main() { enum { ONE = 1, TWO }; int x = TWO; return x; }`,
+			expected: 2,
+		},
+		{
+			name:     "local variable shadows enumerator",
+			code:     `enum { N = 10 }; int main() { int r = N; { int N = 3; r = r + N; } return r; }`,
+			expected: 13,
+		},
+		{
+			name:     "typedef alias",
+			code:     `typedef int myint; myint twice(myint x) { return x * 2; } int main() { myint a = 21; return twice(a); }`,
+			expected: 42,
+		},
+		{
+			name:     "typedef of array",
+			code:     `typedef int row[3]; int main() { row m[2] = {{1, 2, 3}, {4, 5, 6}}; return m[1][2]; }`,
+			expected: 6,
+		},
+		{
+			name:     "typedef of enum",
+			code:     `typedef enum { OFF, ON } Switch; int main() { Switch s = ON; return s; }`,
+			expected: 1,
+		},
+		{
+			name:     "typedef of double converts on assignment",
+			code:     `typedef double real; int main() { real r = 7; r = r / 2; return r * 2; }`,
+			expected: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCode(t, tt.code)
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+}
+
 // TestComparison tests comparison operations
+func TestHeap(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "malloc array via pointer",
+			code:     `int main() { int *p = malloc(3 * sizeof(int)); p[0] = 1; p[1] = 2; p[2] = 3; int s = p[0] + p[1] + p[2]; free(p); return s; }`,
+			expected: 6,
+		},
+		{
+			name:     "dereference and pointer arithmetic",
+			code:     `int main() { int *p = (int*)malloc(2 * sizeof(int)); *p = 4; *(p + 1) = 5; int *q = p + 1; int r = *p * 10 + *q + (q - p) * 100; free(p); return r; }`,
+			expected: 145,
+		},
+		{
+			name:     "calloc zero-fills",
+			code:     `int main() { double *d = calloc(4, sizeof(double)); double s = d[0] + d[3]; free(d); return s == 0; }`,
+			expected: 1,
+		},
+		{
+			name:     "realloc keeps values",
+			code:     `int main() { int *p = malloc(2 * sizeof(int)); p[0] = 7; p[1] = 8; p = realloc(p, 4 * sizeof(int)); p[3] = 1; int r = p[0] + p[1] + p[3]; free(p); return r; }`,
+			expected: 16,
+		},
+		{
+			name:     "null pointer checks",
+			code:     `int main() { int *p = NULL; if (!p) { p = malloc(sizeof(int)); } int ok = p != NULL; free(p); free(NULL); return ok; }`,
+			expected: 1,
+		},
+		{
+			name:     "malloc of too large block returns NULL",
+			code:     `int main() { char *p = malloc(1000000); return p == NULL; }`,
+			expected: 1,
+		},
+		{
+			name:     "pointer parameter and return",
+			code:     `int *make(int n) { int *a = malloc(n * sizeof(int)); for (int i = 0; i < n; i++) { a[i] = i * i; } return a; } int sum(int *a, int n) { int s = 0; for (int i = 0; i < n; i++) { s += a[i]; } return s; } int main() { int *a = make(4); int s = sum(a, 4); free(a); return s; }`,
+			expected: 14,
+		},
+		{
+			name:     "pointer increment walks block",
+			code:     `int main() { char *s = malloc(3); s[0] = 'a'; s[1] = 'b'; s[2] = 0; char *c = s; int n = 0; while (*c) { n++; c++; } free(s); return n; }`,
+			expected: 2,
+		},
+		{
+			name:     "sizeof of types and expressions",
+			code:     `int main() { int a[5]; double m[2][3]; char *p; return sizeof(char) + sizeof(int) * 10 + sizeof(a) * 100 + sizeof(m[1]) * 1000 + sizeof(p) + sizeof(*p) * 0; }`,
+			expected: 1 + 40 + 2000 + 24000 + 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCode(t, tt.code)
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "abs min max",
+			code:     `int main() { return abs(-7) * 100 + min(3, -2) * 10 + max(4, 9); }`,
+			expected: 689,
+		},
+		{
+			name:     "abs converts double argument",
+			code:     `int main() { return abs(-2.7); }`,
+			expected: 2,
+		},
+		{
+			name:     "rand is deterministic for a seed",
+			code:     `int main() { srand(7); int a = rand(); int b = rand(); srand(7); return a == rand() && b == rand() && a != b; }`,
+			expected: 1,
+		},
+		{
+			name:     "rand without srand uses seed 1",
+			code:     `int main() { return rand(); }`,
+			expected: 16838,
+		},
+		{
+			name:     "rand range",
+			code:     `int main() { for (int i = 0; i < 20; i++) { int r = rand(); if (r < 0 || r > 32767) { return 0; } } return 1; }`,
+			expected: 1,
+		},
+		{
+			name:     "passing assert",
+			code:     `int main() { double d = 0.5; assert(d); assert(1 < 2); return 1; }`,
+			expected: 1,
+		},
+		{
+			name:     "exit from nested function",
+			code:     `void fail(int code) { exit(code); } int main() { fail(3); return 0; }`,
+			expected: 3,
+		},
+		{
+			name:     "user function shadows built-in",
+			code:     `int max(int a, int b, int c) { return c; } int main() { return max(1, 2, 3); }`,
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCode(t, tt.code)
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+}
+
+func TestBuiltinRegistry(t *testing.T) {
+	for _, signature := range builtins.All() {
+		fn, ok := lookupBuiltin(signature.Name)
+		require.True(t, ok, "no implementation for built-in %s", signature.Name)
+		assert.Equal(t, signature.Header, fn.Header)
+	}
+	for name := range builtinImpls {
+		_, ok := builtins.Lookup(name)
+		assert.True(t, ok, "built-in %s is not declared in the registry", name)
+	}
+	assert.Len(t, builtinImpls, len(builtins.All()))
+
+	err := runCodeExpectError(t, `int main() { return abs(1, 2); }`)
+	require.Error(t, err)
+	assert.Equal(t, "runtime error: function abs expects 1 arguments, got 2", err.Error())
+}
+
+func TestHeapUndefinedBehavior(t *testing.T) {
+	testCases := []struct {
+		name    string
+		code    string
+		message string
+	}{
+		{
+			name:    "use after free",
+			code:    "int main() {\n int *p = malloc(sizeof(int));\n free(p);\n *p = 1;\n return 0;\n}",
+			message: "undefined behavior: use after free of heap block allocated at line 2",
+		},
+		{
+			name:    "double free",
+			code:    "int main() {\n int *p = malloc(sizeof(int));\n free(p);\n free(p);\n return 0;\n}",
+			message: "undefined behavior: free: double free of heap block allocated at line 2",
+		},
+		{
+			name:    "heap overflow",
+			code:    "int main() {\n int *p = malloc(2 * sizeof(int));\n p[2] = 1;\n return 0;\n}",
+			message: "undefined behavior: heap buffer overflow: element 2 is outside of heap block of 2 elements allocated at line 2",
+		},
+		{
+			name:    "heap underflow",
+			code:    "int main() {\n int *p = malloc(2 * sizeof(int));\n return p[-1];\n}",
+			message: "undefined behavior: heap buffer overflow: element -1 is outside of heap block of 2 elements allocated at line 2",
+		},
+		{
+			name:    "uninitialized heap element",
+			code:    "int main() {\n int *p = malloc(2 * sizeof(int));\n return p[1];\n}",
+			message: "undefined behavior: reading uninitialized element 1 of heap block allocated at line 2",
+		},
+		{
+			name:    "null pointer dereference",
+			code:    `int main() { int *p = NULL; return *p; }`,
+			message: "undefined behavior: null pointer dereference",
+		},
+		{
+			name:    "free of pointer inside block",
+			code:    `int main() { int *p = malloc(2 * sizeof(int)); free(p + 1); return 0; }`,
+			message: "undefined behavior: free: pointer 0x18004 was not returned by malloc",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCodeExpectError(t, tt.code)
+			require.Error(t, err)
+			assert.Equal(t, tt.message, err.Error())
+		})
+	}
+}
+
 func TestComparison(t *testing.T) {
 	tests := []testCase{
 		{
@@ -1234,6 +1686,10 @@ func TestErrors(t *testing.T) {
 			name: "uninitialized variable access",
 			code: `int main() { int x; return x; }`,
 		},
+		{
+			name: "assignment to enumeration constant",
+			code: `enum Color { RED, GREEN }; int main() { RED = 1; return 0; }`,
+		},
 		{
 			name: "uninitialized variable in expression",
 			code: `int main() { int x; int y = x + 5; return y; }`,
@@ -1374,6 +1830,58 @@ func TestErrors(t *testing.T) {
 			name: "missing main function",
 			code: `int helper() { return 42; }`,
 		},
+		{
+			name: "string literal too long for array",
+			code: `int main() { char s[3] = "abcd"; return 0; }`,
+		},
+		{
+			name: "string literal for int array",
+			code: `int main() { int s[5] = "abc"; return 0; }`,
+		},
+		{
+			name: "strcpy overflow",
+			code: `int main() { char s[3]; strcpy(s, "abc"); return 0; }`,
+		},
+		{
+			name: "strlen without terminator",
+			code: `int main() { char s[3] = "abc"; return strlen(s); }`,
+		},
+		{
+			name: "strlen of int array",
+			code: `int main() { int a[3] = {1, 0, 0}; return strlen(a); }`,
+		},
+		{
+			name: "modulo with double operand",
+			code: `int main() { double a = 5; return a % 2; }`,
+		},
+		{
+			name: "double array index",
+			code: `int main() { int arr[3] = {1, 2, 3}; return arr[1.0]; }`,
+		},
+		{
+			name: "double division by zero",
+			code: `int main() { double a = 1; return a / 0; }`,
+		},
+		{
+			name: "dereference of void pointer",
+			code: `int main() { void *p = malloc(4); return *p; }`,
+		},
+		{
+			name: "incompatible pointer types",
+			code: `int main() { int *p = malloc(4); char *c = p; return 0; }`,
+		},
+		{
+			name: "integer to pointer",
+			code: `int main() { int *p = 5; return 0; }`,
+		},
+		{
+			name: "heap block reused with another type",
+			code: `int main() { void *v = malloc(8); int *p = v; double *d = v; return 0; }`,
+		},
+		{
+			name: "heap allocation limit",
+			code: `int main() { char *p = malloc(1000); return 0; }`,
+		},
 	}
 
 	for _, tt := range testCases {
@@ -1752,3 +2260,33 @@ func TestMultipleArrayDeclarations(t *testing.T) {
 		})
 	}
 }
+
+func TestOperationCounts(t *testing.T) {
+	program, convErr := converter.New().ParseToAST(`int twice(int x) {
+	return 2 * x;
+}
+int main() {
+	int a[3] = {1, 2, 3};
+	int s = 0;
+	for (int i = 0; i < 3; i++) {
+		if (a[i] != 2) {
+			s += twice(a[i]);
+		}
+	}
+	return abs(s);
+}`)
+	require.Nil(t, convErr)
+
+	runner := NewInterpreter()
+	result, _, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+	require.Equal(t, 8, *result)
+
+	// присваивания: s = 0, i = 0, три i++, два s += и два параметра x
+	assert.Equal(t, OperationCounts{Comparisons: 7, Assignments: 9, ArrayAccesses: 5, Calls: 3}, runner.Operations())
+	assert.Equal(t, 24, runner.Operations().Total())
+
+	_, _, _, err = runner.ExecuteProgram(program)
+	require.NoError(t, err)
+	assert.Equal(t, 24, runner.Operations().Total())
+}
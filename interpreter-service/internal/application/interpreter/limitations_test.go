@@ -222,7 +222,7 @@ func TestInterpreter_InfiniteExecutionStopsByStepLimit(t *testing.T) {
 		},
 		{
 			name:         "infinite while loop",
-			code:         "int main(){ while(1){} return 0; }",
+			code:         "int main(){ int i = 0; while(1){ i++; } return 0; }",
 			maxAllocated: 10,
 			maxSteps:     50,
 			expectedErr:  "too many steps in program",
@@ -273,3 +273,31 @@ func TestInterpreter_ReusedInstanceResetsStatePerExecuteProgram(t *testing.T) {
 	assert.Equal(t, 0, stepBegin2)
 	assert.NotEmpty(t, steps2)
 }
+
+func TestInterpreter_MainStepsExcludeGlobalDeclarations(t *testing.T) {
+	program, convErr := converter.New().ParseToAST(`int g = 1;
+int h = 2;
+int main() {
+	int s = 0;
+	while (1) {
+		s = s + g;
+	}
+	return s;
+}`)
+	require.Nil(t, convErr)
+
+	runner := NewInterpreterWithLimits(10, 20)
+	_, steps, _, execErr := runner.ExecuteProgram(program)
+	require.Error(t, execErr)
+	assert.Nil(t, steps)
+
+	// шаги до прерывания лимитом считаются с начала main, объявления глобальных переменных не учитываются
+	assert.Positive(t, runner.MainSteps())
+	assert.Equal(t, 2, len(runner.Steps)-runner.MainSteps())
+
+	complete, convErr := converter.New().ParseToAST("int g = 1;\nint main() { return g; }")
+	require.Nil(t, convErr)
+	_, steps, stepBegin, execErr := runner.ExecuteProgram(complete)
+	require.NoError(t, execErr)
+	assert.Equal(t, len(steps)-stepBegin, runner.MainSteps())
+}
@@ -0,0 +1,341 @@
+package interpreter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/maphash"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+// loopStates - состояния программы, встреченные на заголовке цикла за одно его выполнение
+type loopStates struct {
+	visits     map[[sha256.Size]byte]loopVisit
+	iterations int
+}
+
+// loopVisit - итерация и номер шага, на которых состояние встретилось впервые
+type loopVisit struct {
+	iteration int
+	step      int
+}
+
+func newLoopStates() *loopStates {
+	return &loopStates{visits: map[[sha256.Size]byte]loopVisit{}}
+}
+
+// checkLoopState запоминает состояние программы на заголовке цикла construct с условием cond
+// (nil для for без условия) на участке loc. Если состояние уже встречалось, выполнение повторится
+// точно так же, поэтому цикл не завершится: добавляется событие InfiniteLoop и возвращается ошибка
+func (i *Interpreter) checkLoopState(states *loopStates, construct events.BranchConstruct, loc converter.Location, cond converter.Expr) error {
+	iteration := states.iterations
+	states.iterations++
+
+	sum := i.stateHash()
+	first, seen := states.visits[sum]
+	if !seen {
+		states.visits[sum] = loopVisit{iteration: iteration, step: i.currentStepNumber}
+		return nil
+	}
+
+	cycle := iteration - first.iteration
+	unchanged := i.unchangedVariables(cond, first.step)
+	name := loopName(construct)
+	var msg string
+	switch {
+	case len(unchanged) == 1:
+		msg = fmt.Sprintf("%s is never modified in this %s loop", unchanged[0], name)
+	case len(unchanged) > 1:
+		msg = fmt.Sprintf("%s are never modified in this %s loop", strings.Join(unchanged, ", "), name)
+	case cond == nil || len(conditionVariables(cond, nil)) == 0:
+		msg = fmt.Sprintf("condition of this %s loop never changes", name)
+	default:
+		msg = fmt.Sprintf("program state repeats every %d iterations of this %s loop", cycle, name)
+	}
+	err := runtimeerrors.NewErrInfiniteLoop(fmt.Sprintf("line %d: %s", loc.Line, msg))
+
+	// шаг ошибки указывает на условие цикла
+	i.addEvents(events.LineChanged{Line: int(loc.Line)}, events.RangeChanged{Loc: loc})
+	i.currentLine = int(loc.Line)
+	i.addEvents(events.InfiniteLoop{
+		Construct: construct,
+		Loc:       loc,
+		Cycle:     cycle,
+		Unchanged: unchanged,
+		Message:   err.Error(),
+	})
+	return err
+}
+
+// unchangedVariables возвращает переменные условия cond, которые не менялись начиная с шага first
+func (i *Interpreter) unchangedVariables(cond converter.Expr, first int) []string {
+	changed := map[int]bool{}
+	collect := func(evs []events.Event) {
+		for _, ev := range evs {
+			switch e := ev.(type) {
+			case events.VarChanged:
+				changed[e.ID] = true
+			case events.ArrayElementChanged:
+				changed[e.ID] = true
+			case events.Array2DElementChanged:
+				changed[e.ID] = true
+			}
+		}
+	}
+	for _, s := range i.Steps[first:] {
+		collect(s.Events)
+	}
+	collect(i.CurrentStep.Events)
+
+	var unchanged []string
+	for _, name := range conditionVariables(cond, nil) {
+		declared, err := i.resolveVariable(name)
+		if err != nil {
+			// константа перечисления
+			continue
+		}
+		var id int
+		switch d := declared.(type) {
+		case *runtime.Variable:
+			id = d.ID
+		case *runtime.Array:
+			id = d.ID
+		case *runtime.Array2D:
+			id = d.ID
+		}
+		if !changed[id] {
+			unchanged = append(unchanged, name)
+		}
+	}
+	return unchanged
+}
+
+// conditionVariables возвращает имена всех переменных выражения в порядке первого появления
+func conditionVariables(expr converter.Expr, names []string) []string {
+	switch e := expr.(type) {
+	case *converter.VariableExpr:
+		if !slices.Contains(names, e.Name) {
+			names = append(names, e.Name)
+		}
+	case *converter.BinaryExpr:
+		names = conditionVariables(e.Left, names)
+		names = conditionVariables(e.Right, names)
+	case *converter.UnaryExpr:
+		names = conditionVariables(e.Operand, names)
+	case *converter.CastExpr:
+		names = conditionVariables(e.Operand, names)
+	case *converter.ArrayAccessExpr:
+		names = conditionVariables(e.Array, names)
+		names = conditionVariables(e.Index, names)
+	case *converter.AssignmentExpr:
+		names = conditionVariables(e.Left, names)
+		names = conditionVariables(e.Right, names)
+	case *converter.CallExpr:
+		for _, arg := range e.Arguments {
+			names = conditionVariables(arg, names)
+		}
+	}
+	return names
+}
+
+func loopName(construct events.BranchConstruct) string {
+	if construct == events.BranchDoWhile {
+		return "do-while"
+	}
+	return string(construct)
+}
+
+// stateHash вычисляет хэш состояния, от которого зависит дальнейшее выполнение: стека вызовов
+// со значениями всех видимых объявлений, кучи и генератора rand. Идентификаторы объявлений
+// и номера шагов не учитываются, ограничения интерпретатора тоже. Элементы массивов и блоков кучи
+// входят в хэш через elementDigests, поэтому стоимость не зависит от их размеров
+func (i *Interpreter) stateHash() [sha256.Size]byte {
+	s := stateHasher{h: sha256.New(), digests: i.digests}
+
+	s.scope(i.GlobalScope)
+	s.int(len(i.CallStack.Frames))
+	for _, frame := range i.CallStack.Frames {
+		s.string(frame.FuncName)
+		// первая область видимости кадра - глобальная
+		s.int(len(frame.Scopes))
+		for _, scope := range frame.Scopes[1:] {
+			s.scope(scope)
+		}
+	}
+
+	s.int(i.Heap.NextAddress())
+	s.int(len(i.Heap.Blocks))
+	for _, block := range i.Heap.Blocks {
+		s.int(block.Address)
+		s.int(block.Size)
+		s.string(string(block.Type))
+		s.int(len(block.Values))
+		s.sum(i.digests.block(block))
+	}
+	s.int(int(i.randState))
+
+	var sum [sha256.Size]byte
+	s.h.Sum(sum[:0])
+	return sum
+}
+
+type stateHasher struct {
+	h       hash.Hash
+	buf     [8]byte
+	digests *elementDigests
+}
+
+func (s *stateHasher) int(v int) {
+	s.sum(uint64(v))
+}
+
+func (s *stateHasher) sum(v uint64) {
+	binary.LittleEndian.PutUint64(s.buf[:], v)
+	s.h.Write(s.buf[:])
+}
+
+func (s *stateHasher) string(v string) {
+	s.int(len(v))
+	s.h.Write([]byte(v))
+}
+
+func (s *stateHasher) value(v *runtime.Value) {
+	if v == nil {
+		s.int(0)
+		return
+	}
+	s.int(1)
+	s.string(string(v.Type))
+	s.int(v.Int)
+	s.int(int(math.Float64bits(v.Float)))
+}
+
+func (s *stateHasher) scope(scope *runtime.Scope) {
+	s.int(len(scope.Declarations.Declarations))
+	for _, d := range scope.Declarations.Declarations {
+		switch d := d.(type) {
+		case *runtime.Variable:
+			s.string(d.Name)
+			s.value(d.Value)
+		case *runtime.Array:
+			s.string(d.Name)
+			s.int(len(d.Values))
+			s.sum(s.digests.array(d))
+		case *runtime.Array2D:
+			s.string(d.Name)
+			s.int(d.Size1)
+			s.int(d.Size2)
+			s.sum(s.digests.array2D(d))
+		}
+	}
+}
+
+// elementDigests - хэши элементов массивов (по идентификатору объявления) и блоков кучи (по адресу).
+// Хэш набора элементов - сумма хэшей пар (индекс, значение): он вычисляется целиком при первом
+// обращении и дальше обновляется по событиям изменения элементов, которые добавляет addEvents
+type elementDigests struct {
+	seed   maphash.Seed
+	arrays map[int]*elementsDigest
+	blocks map[int]*elementsDigest
+	buf    []byte
+	hashed int // число вычисленных хэшей элементов
+}
+
+// elementsDigest - хэши элементов и их сумма. Элементы двумерного массива нумеруются по строкам длины width
+type elementsDigest struct {
+	hashes []uint64
+	sum    uint64
+	width  int
+}
+
+func newElementDigests() *elementDigests {
+	return &elementDigests{
+		seed:   maphash.MakeSeed(),
+		arrays: map[int]*elementsDigest{},
+		blocks: map[int]*elementsDigest{},
+	}
+}
+
+// observe обновляет вычисленные хэши по событиям evs
+func (d *elementDigests) observe(evs []events.Event) {
+	for _, ev := range evs {
+		switch e := ev.(type) {
+		case events.ArrayElementChanged:
+			d.update(d.arrays[e.ID], e.Ind, &e.Value)
+		case events.Array2DElementChanged:
+			if digest, ok := d.arrays[e.ID]; ok {
+				d.update(digest, e.Ind1*digest.width+e.Ind2, &e.Value)
+			}
+		case events.HeapElementChanged:
+			d.update(d.blocks[e.Address], e.Ind, &e.Value)
+		case events.HeapBlockTyped:
+			// приведение типа заново создает элементы блока
+			delete(d.blocks, e.Address)
+		case events.HeapFree:
+			delete(d.blocks, e.Address)
+		case events.VariableDestroyed:
+			delete(d.arrays, e.ID)
+		}
+	}
+}
+
+func (d *elementDigests) array(a *runtime.Array) uint64 {
+	return d.lookup(d.arrays, a.ID, len(a.Values), 0, func(ind int) *runtime.Value {
+		return a.Values[ind].Value
+	})
+}
+
+func (d *elementDigests) array2D(a *runtime.Array2D) uint64 {
+	return d.lookup(d.arrays, a.ID, a.Size1*a.Size2, a.Size2, func(ind int) *runtime.Value {
+		return a.Values[ind/a.Size2].Values[ind%a.Size2].Value
+	})
+}
+
+func (d *elementDigests) block(b *runtime.HeapBlock) uint64 {
+	return d.lookup(d.blocks, b.Address, len(b.Values), 0, func(ind int) *runtime.Value {
+		return b.Values[ind].Value
+	})
+}
+
+// lookup возвращает сумму хэшей count элементов с ключом key, вычисляя их при первом обращении
+func (d *elementDigests) lookup(digests map[int]*elementsDigest, key, count, width int, at func(int) *runtime.Value) uint64 {
+	if digest, ok := digests[key]; ok && len(digest.hashes) == count {
+		return digest.sum
+	}
+	digest := &elementsDigest{hashes: make([]uint64, count), width: width}
+	for ind := range count {
+		digest.hashes[ind] = d.element(ind, at(ind))
+		digest.sum += digest.hashes[ind]
+	}
+	digests[key] = digest
+	return digest.sum
+}
+
+// update заменяет хэш элемента ind вычисленного набора digest (nil, если набор еще не вычислялся)
+func (d *elementDigests) update(digest *elementsDigest, ind int, v *runtime.Value) {
+	if digest == nil || ind < 0 || ind >= len(digest.hashes) {
+		return
+	}
+	h := d.element(ind, v)
+	digest.sum += h - digest.hashes[ind]
+	digest.hashes[ind] = h
+}
+
+func (d *elementDigests) element(ind int, v *runtime.Value) uint64 {
+	d.hashed++
+	d.buf = binary.LittleEndian.AppendUint64(d.buf[:0], uint64(ind))
+	if v != nil {
+		d.buf = append(d.buf, string(v.Type)...)
+		d.buf = binary.LittleEndian.AppendUint64(d.buf, uint64(v.Int))
+		d.buf = binary.LittleEndian.AppendUint64(d.buf, math.Float64bits(v.Float))
+	}
+	return maphash.Bytes(d.seed, d.buf)
+}
@@ -0,0 +1,59 @@
+package interpreter
+
+import (
+	"slices"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+)
+
+// markIndex в режиме маркеров индексов фиксирует событием ArrayIndexed каждую переменную индекса expr.
+// marker задает массив, измерение и элемент обращения; смещение вычисляется по текущему значению переменной
+func (i *Interpreter) markIndex(expr converter.Expr, marker events.ArrayIndexed) {
+	if !i.indexMarkers {
+		return
+	}
+
+	for _, name := range indexVariables(expr, nil) {
+		v, err := i.resolveVariable(name)
+		if err != nil {
+			continue
+		}
+		variable, ok := v.(*runtime.Variable)
+		if !ok || variable.Type.IsPointer() {
+			continue
+		}
+		value, err := variable.GetValue()
+		if err != nil {
+			continue
+		}
+		current, err := value.AsInt()
+		if err != nil {
+			continue
+		}
+
+		marker.Variable, marker.Offset = name, marker.Index-current
+		i.addEvents(marker)
+	}
+}
+
+// indexVariables возвращает имена переменных индексного выражения в порядке первого появления.
+// Индексы вложенных обращений к массивам и аргументы вызовов не учитываются:
+// в a[b[i]] переменная i индексирует b, а не a
+func indexVariables(expr converter.Expr, names []string) []string {
+	switch e := expr.(type) {
+	case *converter.VariableExpr:
+		if !slices.Contains(names, e.Name) {
+			names = append(names, e.Name)
+		}
+	case *converter.BinaryExpr:
+		names = indexVariables(e.Left, names)
+		names = indexVariables(e.Right, names)
+	case *converter.UnaryExpr:
+		names = indexVariables(e.Operand, names)
+	case *converter.CastExpr:
+		names = indexVariables(e.Operand, names)
+	}
+	return names
+}
@@ -0,0 +1,22 @@
+package interpreter
+
+// OperationCounts - число элементарных операций, выполненных программой: по нему оценивается
+// сложность алгоритма. Присваивания включают инициализацию переменных при объявлении, ++/-- и передачу аргументов,
+// обращения к массивам - чтения и записи элементов массивов и блоков кучи по индексу,
+// вызовы - вызовы функций программы (кроме main) и встроенных функций
+type OperationCounts struct {
+	Comparisons   int `json:"comparisons"`
+	Assignments   int `json:"assignments"`
+	ArrayAccesses int `json:"array_accesses"`
+	Calls         int `json:"calls"`
+}
+
+// Total возвращает общее число операций
+func (c OperationCounts) Total() int {
+	return c.Comparisons + c.Assignments + c.ArrayAccesses + c.Calls
+}
+
+// Operations возвращает число операций последнего выполнения программы
+func (i *Interpreter) Operations() OperationCounts {
+	return i.operations
+}
@@ -0,0 +1,305 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+// convert приводит значение к типу t. Приведение void* к T* задает тип блока кучи,
+// на который указывает указатель (так результат malloc становится массивом элементов T)
+func (i *Interpreter) convert(value runtime.Value, t runtime.Type) (runtime.Value, error) {
+	converted, err := value.ConvertTo(t)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+
+	if value.Type == runtime.TypeVoidPtr && t.IsPointer() && t != runtime.TypeVoidPtr && value.Int != 0 {
+		if err := i.typeHeapBlock(value.Int, t.Pointee()); err != nil {
+			return runtime.Value{}, err
+		}
+	}
+
+	return converted, nil
+}
+
+func (i *Interpreter) typeHeapBlock(address int, elemType runtime.Type) error {
+	block, err := i.liveHeapBlock(address)
+	if err != nil {
+		return err
+	}
+	if block.Type == elemType {
+		return nil
+	}
+	if block.Type != "" {
+		return runtimeerrors.NewErrRuntime(fmt.Sprintf("heap block allocated at line %d already holds %s values, cannot use it as %s", block.Line, block.Type, elemType))
+	}
+
+	if err := block.SetType(elemType, i.currentStepNumber); err != nil {
+		return err
+	}
+	i.addEvents(events.HeapBlockTyped{Address: block.Address, Type: elemType})
+
+	return nil
+}
+
+// liveHeapBlock возвращает неосвобожденный блок кучи, к которому относится адрес
+func (i *Interpreter) liveHeapBlock(address int) (*runtime.HeapBlock, error) {
+	block, ok := i.Heap.Find(address)
+	if !ok {
+		return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("invalid pointer %s", runtime.FormatAddress(address)))
+	}
+	if block.Freed {
+		return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("use after free of heap block allocated at line %d", block.Line))
+	}
+	return block, nil
+}
+
+// heapElement возвращает элемент кучи pointer[ind]
+func (i *Interpreter) heapElement(pointer runtime.Value, ind int) (lvalue, error) {
+	if !pointer.Type.IsPointer() {
+		return lvalue{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("cannot dereference value of type %s", pointer.Type))
+	}
+	elemType := pointer.Type.Pointee()
+	if elemType == runtime.TypeVoid {
+		return lvalue{}, runtimeerrors.NewErrRuntime("dereferencing void* pointer")
+	}
+	if pointer.Int == 0 {
+		return lvalue{}, runtimeerrors.NewErrUndefinedBehavior("null pointer dereference")
+	}
+
+	size, err := runtime.SizeOf(elemType)
+	if err != nil {
+		return lvalue{}, err
+	}
+
+	address := pointer.Int + ind*size
+	block, err := i.liveHeapBlock(address)
+	if err != nil {
+		return lvalue{}, err
+	}
+	if block.Type != elemType {
+		return lvalue{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("heap block allocated at line %d holds %s values, not %s", block.Line, block.Type, elemType))
+	}
+
+	offset := address - block.Address
+	if offset < 0 || offset >= len(block.Values)*size {
+		return lvalue{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("heap buffer overflow: element %d is outside of heap block of %d elements allocated at line %d", offset/size, len(block.Values), block.Line))
+	}
+	if offset%size != 0 {
+		return lvalue{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("misaligned access to heap block allocated at line %d", block.Line))
+	}
+
+	ind = offset / size
+	return lvalue{target: &block.Values[ind], valueType: elemType, name: runtime.FormatAddress(block.Address), indices: []int{ind}, block: block}, nil
+}
+
+// executePointerOperand вычисляет указатель, к элементу которого обращаются через p[i].
+// Возвращает false, если выражение не является указателем и обрабатывается как массив
+func (i *Interpreter) executePointerOperand(expr converter.Expr) (runtime.Value, bool, error) {
+	switch e := expr.(type) {
+	case *converter.VariableExpr:
+		v, err := i.resolveVariable(e.Name)
+		if err != nil {
+			return runtime.Value{}, false, err
+		}
+		variable, ok := v.(*runtime.Variable)
+		if !ok || !variable.Type.IsPointer() {
+			return runtime.Value{}, false, nil
+		}
+		value, err := variable.GetValue()
+		return value, true, err
+	case *converter.ArrayAccessExpr:
+		return runtime.Value{}, false, nil
+	default:
+		value, err := i.executeExpression(expr)
+		return value, true, err
+	}
+}
+
+// applyPointerOperator вычисляет адресную арифметику и сравнение указателей.
+// Смещение масштабируется размером элемента, разность указателей дает число элементов
+func applyPointerOperator(operator string, left, right runtime.Value) (runtime.Value, error) {
+	invalid := runtimeerrors.NewErrRuntime(fmt.Sprintf("invalid operands to binary %s (have %s and %s)", operator, left.Type, right.Type))
+
+	switch operator {
+	case "+", "-":
+		if left.Type.IsPointer() && right.Type.IsPointer() {
+			if operator != "-" || left.Type != right.Type {
+				return runtime.Value{}, invalid
+			}
+			size, err := pointeeSize(left.Type)
+			if err != nil {
+				return runtime.Value{}, err
+			}
+			return runtime.NewIntValue((left.Int - right.Int) / size), nil
+		}
+
+		pointer, offset := left, right
+		if right.Type.IsPointer() {
+			if operator == "-" {
+				return runtime.Value{}, invalid
+			}
+			pointer, offset = right, left
+		}
+		n, err := offset.AsInt()
+		if err != nil {
+			return runtime.Value{}, invalid
+		}
+		size, err := pointeeSize(pointer.Type)
+		if err != nil {
+			return runtime.Value{}, err
+		}
+		if operator == "-" {
+			n = -n
+		}
+		return runtime.NewPointerValue(pointer.Type, pointer.Int+n*size), nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		if !comparablePointers(left, right) {
+			return runtime.Value{}, invalid
+		}
+		return compareAddresses(operator, left.Int, right.Int), nil
+	default:
+		return runtime.Value{}, invalid
+	}
+}
+
+// comparablePointers сообщает, можно ли сравнивать операнды: указатели одного типа,
+// указатель и void* или указатель и целый 0
+func comparablePointers(left, right runtime.Value) bool {
+	if !left.Type.IsPointer() {
+		left, right = right, left
+	}
+	if !right.Type.IsPointer() {
+		return right.Type.IsInteger() && right.Int == 0
+	}
+	return left.Type == right.Type || left.Type == runtime.TypeVoidPtr || right.Type == runtime.TypeVoidPtr
+}
+
+func compareAddresses(operator string, left, right int) runtime.Value {
+	switch operator {
+	case "==":
+		return boolValue(left == right)
+	case "!=":
+		return boolValue(left != right)
+	case "<":
+		return boolValue(left < right)
+	case "<=":
+		return boolValue(left <= right)
+	case ">":
+		return boolValue(left > right)
+	default:
+		return boolValue(left >= right)
+	}
+}
+
+func pointeeSize(t runtime.Type) (int, error) {
+	if t == runtime.TypeVoidPtr {
+		return 0, runtimeerrors.NewErrRuntime("pointer arithmetic on void* pointer")
+	}
+	return runtime.SizeOf(t.Pointee())
+}
+
+func (i *Interpreter) executeCastExpr(e *converter.CastExpr) (runtime.Value, error) {
+	value, err := i.executeExpression(e.Operand)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+
+	t := typeOf(e.TargetType)
+	if t == runtime.TypeVoid {
+		return runtime.VoidValue(), nil
+	}
+	if value.IsVoid() {
+		return runtime.Value{}, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
+	}
+
+	return i.convert(value, t)
+}
+
+// executeSizeofExpr вычисляет sizeof типа или выражения (выражение при этом не вычисляется)
+func (i *Interpreter) executeSizeofExpr(e *converter.SizeofExpr) (runtime.Value, error) {
+	var t runtime.Type
+	var dims []int
+	if e.ArgType != nil {
+		t, dims = typeOf(*e.ArgType), e.ArgType.ArraySizes
+	} else {
+		var err error
+		t, dims, err = i.staticType(e.Operand)
+		if err != nil {
+			return runtime.Value{}, err
+		}
+	}
+
+	size, err := runtime.SizeOf(t)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	for _, dim := range dims {
+		size *= dim
+	}
+
+	return runtime.NewIntValue(size), nil
+}
+
+// staticType определяет тип выражения без его вычисления: тип элементов и размеры массива
+func (i *Interpreter) staticType(expr converter.Expr) (runtime.Type, []int, error) {
+	switch e := expr.(type) {
+	case *converter.IntLiteral, *converter.CharLiteral:
+		// символьная константа в C имеет тип int
+		return runtime.TypeInt, nil, nil
+	case *converter.FloatLiteral:
+		if e.IsFloat {
+			return runtime.TypeFloat, nil, nil
+		}
+		return runtime.TypeDouble, nil, nil
+	case *converter.StringLiteral:
+		return runtime.TypeChar, []int{len(e.Value) + 1}, nil
+	case *converter.NullLiteral:
+		return runtime.TypeVoidPtr, nil, nil
+	case *converter.CastExpr:
+		return typeOf(e.TargetType), nil, nil
+	case *converter.VariableExpr:
+		v, err := i.resolveVariable(e.Name)
+		if err != nil {
+			if _, ok := i.CallStack.GetCurrentFrame().GetEnumConstant(e.Name); ok {
+				return runtime.TypeInt, nil, nil
+			}
+			return "", nil, err
+		}
+		switch x := v.(type) {
+		case *runtime.Variable:
+			return x.Type, nil, nil
+		case *runtime.Array:
+			return x.Type, []int{x.Size}, nil
+		case *runtime.Array2D:
+			return x.Type, []int{x.Size1, x.Size2}, nil
+		}
+	case *converter.ArrayAccessExpr:
+		return i.staticElementType(e.Array)
+	case *converter.UnaryExpr:
+		if e.Operator == "*" {
+			return i.staticElementType(e.Operand)
+		}
+	}
+
+	return "", nil, runtimeerrors.NewErrRuntime(fmt.Sprintf("sizeof of expression %T is not supported", expr))
+}
+
+// staticElementType определяет тип элемента массива или значения, на которое указывает указатель
+func (i *Interpreter) staticElementType(expr converter.Expr) (runtime.Type, []int, error) {
+	t, dims, err := i.staticType(expr)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(dims) > 0 {
+		return t, dims[1:], nil
+	}
+	if t.IsPointer() {
+		return t.Pointee(), nil, nil
+	}
+	return "", nil, runtimeerrors.NewErrRuntime(fmt.Sprintf("value of type %s is not an array or pointer", t))
+}
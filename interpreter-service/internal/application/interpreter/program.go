@@ -29,12 +29,18 @@ func (i *Interpreter) ExecuteProgram(program *converter.Program) (*int, []step.S
 			if err != nil {
 				return nil, nil, 0, err
 			}
+		case *converter.EnumDecl, *converter.TypedefDecl:
+			_, err := i.executeStatement(d)
+			if err != nil {
+				return nil, nil, 0, err
+			}
 		default:
 			return nil, nil, 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unsupported top-level declaration type %T", decl))
 		}
 	}
 
 	stepBegin := i.currentStepNumber
+	i.mainStepBegin = stepBegin
 
 	if _, ok := i.Functions["main"]; !ok {
 		return nil, nil, 0, runtimeerrors.NewErrUnexpectedInternalError("entrypoint function main not found")
@@ -46,8 +52,33 @@ func (i *Interpreter) ExecuteProgram(program *converter.Program) (*int, []step.S
 	if err != nil {
 		var unfErr runtimeerrors.ErrUndefinedBehavior
 		var runErr runtimeerrors.ErrRuntime
+		var assertErr runtimeerrors.ErrAssertionFailed
+		var loopErr runtimeerrors.ErrInfiniteLoop
+		var exit exitSignal
+
+		if errors.As(err, &exit) {
+			// exit завершает программу с кодом возврата, как возврат из main
+			if unwindErr := i.unwindFrames(); unwindErr != nil {
+				return nil, nil, 0, unwindErr
+			}
+			i.stepKind = step.KindProgramEnd
+			if stepErr := i.addStep(); stepErr != nil {
+				return nil, nil, 0, stepErr
+			}
+			if leakErr := i.reportLeaks(); leakErr != nil {
+				return nil, nil, 0, leakErr
+			}
+			return &exit.status, i.Steps, stepBegin, nil
+		}
 
-		if errors.As(err, &unfErr) {
+		i.stepKind = step.KindError
+		if errors.As(err, &assertErr) || errors.As(err, &loopErr) {
+			// события AssertionFailed и InfiniteLoop уже добавлены реализацией assert и проверкой цикла
+			if stepErr := i.addStep(); stepErr != nil {
+				return nil, nil, 0, stepErr
+			}
+			return nil, i.Steps, stepBegin, err
+		} else if errors.As(err, &unfErr) {
 			i.addEvents(events.UndefinedBehavior{Message: err.Error()})
 			if stepErr := i.addStep(); stepErr != nil {
 				return nil, nil, 0, stepErr
@@ -64,15 +95,27 @@ func (i *Interpreter) ExecuteProgram(program *converter.Program) (*int, []step.S
 		}
 	}
 
-	switch v := value.(type) {
-	case nil:
+	if err := i.reportLeaks(); err != nil {
+		return nil, nil, 0, err
+	}
+
+	if value.IsVoid() {
 		return nil, i.Steps, stepBegin, nil
-	case *int:
-		return v, i.Steps, stepBegin, nil
-	case int:
-		result := v
-		return &result, i.Steps, stepBegin, nil
-	default:
-		return nil, nil, 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unexpected main return type %T", value))
 	}
+
+	result, err := value.AsInt()
+	if err != nil {
+		return nil, nil, 0, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unexpected main return type %s", value.Type))
+	}
+
+	return &result, i.Steps, stepBegin, nil
+}
+
+// MainSteps возвращает число записанных шагов, начиная с первого шага main. Для выполнения,
+// прерванного лимитом, учитываются шаги до прерывания
+func (i *Interpreter) MainSteps() int {
+	if i.mainStepBegin < 0 {
+		return 0
+	}
+	return max(len(i.Steps)-i.mainStepBegin, 0)
 }
@@ -0,0 +1,69 @@
+package interpreter
+
+import (
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+)
+
+// scopeInfo описывает область видимости вида kind, принадлежащую узлу owner,
+// объявления которой видны на строках lines
+func scopeInfo(kind runtime.ScopeKind, owner converter.Location, lines converter.Location) runtime.ScopeInfo {
+	return runtime.ScopeInfo{Kind: kind, Owner: &owner, StartLine: int(lines.Line), EndLine: int(lines.EndLine)}
+}
+
+// enterScope открывает область видимости в текущем кадре
+func (i *Interpreter) enterScope(info runtime.ScopeInfo) {
+	i.CallStack.GetCurrentFrame().EnterScope().ScopeInfo = info
+	i.addEvents(events.EnterScope{ScopeInfo: info})
+}
+
+// exitScope закрывает текущую область видимости кадра. Ее переменные и массивы
+// уничтожаются в порядке, обратном объявлению
+func (i *Interpreter) exitScope() error {
+	frame := i.CallStack.GetCurrentFrame()
+	scope := frame.GetCurrentScope()
+	if err := frame.ExitScope(); err != nil {
+		return err
+	}
+
+	declarations := scope.Declarations.Declarations
+	for ind := len(declarations) - 1; ind >= 0; ind-- {
+		switch d := declarations[ind].(type) {
+		case *runtime.Variable:
+			i.addEvents(events.VariableDestroyed{ID: d.ID, Name: d.Name})
+		case *runtime.Array:
+			i.addEvents(events.VariableDestroyed{ID: d.ID, Name: d.Name})
+		case *runtime.Array2D:
+			i.addEvents(events.VariableDestroyed{ID: d.ID, Name: d.Name})
+		}
+	}
+	i.addEvents(events.ExitScope{Kind: scope.Kind})
+	return nil
+}
+
+// exitFrameScopes закрывает все области видимости текущего кадра, кроме глобальной:
+// при return и при завершении функции без return, до снятия кадра со стека
+func (i *Interpreter) exitFrameScopes() error {
+	for len(i.CallStack.GetCurrentFrame().Scopes) > 1 {
+		if err := i.exitScope(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwindFrames снимает все кадры вызовов от внутреннего к main при exit: в каждом кадре
+// закрываются области видимости и фиксируется возврат без значения
+func (i *Interpreter) unwindFrames() error {
+	for i.CallStack.FramesCount() > 1 {
+		frame := i.CallStack.GetCurrentFrame()
+		if err := i.exitFrameScopes(); err != nil {
+			return err
+		}
+		i.addEvents(events.FunctionReturn{Name: frame.FuncName, ReturnValue: nil, CallerLine: i.callLines[len(i.callLines)-1]})
+		i.CallStack.PopFrame()
+		i.callLines = i.callLines[:len(i.callLines)-1]
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
 
 func (i *Interpreter) executeStatement(stmt converter.Stmt) (ExecResult, error) {
@@ -35,6 +36,11 @@ func (i *Interpreter) executeStatement(stmt converter.Stmt) (ExecResult, error)
 		return i.executeBreakStmt(t)
 	case *converter.ContinueStmt:
 		return i.executeContinueStmt(t)
+	case *converter.EnumDecl:
+		return i.executeEnumDecl(t)
+	case *converter.TypedefDecl:
+		// псевдонимы раскрыты конвертером, во время выполнения typedef ничего не делает
+		return NormalResult(), nil
 
 	default:
 		return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown statement type %T", stmt))
@@ -42,8 +48,7 @@ func (i *Interpreter) executeStatement(stmt converter.Stmt) (ExecResult, error)
 }
 
 func (i *Interpreter) executeNonFunctionDecl(v *VariableDecl) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(v.Loc.Line)})
-	if err := i.addStep(); err != nil {
+	if err := i.stepTo(declarationRange(v), step.KindDeclaration); err != nil {
 		return NormalResult(), err
 	}
 
@@ -59,60 +64,109 @@ func (i *Interpreter) executeNonFunctionDecl(v *VariableDecl) (ExecResult, error
 	}
 }
 
+// declarationRange возвращает участок объявления вместе с инициализатором: в объявлении
+// нескольких переменных участок каждой из них содержит только ее декларатор
+func declarationRange(v *VariableDecl) converter.Location {
+	loc := v.Loc
+	if v.InitExpr != nil {
+		initLoc := v.InitExpr.GetLocation()
+		if initLoc.EndLine > loc.EndLine || initLoc.EndLine == loc.EndLine && initLoc.EndColumn > loc.EndColumn {
+			loc.EndLine, loc.EndColumn = initLoc.EndLine, initLoc.EndColumn
+		}
+	}
+	return loc
+}
+
 func (i *Interpreter) executeVariableDecl(v VariableDecl) (ExecResult, error) {
 	if err := i.LimitManager.AllocateVariable(); err != nil {
 		return NormalResult(), err
 	}
 
-	var value *int
+	varType := typeOf(v.VarType)
+
+	var value *runtime.Value
 	if v.InitExpr != nil {
 		val, err := i.executeExpression(v.InitExpr)
 		if err != nil {
 			return NormalResult(), err
 		}
-		v, ok := val.(int)
-		if !ok {
-			return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
+		val, err = i.convertValue(val, varType)
+		if err != nil {
+			return NormalResult(), err
 		}
-		value = &v
+		value = &val
+		i.operations.Assignments++
 	}
 
-	variable := runtime.NewVariable(v.Name, value, i.currentStepNumber, v.IsGlobal)
+	enum, err := i.resolveEnum(v.VarType)
+	if err != nil {
+		return NormalResult(), err
+	}
+
+	variable := runtime.NewVariable(v.Name, varType, value, i.currentStepNumber, v.IsGlobal)
+	variable.ID = i.nextDeclarationID()
+	variable.Enum = enum
 
 	frame := i.CallStack.GetCurrentFrame()
 	currentScope := frame.GetCurrentScope()
 	currentScope.Declare(variable)
 
-	i.addEvents(events.DeclareVar{Name: v.Name, Value: cloneValue(value), IsGlobal: v.IsGlobal})
+	i.addEvents(events.DeclareVar{ID: variable.ID, Name: v.Name, Type: varType, Value: runtime.CloneValue(value), IsGlobal: v.IsGlobal, Enum: enum})
+
+	return NormalResult(), nil
+}
+
+// executeEnumDecl делает константы перечисления видимыми в текущей области видимости.
+// Значения констант вычислены конвертером, поэтому шаг выполнения не создается
+func (i *Interpreter) executeEnumDecl(e *converter.EnumDecl) (ExecResult, error) {
+	enumerators := make([]runtime.Enumerator, len(e.Enumerators))
+	for ind, en := range e.Enumerators {
+		enumerators[ind] = runtime.Enumerator{Name: en.Name, Value: en.Value}
+	}
+
+	i.CallStack.GetCurrentFrame().GetCurrentScope().DeclareEnum(runtime.NewEnum(e.Name, enumerators))
 
 	return NormalResult(), nil
 }
 
+// resolveEnum возвращает перечисление для enum-типа или nil для остальных типов
+func (i *Interpreter) resolveEnum(t converter.Type) (*runtime.Enum, error) {
+	if t.Enum == "" {
+		return nil, nil
+	}
+
+	enum, ok := i.CallStack.GetCurrentFrame().GetEnum(t.Enum)
+	if !ok {
+		return nil, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("unknown enum %s", t.Enum))
+	}
+
+	return enum, nil
+}
+
 func (i *Interpreter) executeArrayDecl(v VariableDecl) (ExecResult, error) {
 	if err := i.LimitManager.AllocateArray(v.VarType.ArraySizes[0]); err != nil {
 		return NormalResult(), err
 	}
 
+	elemType := typeOf(v.VarType)
+
 	var value []runtime.ArrayElement
 	if v.InitExpr != nil {
-		val, err := i.executeExpression(v.InitExpr)
+		elements, err := i.executeArrayInitializer(v.InitExpr, elemType, v.VarType.ArraySizes[0])
 		if err != nil {
 			return NormalResult(), err
 		}
-		v, ok := val.([]runtime.ArrayElement)
-		if !ok {
-			return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("types mismatch: expected []ArrayElement, got %T", val))
-		}
-		value = v
+		value = elements
 	}
 
-	variable := runtime.NewArray(v.Name, v.VarType.ArraySizes[0], value, i.currentStepNumber, v.IsGlobal)
+	variable := runtime.NewArray(v.Name, elemType, v.VarType.ArraySizes[0], value, i.currentStepNumber, v.IsGlobal)
+	variable.ID = i.nextDeclarationID()
 
 	frame := i.CallStack.GetCurrentFrame()
 	currentScope := frame.GetCurrentScope()
 	currentScope.Declare(variable)
 
-	i.addEvents(events.DeclareArray{Name: v.Name, Value: cloneArrayElements(value), Size: v.VarType.ArraySizes[0], IsGlobal: v.IsGlobal})
+	i.addEvents(events.DeclareArray{ID: variable.ID, Name: v.Name, Type: elemType, Value: cloneArrayElements(value), Size: v.VarType.ArraySizes[0], IsGlobal: v.IsGlobal})
 
 	return NormalResult(), nil
 }
@@ -122,34 +176,122 @@ func (i *Interpreter) executeArray2DDecl(v VariableDecl) (ExecResult, error) {
 		return NormalResult(), err
 	}
 
+	elemType := typeOf(v.VarType)
+
 	var value []runtime.Array
 	if v.InitExpr != nil {
-		val, err := i.executeExpression(v.InitExpr)
+		rows, err := i.executeArray2DInitializer(v.InitExpr, elemType, v.VarType.ArraySizes[0], v.VarType.ArraySizes[1])
 		if err != nil {
 			return NormalResult(), err
 		}
-		v, ok := val.([]runtime.Array)
-		if !ok {
-			return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
-		}
-		value = v
+		value = rows
 	}
 
-	variable := runtime.NewArray2D(v.Name, v.VarType.ArraySizes[0], v.VarType.ArraySizes[1], value, i.currentStepNumber, v.IsGlobal)
+	variable := runtime.NewArray2D(v.Name, elemType, v.VarType.ArraySizes[0], v.VarType.ArraySizes[1], value, i.currentStepNumber, v.IsGlobal)
+	variable.ID = i.nextDeclarationID()
 
 	frame := i.CallStack.GetCurrentFrame()
 	currentScope := frame.GetCurrentScope()
 	currentScope.Declare(variable)
 
-	i.addEvents(events.DeclareArray2D{Name: v.Name, Value: cloneArrayRows(value), Size1: v.VarType.ArraySizes[0], Size2: v.VarType.ArraySizes[1], IsGlobal: v.IsGlobal})
+	i.addEvents(events.DeclareArray2D{ID: variable.ID, Name: v.Name, Type: elemType, Value: cloneArrayRows(value), Size1: v.VarType.ArraySizes[0], Size2: v.VarType.ArraySizes[1], IsGlobal: v.IsGlobal})
 
 	return NormalResult(), nil
 }
 
+// executeArrayInitializer вычисляет список инициализации массива размера size с элементами типа elemType.
+// Недостающие элементы инициализируются нулем, как в C
+func (i *Interpreter) executeArrayInitializer(expr converter.Expr, elemType runtime.Type, size int) ([]runtime.ArrayElement, error) {
+	var values []runtime.Value
+	var err error
+	switch init := expr.(type) {
+	case *converter.ArrayInitExpr:
+		values, err = i.executeArrayInitExpr(init)
+		if err != nil {
+			return nil, err
+		}
+	case *converter.StringLiteral:
+		values, err = stringInitializer(init, elemType, size)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, runtimeerrors.NewErrRuntime("array must be initialized with an initializer list")
+	}
+
+	if len(values) > size {
+		return nil, runtimeerrors.NewErrRuntime(fmt.Sprintf("too many initializers for array of size %d", size))
+	}
+
+	elements := make([]runtime.ArrayElement, size)
+	for ind := range elements {
+		value := runtime.ZeroValue(elemType)
+		if ind < len(values) {
+			value, err = i.convertValue(values[ind], elemType)
+			if err != nil {
+				return nil, err
+			}
+		}
+		elements[ind] = *runtime.NewArrayElement(elemType, &value, 0, false)
+	}
+
+	return elements, nil
+}
+
+// stringInitializer возвращает символы строкового литерала с завершающим нулем.
+// Как в C, нуль опускается, если строка занимает массив целиком
+func stringInitializer(lit *converter.StringLiteral, elemType runtime.Type, size int) ([]runtime.Value, error) {
+	if elemType != runtime.TypeChar {
+		return nil, runtimeerrors.NewErrRuntime(fmt.Sprintf("array of %s initialized from string literal", elemType))
+	}
+	if len(lit.Value) > size {
+		return nil, runtimeerrors.NewErrRuntime(fmt.Sprintf("initializer-string for char array of size %d is too long", size))
+	}
+
+	values := make([]runtime.Value, 0, len(lit.Value)+1)
+	for ind := 0; ind < len(lit.Value); ind++ {
+		values = append(values, runtime.NewCharValue(int(lit.Value[ind])))
+	}
+	if len(values) < size {
+		values = append(values, runtime.NewCharValue(0))
+	}
+	return values, nil
+}
+
+// executeArray2DInitializer вычисляет вложенный список инициализации двумерного массива
+func (i *Interpreter) executeArray2DInitializer(expr converter.Expr, elemType runtime.Type, size1, size2 int) ([]runtime.Array, error) {
+	initList, ok := expr.(*converter.ArrayInitExpr)
+	if !ok {
+		return nil, runtimeerrors.NewErrRuntime("array2d must be initialized with an initializer list")
+	}
+
+	if len(initList.Elements) > size1 {
+		return nil, runtimeerrors.NewErrRuntime(fmt.Sprintf("too many initializers for array2d with %d rows", size1))
+	}
+
+	rows := make([]runtime.Array, size1)
+	for ind := range rows {
+		var rowInit converter.Expr = &converter.ArrayInitExpr{Type: "ArrayInitExpr"}
+		if ind < len(initList.Elements) {
+			rowInit = initList.Elements[ind]
+		}
+		elements, err := i.executeArrayInitializer(rowInit, elemType, size2)
+		if err != nil {
+			return nil, err
+		}
+		rows[ind] = *runtime.NewArray("", elemType, size2, elements, 0, false)
+	}
+
+	return rows, nil
+}
+
 func (i *Interpreter) executeBlockStmt(b *converter.BlockStmt) (ExecResult, error) {
-	frame := i.CallStack.GetCurrentFrame()
-	frame.EnterScope()
-	i.addEvents(events.EnterScope{})
+	return i.executeBlock(b, runtime.ScopeBlock)
+}
+
+// executeBlock выполняет составной оператор в новой области видимости вида kind
+func (i *Interpreter) executeBlock(b *converter.BlockStmt, kind runtime.ScopeKind) (ExecResult, error) {
+	i.enterScope(scopeInfo(kind, b.Loc, b.Loc))
 
 	for _, stmt := range b.Statements {
 		res, err := i.executeStatement(stmt)
@@ -157,37 +299,30 @@ func (i *Interpreter) executeBlockStmt(b *converter.BlockStmt) (ExecResult, erro
 			return res, err
 		}
 		if res.Signal != SignalNormal {
+			// при return области видимости кадра уже закрыты оператором return
 			if res.Signal != SignalReturn {
-				frame.ExitScope()
-				i.addEvents(events.ExitScope{})
+				if err := i.exitScope(); err != nil {
+					return res, err
+				}
 			}
 			return res, nil
 		}
 	}
 
-	frame.ExitScope()
-	i.addEvents(events.ExitScope{})
-
-	return NormalResult(), nil
+	return NormalResult(), i.exitScope()
 }
 
 func (i *Interpreter) executeIfStmt(ifStmt *converter.IfStmt) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(ifStmt.Loc.Line)})
-	if err := i.addStep(); err != nil {
+	if err := i.stepTo(ifStmt.Condition.GetLocation(), step.KindCondition); err != nil {
 		return NormalResult(), err
 	}
 
-	cond, err := i.executeExpression(ifStmt.Condition)
+	cond, err := i.executeCondition(events.BranchIf, ifStmt.Condition)
 	if err != nil {
 		return NormalResult(), err
 	}
 
-	v, ok := cond.(int)
-	if !ok {
-		return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
-	}
-
-	if v != 0 {
+	if cond {
 		return i.executeStatement(ifStmt.ThenBlock)
 	} else if ifStmt.ElseBlock != nil {
 		return i.executeStatement(ifStmt.ElseBlock)
@@ -197,32 +332,46 @@ func (i *Interpreter) executeIfStmt(ifStmt *converter.IfStmt) (ExecResult, error
 }
 
 func (i *Interpreter) executeReturnStmt(r *converter.ReturnStmt) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(r.Loc.Line)})
-	if err := i.addStep(); err != nil {
+	if err := i.stepTo(r.Loc, step.KindReturn); err != nil {
 		return NormalResult(), err
 	}
 
-	var val *int
+	var val *runtime.Value
 	if r.Value != nil {
 		v, err := i.executeExpression(r.Value)
 		if err != nil {
 			return NormalResult(), err
 		}
-		t, ok := v.(int)
-		if !ok {
-			return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
+		if returnType, ok := i.currentReturnType(); ok {
+			v, err = i.convertValue(v, returnType)
+		} else if v.IsVoid() {
+			err = runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
 		}
-		val = &t
+		if err != nil {
+			return NormalResult(), err
+		}
+		val = &v
 	}
 
-	i.addEvents(events.FunctionReturn{Name: i.CallStack.GetCurrentFrame().FuncName, ReturnValue: val})
+	if err := i.exitFrameScopes(); err != nil {
+		return NormalResult(), err
+	}
+	i.addEvents(events.FunctionReturn{Name: i.CallStack.GetCurrentFrame().FuncName, ReturnValue: val, CallerLine: i.callLines[len(i.callLines)-1]})
 
 	return ReturnResult(val), nil
 }
 
+// currentReturnType возвращает объявленный возвращаемый тип текущей функции, если он не void
+func (i *Interpreter) currentReturnType() (runtime.Type, bool) {
+	decl, ok := i.Functions[i.CallStack.GetCurrentFrame().FuncName]
+	if !ok || typeOf(decl.ReturnType) == runtime.TypeVoid {
+		return "", false
+	}
+	return typeOf(decl.ReturnType), true
+}
+
 func (i *Interpreter) executeExprStmt(e *converter.ExprStmt) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(e.Loc.Line)})
-	if err := i.addStep(); err != nil {
+	if err := i.stepTo(e.Loc, step.KindExpression); err != nil {
 		return NormalResult(), err
 	}
 
@@ -239,23 +388,21 @@ func (i *Interpreter) executeExprStmt(e *converter.ExprStmt) (ExecResult, error)
 }
 
 func (i *Interpreter) executeWhileStmt(loop *converter.WhileStmt) (ExecResult, error) {
+	states := newLoopStates()
 	for {
-		i.addEvents(events.LineChanged{Line: int(loop.Loc.Line)})
-		if err := i.addStep(); err != nil {
+		if err := i.checkLoopState(states, events.BranchWhile, loop.Condition.GetLocation(), loop.Condition); err != nil {
 			return NormalResult(), err
 		}
-
-		condVal, err := i.executeExpression(loop.Condition)
-		if err != nil {
+		if err := i.stepTo(loop.Condition.GetLocation(), step.KindCondition); err != nil {
 			return NormalResult(), err
 		}
 
-		v, ok := condVal.(int)
-		if !ok {
-			return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
+		cond, err := i.executeCondition(events.BranchWhile, loop.Condition)
+		if err != nil {
+			return NormalResult(), err
 		}
 
-		if v == 0 {
+		if !cond {
 			break
 		}
 
@@ -278,6 +425,7 @@ func (i *Interpreter) executeWhileStmt(loop *converter.WhileStmt) (ExecResult, e
 }
 
 func (i *Interpreter) executeDoWhileStmt(loop *converter.DoWhileStmt) (ExecResult, error) {
+	states := newLoopStates()
 	for {
 		res, err := i.executeStatement(loop.Body)
 		if err != nil {
@@ -293,22 +441,19 @@ func (i *Interpreter) executeDoWhileStmt(loop *converter.DoWhileStmt) (ExecResul
 			return res, nil
 		}
 
-		i.addEvents(events.LineChanged{Line: int(loop.Condition.GetLocation().Line)})
-		if err := i.addStep(); err != nil {
+		if err := i.checkLoopState(states, events.BranchDoWhile, loop.Condition.GetLocation(), loop.Condition); err != nil {
 			return NormalResult(), err
 		}
-
-		condVal, err := i.executeExpression(loop.Condition)
-		if err != nil {
+		if err := i.stepTo(loop.Condition.GetLocation(), step.KindCondition); err != nil {
 			return NormalResult(), err
 		}
 
-		v, ok := condVal.(int)
-		if !ok {
-			return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
+		cond, err := i.executeCondition(events.BranchDoWhile, loop.Condition)
+		if err != nil {
+			return NormalResult(), err
 		}
 
-		if v == 0 {
+		if !cond {
 			break
 		}
 	}
@@ -316,16 +461,10 @@ func (i *Interpreter) executeDoWhileStmt(loop *converter.DoWhileStmt) (ExecResul
 	return NormalResult(), nil
 }
 
+// executeForStmt выполняет цикл for. Инициализация, условие и изменение счетчика
+// выполняются отдельными шагами, каждый со своим участком исходного кода
 func (i *Interpreter) executeForStmt(loop *converter.ForStmt) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(loop.Loc.Line)})
-	if err := i.addStep(); err != nil {
-		return NormalResult(), err
-	}
-
-	frame := i.CallStack.GetCurrentFrame()
-
-	frame.EnterScope()
-	i.addEvents(events.EnterScope{})
+	i.enterScope(scopeInfo(runtime.ScopeFor, loop.Loc, loop.Loc))
 
 	if loop.Init != nil {
 		_, err := i.executeStatement(loop.Init)
@@ -334,19 +473,27 @@ func (i *Interpreter) executeForStmt(loop *converter.ForStmt) (ExecResult, error
 		}
 	}
 
+	states := newLoopStates()
 	for {
+		loc := loop.Loc
 		if loop.Condition != nil {
-			condVal, err := i.executeExpression(loop.Condition)
-			if err != nil {
+			loc = loop.Condition.GetLocation()
+		}
+		if err := i.checkLoopState(states, events.BranchFor, loc, loop.Condition); err != nil {
+			return NormalResult(), err
+		}
+
+		if loop.Condition != nil {
+			if err := i.stepTo(loop.Condition.GetLocation(), step.KindCondition); err != nil {
 				return NormalResult(), err
 			}
 
-			v, ok := condVal.(int)
-			if !ok {
-				return ExecResult{}, runtimeerrors.NewErrUnexpectedInternalError("types mismatch")
+			cond, err := i.executeCondition(events.BranchFor, loop.Condition)
+			if err != nil {
+				return NormalResult(), err
 			}
 
-			if v == 0 {
+			if !cond {
 				break
 			}
 		}
@@ -358,7 +505,7 @@ func (i *Interpreter) executeForStmt(loop *converter.ForStmt) (ExecResult, error
 
 		switch res.Signal {
 		case SignalBreak:
-			return NormalResult(), nil
+			return NormalResult(), i.exitScope()
 		case SignalContinue:
 		case SignalReturn:
 			return res, nil
@@ -372,28 +519,39 @@ func (i *Interpreter) executeForStmt(loop *converter.ForStmt) (ExecResult, error
 		}
 	}
 
-	frame.ExitScope()
-	i.addEvents(events.ExitScope{})
-
-	return NormalResult(), nil
+	return NormalResult(), i.exitScope()
 }
 
 func (i *Interpreter) executeBreakStmt(b *converter.BreakStmt) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(b.Loc.Line)})
-	if err := i.addStep(); err != nil {
+	if err := i.stepTo(b.Loc, step.KindBreak); err != nil {
 		return NormalResult(), err
 	}
 	return BreakResult(), nil
 }
 
 func (i *Interpreter) executeContinueStmt(c *converter.ContinueStmt) (ExecResult, error) {
-	i.addEvents(events.LineChanged{Line: int(c.Loc.Line)})
-	if err := i.addStep(); err != nil {
+	if err := i.stepTo(c.Loc, step.KindContinue); err != nil {
 		return NormalResult(), err
 	}
 	return ContinueResult(), nil
 }
 
+// executeCondition вычисляет условие ветвления или цикла construct
+func (i *Interpreter) executeCondition(construct events.BranchConstruct, expr converter.Expr) (bool, error) {
+	value, err := i.executeExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	cond, err := value.Truth()
+	if err != nil {
+		return false, err
+	}
+	if i.branchEvents {
+		i.addEvents(events.BranchEvaluated{Construct: construct, Loc: expr.GetLocation(), Taken: cond})
+	}
+	return cond, nil
+}
+
 func (i *Interpreter) executeFunctionDecl(f *converter.FunctionDecl) (ExecResult, error) {
 	if _, exists := i.Functions[f.Name]; exists {
 		return NormalResult(), runtimeerrors.NewErrUnexpectedInternalError(fmt.Sprintf("functions with the same name: %s", f.Name))
@@ -411,10 +569,7 @@ func cloneArrayElements(src []runtime.ArrayElement) []runtime.ArrayElement {
 	dst := make([]runtime.ArrayElement, len(src))
 	for i := range src {
 		dst[i].StepChanged = src[i].StepChanged
-		if src[i].Value != nil {
-			val := *src[i].Value
-			dst[i].Value = &val
-		}
+		dst[i].Value = runtime.CloneValue(src[i].Value)
 	}
 	return dst
 }
@@ -434,12 +589,3 @@ func cloneArrayRows(src []runtime.Array) []runtime.Array {
 	}
 	return dst
 }
-
-// cloneValue создает копию значения указателя для сохранения снимка
-func cloneValue(src *int) *int {
-	if src == nil {
-		return nil
-	}
-	val := *src
-	return &val
-}
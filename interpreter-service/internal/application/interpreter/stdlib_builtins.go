@@ -0,0 +1,65 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+const (
+	// randMax - наибольшее значение rand (RAND_MAX)
+	randMax = 32767
+	// defaultRandSeed - начальное значение генератора, как если бы был вызван srand(1)
+	defaultRandSeed = 1
+)
+
+func (i *Interpreter) builtinAbs(_ *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	if args[0].Int < 0 {
+		return runtime.NewIntValue(-args[0].Int), nil
+	}
+	return args[0], nil
+}
+
+func (i *Interpreter) builtinMin(_ *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	return runtime.NewIntValue(min(args[0].Int, args[1].Int)), nil
+}
+
+func (i *Interpreter) builtinMax(_ *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	return runtime.NewIntValue(max(args[0].Int, args[1].Int)), nil
+}
+
+// builtinRand - линейный конгруэнтный генератор из примера стандарта C,
+// поэтому последовательность при одном и том же seed всегда одинакова
+func (i *Interpreter) builtinRand(_ *converter.CallExpr, _ []runtime.Value) (runtime.Value, error) {
+	i.randState = i.randState*1103515245 + 12345
+	return runtime.NewIntValue(int(i.randState/65536) % (randMax + 1)), nil
+}
+
+func (i *Interpreter) builtinSrand(_ *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	i.randState = uint32(args[0].Int)
+	return runtime.VoidValue(), nil
+}
+
+// builtinAssert завершает программу событием AssertionFailed, если условие ложно
+func (i *Interpreter) builtinAssert(call *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	truth, err := args[0].Truth()
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	if truth {
+		return runtime.VoidValue(), nil
+	}
+
+	line := int(call.Loc.Line)
+	err = runtimeerrors.NewErrAssertionFailed(fmt.Sprintf("assert at line %d", line))
+	i.addEvents(events.AssertionFailed{Message: err.Error(), Line: line})
+
+	return runtime.Value{}, err
+}
+
+func (i *Interpreter) builtinExit(_ *converter.CallExpr, args []runtime.Value) (runtime.Value, error) {
+	return runtime.Value{}, exitSignal{status: args[0].Int}
+}
@@ -0,0 +1,160 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+// stringOperand - аргумент строковой функции: массив char (или строка двумерного массива) либо строковый литерал
+type stringOperand struct {
+	arrayOperand
+	literal []byte
+}
+
+// stringOperands вычисляет аргументы строковой функции из <string.h>
+func (i *Interpreter) stringOperands(call *converter.CallExpr) ([]stringOperand, error) {
+	operands := make([]stringOperand, len(call.Arguments))
+	for ind, arg := range call.Arguments {
+		op, err := i.executeStringOperand(call.FunctionName, arg)
+		if err != nil {
+			return nil, err
+		}
+		operands[ind] = op
+	}
+	return operands, nil
+}
+
+func (i *Interpreter) builtinStrlen(call *converter.CallExpr, _ []runtime.Value) (runtime.Value, error) {
+	operands, err := i.stringOperands(call)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	s, err := operands[0].read(call.FunctionName)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	return runtime.NewIntValue(len(s)), nil
+}
+
+func (i *Interpreter) builtinStrcmp(call *converter.CallExpr, _ []runtime.Value) (runtime.Value, error) {
+	operands, err := i.stringOperands(call)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	a, err := operands[0].read(call.FunctionName)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	b, err := operands[1].read(call.FunctionName)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	return runtime.NewIntValue(bytes.Compare(a, b)), nil
+}
+
+func (i *Interpreter) builtinStrcpy(call *converter.CallExpr, _ []runtime.Value) (runtime.Value, error) {
+	operands, err := i.stringOperands(call)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	src, err := operands[1].read(call.FunctionName)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	return runtime.VoidValue(), i.writeString(call.FunctionName, operands[0], 0, src)
+}
+
+func (i *Interpreter) builtinStrcat(call *converter.CallExpr, _ []runtime.Value) (runtime.Value, error) {
+	operands, err := i.stringOperands(call)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	dst, err := operands[0].read(call.FunctionName)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	src, err := operands[1].read(call.FunctionName)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	return runtime.VoidValue(), i.writeString(call.FunctionName, operands[0], len(dst), src)
+}
+
+func (i *Interpreter) executeStringOperand(fn string, expr converter.Expr) (stringOperand, error) {
+	if lit, ok := expr.(*converter.StringLiteral); ok {
+		return stringOperand{literal: []byte(lit.Value)}, nil
+	}
+
+	operand, err := i.executeArrayOperand(expr)
+	if err != nil {
+		return stringOperand{}, err
+	}
+	if operand.array.Type != runtime.TypeChar {
+		return stringOperand{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("%s: argument %s is not a char array", fn, operand.name))
+	}
+
+	return stringOperand{arrayOperand: operand}, nil
+}
+
+// displayName возвращает имя массива вместе с индексом строки для двумерного массива
+func (op stringOperand) displayName() string {
+	name := op.name
+	for _, ind := range op.indices {
+		name += fmt.Sprintf("[%d]", ind)
+	}
+	return name
+}
+
+// read возвращает символы строки до завершающего нуля (без него)
+func (op stringOperand) read(fn string) ([]byte, error) {
+	if op.array == nil {
+		return op.literal, nil
+	}
+
+	result := make([]byte, 0, op.array.Size)
+	for ind := 0; ind < op.array.Size; ind++ {
+		element, err := op.array.GetElement(ind)
+		if err != nil {
+			return nil, err
+		}
+		value, err := element.GetValue()
+		if err != nil {
+			return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("%s: reading uninitialized element %s[%d]", fn, op.displayName(), ind))
+		}
+		if value.Int == 0 {
+			return result, nil
+		}
+		result = append(result, byte(value.Int))
+	}
+
+	return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("%s: string in array %s is not null-terminated", fn, op.displayName()))
+}
+
+// writeString записывает строку с завершающим нулем в массив начиная с offset.
+// Выход за границу массива - неопределенное поведение, массив при этом не изменяется
+func (i *Interpreter) writeString(fn string, dst stringOperand, offset int, s []byte) error {
+	if dst.array == nil {
+		return runtimeerrors.NewErrRuntime(fmt.Sprintf("%s: destination must be a char array", fn))
+	}
+
+	data := append(append([]byte{}, s...), 0)
+	if offset+len(data) > dst.array.Size {
+		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("%s: buffer overflow, %d bytes needed but array %s has size %d", fn, offset+len(data), dst.displayName(), dst.array.Size))
+	}
+
+	for k, b := range data {
+		element, err := dst.array.GetElement(offset + k)
+		if err != nil {
+			return err
+		}
+		if _, err := i.assignLvalue(dst.element(element, offset+k), runtime.NewCharValue(int(b))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package profile
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+)
+
+// Номера полей сообщений profile.proto, которые записывает WritePprof
+const (
+	profileSampleType  = 1
+	profileSample      = 2
+	profileLocation    = 4
+	profileFunction    = 5
+	profileStringTable = 6
+	profilePeriodType  = 11
+	profilePeriod      = 12
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID       = 1
+	functionName     = 2
+	functionFilename = 4
+)
+
+// WritePprof записывает профиль в формате pprof (profile.proto, сжатый gzip) для файла source:
+// значение каждой выборки - число шагов с ее стеком вызовов. Результат открывается go tool pprof
+func (p *Profile) WritePprof(w io.Writer, source string) error {
+	indices := map[string]int{"": 0}
+	table := []string{""}
+	str := func(s string) uint64 {
+		if ind, ok := indices[s]; ok {
+			return uint64(ind)
+		}
+		indices[s] = len(table)
+		table = append(table, s)
+		return uint64(len(table) - 1)
+	}
+
+	var out protoBuffer
+	valueType := func(field int, typ, unit string) {
+		var vt protoBuffer
+		vt.varint(valueTypeType, str(typ))
+		vt.varint(valueTypeUnit, str(unit))
+		out.message(field, vt)
+	}
+	valueType(profileSampleType, "steps", "count")
+
+	functionIDs := map[string]uint64{}
+	locationIDs := map[location]uint64{}
+	var functions, locations []protoBuffer
+	for _, s := range p.samples {
+		var sm protoBuffer
+		ids := make([]uint64, len(s.stack))
+		for ind, loc := range s.stack {
+			fnID, ok := functionIDs[loc.function]
+			if !ok {
+				fnID = uint64(len(functionIDs) + 1)
+				functionIDs[loc.function] = fnID
+				var fn protoBuffer
+				fn.varint(functionID, fnID)
+				fn.varint(functionName, str(loc.function))
+				fn.varint(functionFilename, str(source))
+				functions = append(functions, fn)
+			}
+
+			locID, ok := locationIDs[loc]
+			if !ok {
+				locID = uint64(len(locationIDs) + 1)
+				locationIDs[loc] = locID
+				var line, l protoBuffer
+				line.varint(lineFunctionID, fnID)
+				line.varint(lineLine, uint64(loc.line))
+				l.varint(locationID, locID)
+				l.message(locationLine, line)
+				locations = append(locations, l)
+			}
+			ids[ind] = locID
+		}
+		sm.packed(sampleLocationID, ids)
+		sm.packed(sampleValue, []uint64{uint64(s.steps)})
+		out.message(profileSample, sm)
+	}
+	for _, l := range locations {
+		out.message(profileLocation, l)
+	}
+	for _, fn := range functions {
+		out.message(profileFunction, fn)
+	}
+
+	valueType(profilePeriodType, "steps", "count")
+	out.varint(profilePeriod, 1)
+	for _, s := range table {
+		out.bytes(profileStringTable, []byte(s))
+	}
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(out.data); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// protoBuffer - минимальная запись сообщения protobuf: целые поля, вложенные сообщения и строки
+type protoBuffer struct {
+	data []byte
+}
+
+func (b *protoBuffer) tag(field int, wireType uint64) {
+	b.data = binary.AppendUvarint(b.data, uint64(field)<<3|wireType)
+}
+
+func (b *protoBuffer) varint(field int, v uint64) {
+	b.tag(field, 0)
+	b.data = binary.AppendUvarint(b.data, v)
+}
+
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.tag(field, 2)
+	b.data = binary.AppendUvarint(b.data, uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protoBuffer) message(field int, m protoBuffer) {
+	b.bytes(field, m.data)
+}
+
+func (b *protoBuffer) packed(field int, values []uint64) {
+	var packed []byte
+	for _, v := range values {
+		packed = binary.AppendUvarint(packed, v)
+	}
+	b.bytes(field, packed)
+}
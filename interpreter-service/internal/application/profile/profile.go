@@ -0,0 +1,218 @@
+package profile
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+)
+
+// Function - затраты функции: Exclusive - шаги, на которых она выполняется сама,
+// Inclusive - шаги, на которых она находится на стеке (рекурсивные вызовы учитываются один раз)
+type Function struct {
+	Name      string `json:"name"`
+	Calls     int    `json:"calls"`
+	Inclusive int    `json:"inclusive"`
+	Exclusive int    `json:"exclusive"`
+}
+
+// Line - число шагов, остановившихся на строке функции Function
+type Line struct {
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+	Steps    int    `json:"steps"`
+}
+
+// Variable - число чтений и записей переменной или элементов массива Name функции Function
+// по всем ее объявлениям: Declarations больше 1 для переменных рекурсивных и повторных вызовов и тел циклов.
+// Function пусто для глобальных объявлений
+type Variable struct {
+	Name         string `json:"name"`
+	Function     string `json:"function,omitempty"`
+	Declarations int    `json:"declarations"`
+	Reads        int    `json:"reads"`
+	Writes       int    `json:"writes"`
+}
+
+// Profile - профиль выполнения программы, затраты измеряются числом шагов
+type Profile struct {
+	Steps     int        `json:"steps"`
+	Functions []Function `json:"functions"`
+	Lines     []Line     `json:"lines"`
+	Variables []Variable `json:"variables,omitempty"` // в порядке первого объявления
+
+	samples []sample // стеки вызовов и число шагов на каждом - для экспорта в pprof
+}
+
+// frame - вызов на стеке: функция и строка, из которой она вызвана
+type frame struct {
+	function string
+	callLine int
+}
+
+// sample - шаги с одинаковым стеком. Стек начинается с выполняемой функции и строки шага,
+// далее идут вызывающие функции и строки вызовов
+type sample struct {
+	stack []location
+	steps int
+}
+
+type location struct {
+	function string
+	line     int
+}
+
+// Build строит профиль трассы steps. Стек вызовов восстанавливается по событиям FunctionCall и FunctionReturn,
+// чтения - по событиям чтения (режим событий чтения), записи - по событиям изменения и объявлениям с инициализатором.
+// Шаги вне функций (инициализация глобальных переменных, завершение программы) не учитываются
+func Build(steps []step.Step) *Profile {
+	p := &Profile{}
+	functions := map[string]*Function{}
+	lines := map[int]*Line{}
+	variables := map[int]*Variable{}      // идентификатор объявления -> переменная
+	declared := map[[2]string]*Variable{} // функция и имя -> переменная
+	var order []*Variable
+	samples := map[string]int{} // стек -> индекс выборки в p.samples
+	var stack []frame
+
+	function := func(name string) *Function {
+		if f, ok := functions[name]; ok {
+			return f
+		}
+		f := &Function{Name: name}
+		functions[name] = f
+		return f
+	}
+	declare := func(id int, name string, global bool) *Variable {
+		fn := ""
+		if !global && len(stack) > 0 {
+			fn = stack[len(stack)-1].function
+		}
+		v, ok := declared[[2]string{fn, name}]
+		if !ok {
+			v = &Variable{Name: name, Function: fn}
+			declared[[2]string{fn, name}] = v
+			order = append(order, v)
+		}
+		v.Declarations++
+		variables[id] = v
+		return v
+	}
+	access := func(id int, write bool) {
+		v, ok := variables[id]
+		if !ok {
+			return
+		}
+		if write {
+			v.Writes++
+		} else {
+			v.Reads++
+		}
+	}
+
+	for _, st := range steps {
+		for _, event := range st.Events {
+			switch e := event.(type) {
+			case events.FunctionCall:
+				callLine := 0
+				if e.Loc != nil {
+					callLine = int(e.Loc.Line)
+				}
+				stack = append(stack, frame{function: e.Name, callLine: callLine})
+				function(e.Name).Calls++
+			case events.FunctionReturn:
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case events.DeclareVar:
+				v := declare(e.ID, e.Name, e.IsGlobal)
+				if e.Value != nil {
+					v.Writes++
+				}
+			case events.DeclareArray:
+				declare(e.ID, e.Name, e.IsGlobal)
+			case events.DeclareArray2D:
+				declare(e.ID, e.Name, e.IsGlobal)
+			case events.VarChanged:
+				access(e.ID, true)
+			case events.ArrayElementChanged:
+				access(e.ID, true)
+			case events.Array2DElementChanged:
+				access(e.ID, true)
+			case events.VarRead:
+				access(e.ID, false)
+			case events.ArrayElementRead:
+				access(e.ID, false)
+			case events.Array2DElementRead:
+				access(e.ID, false)
+			}
+		}
+
+		if len(stack) == 0 {
+			continue
+		}
+		p.Steps++
+
+		current := stack[len(stack)-1].function
+		function(current).Exclusive++
+		seen := map[string]bool{}
+		for _, fr := range stack {
+			if !seen[fr.function] {
+				seen[fr.function] = true
+				function(fr.function).Inclusive++
+			}
+		}
+
+		line, ok := lines[st.Line]
+		if !ok {
+			line = &Line{Line: st.Line, Function: current}
+			lines[st.Line] = line
+		}
+		line.Steps++
+
+		stackSample := make([]location, len(stack))
+		for ind := range stack {
+			loc := location{function: stack[len(stack)-1-ind].function, line: st.Line}
+			if ind > 0 {
+				loc.line = stack[len(stack)-ind].callLine
+			}
+			stackSample[ind] = loc
+		}
+		key := sampleKey(stackSample)
+		ind, ok := samples[key]
+		if !ok {
+			ind = len(p.samples)
+			samples[key] = ind
+			p.samples = append(p.samples, sample{stack: stackSample})
+		}
+		p.samples[ind].steps++
+	}
+
+	for _, f := range functions {
+		p.Functions = append(p.Functions, *f)
+	}
+	slices.SortFunc(p.Functions, func(a, b Function) int {
+		return cmp.Or(cmp.Compare(b.Inclusive, a.Inclusive), cmp.Compare(b.Exclusive, a.Exclusive), cmp.Compare(a.Name, b.Name))
+	})
+	for _, l := range lines {
+		p.Lines = append(p.Lines, *l)
+	}
+	slices.SortFunc(p.Lines, func(a, b Line) int {
+		return cmp.Or(cmp.Compare(b.Steps, a.Steps), cmp.Compare(a.Line, b.Line))
+	})
+	for _, v := range order {
+		p.Variables = append(p.Variables, *v)
+	}
+	return p
+}
+
+func sampleKey(stack []location) string {
+	var key strings.Builder
+	for _, loc := range stack {
+		fmt.Fprintf(&key, "%s:%d;", loc.function, loc.line)
+	}
+	return key.String()
+}
@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+)
+
+const fibCode = `int fib(int n) {
+	if (n < 2) {
+		return n;
+	}
+	return fib(n - 1) + fib(n - 2);
+}
+int main() {
+	int r = fib(5);
+	return r;
+}`
+
+func build(t *testing.T, code string) *Profile {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	runner := interpreter.NewInterpreter()
+	runner.SetReadEvents(true)
+	_, steps, _, err := runner.ExecuteProgram(program)
+	require.NoError(t, err)
+	return Build(steps)
+}
+
+func TestBuild_Functions(t *testing.T) {
+	p := build(t, fibCode)
+
+	// объявление r, возврат из fib в строку 8 и return r
+	assert.Equal(t, []Function{
+		{Name: "main", Calls: 1, Inclusive: p.Steps, Exclusive: 3},
+		{Name: "fib", Calls: 15, Inclusive: p.Steps - 3, Exclusive: p.Steps - 3},
+	}, p.Functions)
+
+	// условие выполняется в каждом из 15 вызовов, return n - в 8 вызовах с n < 2
+	require.NotEmpty(t, p.Lines)
+	assert.Equal(t, Line{Line: 5, Function: "fib", Steps: 21}, p.Lines[0])
+	assert.Contains(t, p.Lines, Line{Line: 2, Function: "fib", Steps: 15})
+	assert.Contains(t, p.Lines, Line{Line: 3, Function: "fib", Steps: 8})
+	for ind := 1; ind < len(p.Lines); ind++ {
+		assert.GreaterOrEqual(t, p.Lines[ind-1].Steps, p.Lines[ind].Steps)
+	}
+
+	total := 0
+	for _, s := range p.samples {
+		total += s.steps
+		assert.Equal(t, "main", s.stack[len(s.stack)-1].function)
+	}
+	assert.Equal(t, p.Steps, total)
+}
+
+func TestBuild_Variables(t *testing.T) {
+	p := build(t, `int a[3] = {3, 2, 1};
+int swaps = 0;
+void sort(int n) {
+	for (int i = 0; i < n - 1; i++) {
+		for (int j = 0; j < n - 1 - i; j++) {
+			if (a[j] > a[j + 1]) {
+				int t = a[j];
+				a[j] = a[j + 1];
+				a[j + 1] = t;
+				swaps++;
+			}
+		}
+	}
+}
+int main() {
+	sort(3);
+	return swaps;
+}`)
+
+	byName := map[string]Variable{}
+	for _, v := range p.Variables {
+		byName[v.Function+"."+v.Name] = v
+	}
+
+	// массив 3, 2, 1 сортируется тремя обменами
+	assert.Equal(t, Variable{Name: "swaps", Declarations: 1, Reads: 4, Writes: 4}, byName[".swaps"])
+	assert.Equal(t, Variable{Name: "t", Function: "sort", Declarations: 3, Reads: 3, Writes: 3}, byName["sort.t"])
+	assert.Equal(t, 2, byName["sort.j"].Declarations)
+	// три сравнения и три обмена, по два элемента в каждом
+	assert.Equal(t, 6, byName[".a"].Writes)
+	assert.Equal(t, 12, byName[".a"].Reads)
+	assert.Equal(t, "a", p.Variables[0].Name)
+}
+
+func TestWritePprof(t *testing.T) {
+	p := build(t, fibCode)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.WritePprof(&buf, "fib.c"))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+
+	for _, s := range []string{"steps", "count", "fib", "main", "fib.c"} {
+		assert.True(t, bytes.Contains(data, []byte(s)), s)
+	}
+}
@@ -0,0 +1,76 @@
+package watch
+
+import (
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+)
+
+// Result - значение наблюдаемого выражения на шаге или ошибка его разбора или вычисления
+type Result struct {
+	Expression string         `json:"expression"`
+	Value      *runtime.Value `json:"value,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// ParseExpression разбирает выражение на C и проверяет, что его вычисление не меняет состояние программы
+func ParseExpression(source string) (converter.Expr, error) {
+	program, convErr := converter.New().ParseToAST("int main() {\nreturn (" + source + ");\n}\n")
+	if convErr != nil {
+		return nil, fmt.Errorf("invalid expression %q: %s", source, convErr.Error())
+	}
+
+	var expr converter.Expr
+	if len(program.Declarations) == 1 {
+		if fn, ok := program.Declarations[0].(*converter.FunctionDecl); ok && fn.Body != nil && len(fn.Body.Statements) == 1 {
+			if ret, ok := fn.Body.Statements[0].(*converter.ReturnStmt); ok {
+				expr = ret.Value
+			}
+		}
+	}
+	if expr == nil {
+		return nil, fmt.Errorf("invalid expression %q", source)
+	}
+
+	if err := interpreter.CheckInspectable(expr); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// IsLocation сообщает, обозначает ли выражение изменяемый объект: переменную, элемент массива или *p
+func IsLocation(expr converter.Expr) bool {
+	switch e := expr.(type) {
+	case *converter.VariableExpr, *converter.ArrayAccessExpr:
+		return true
+	case *converter.UnaryExpr:
+		return e.Operator == "*"
+	default:
+		return false
+	}
+}
+
+// Evaluate вычисляет выражения на состоянии программы из снимка. Ошибка одного выражения
+// (чтение неинициализированного значения, выход за границы массива) не мешает вычислению остальных
+func Evaluate(sn *snapshot.Snapshot, sources []string) []Result {
+	results := make([]Result, 0, len(sources))
+	for _, source := range sources {
+		result := Result{Expression: source}
+		expr, err := ParseExpression(source)
+		if err == nil {
+			var value runtime.Value
+			value, err = interpreter.Inspect(sn, expr)
+			if err == nil {
+				result.Value = &value
+			}
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,79 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+)
+
+const watchCode = `int main() {
+	int a[4] = {3, 9, 4, 1};
+	int n = 4;
+	int max = a[0];
+	int x;
+	for (int i = 1; i < n; i++) {
+		if (a[i] > max) {
+			max = a[i];
+		}
+	}
+	return max;
+}`
+
+// snapshotOnLine возвращает снимок на первом шаге, остановленном на строке line
+func snapshotOnLine(t *testing.T, code string, line int) *snapshot.Snapshot {
+	t.Helper()
+
+	program, convErr := converter.New().ParseToAST(code)
+	require.Nil(t, convErr)
+	_, steps, stepBegin, err := interpreter.NewInterpreter().ExecuteProgram(program)
+	require.NoError(t, err)
+
+	ed := eventdispatcher.NewEventDispatcher(stepBegin)
+	ed.Steps = steps
+	for ind := stepBegin; ind < len(steps); ind++ {
+		if steps[ind].Line == line {
+			require.NoError(t, ed.ApplyStep(ind-stepBegin))
+			return ed.GetSnapshot()
+		}
+	}
+	require.Failf(t, "line is not reached", "line %d", line)
+	return nil
+}
+
+func TestEvaluate(t *testing.T) {
+	sn := snapshotOnLine(t, watchCode, 7)
+
+	results := Evaluate(sn, []string{"a[i] + a[i+1]", "i < n && a[i] > max", "max * 2"})
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.Empty(t, result.Error, result.Expression)
+		require.NotNil(t, result.Value, result.Expression)
+	}
+	assert.Equal(t, 13, results[0].Value.Int)
+	assert.Equal(t, 1, results[1].Value.Int)
+	assert.Equal(t, 6, results[2].Value.Int)
+}
+
+func TestEvaluate_PerExpressionErrors(t *testing.T) {
+	sn := snapshotOnLine(t, watchCode, 7)
+
+	results := Evaluate(sn, []string{"x + 1", "a[i + 5]", "y", "n = 0", "a[", "n - 1"})
+	require.Len(t, results, 6)
+	assert.Contains(t, results[0].Error, "undefined behavior")
+	assert.Contains(t, results[1].Error, "out of")
+	assert.Equal(t, "runtime error: y is not declared in the current scope", results[2].Error)
+	assert.Contains(t, results[3].Error, "assignment is not allowed")
+	assert.Contains(t, results[4].Error, "invalid expression")
+	for _, result := range results[:5] {
+		assert.Nil(t, result.Value, result.Expression)
+	}
+
+	require.NotNil(t, results[5].Value)
+	assert.Equal(t, 3, results[5].Value.Int)
+}
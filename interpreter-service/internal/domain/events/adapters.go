@@ -21,6 +21,9 @@ func MarshalEvent(e Event) (EventDTO, error) {
 	case ExitScope:
 		typeStr = "ExitScope"
 		data = v
+	case VariableDestroyed:
+		typeStr = "VariableDestroyed"
+		data = v
 	case DeclareVar:
 		typeStr = "DeclareVar"
 		data = v
@@ -39,15 +42,66 @@ func MarshalEvent(e Event) (EventDTO, error) {
 	case Array2DElementChanged:
 		typeStr = "Array2DElementChanged"
 		data = v
+	case ImplicitConversion:
+		typeStr = "ImplicitConversion"
+		data = v
 	case FunctionCall:
 		typeStr = "FunctionCall"
 		data = v
 	case FunctionReturn:
 		typeStr = "FunctionReturn"
 		data = v
+	case HeapAlloc:
+		typeStr = "HeapAlloc"
+		data = v
+	case HeapBlockTyped:
+		typeStr = "HeapBlockTyped"
+		data = v
+	case HeapFree:
+		typeStr = "HeapFree"
+		data = v
+	case HeapElementChanged:
+		typeStr = "HeapElementChanged"
+		data = v
+	case HeapLeak:
+		typeStr = "HeapLeak"
+		data = v
+	case BuiltinCall:
+		typeStr = "BuiltinCall"
+		data = v
+	case AssertionFailed:
+		typeStr = "AssertionFailed"
+		data = v
 	case LineChanged:
 		typeStr = "LineChanged"
 		data = v
+	case RangeChanged:
+		typeStr = "RangeChanged"
+		data = v
+	case ExprEvaluated:
+		typeStr = "ExprEvaluated"
+		data = v
+	case VarRead:
+		typeStr = "VarRead"
+		data = v
+	case ArrayElementRead:
+		typeStr = "ArrayElementRead"
+		data = v
+	case Array2DElementRead:
+		typeStr = "Array2DElementRead"
+		data = v
+	case HeapElementRead:
+		typeStr = "HeapElementRead"
+		data = v
+	case ArrayIndexed:
+		typeStr = "ArrayIndexed"
+		data = v
+	case BranchEvaluated:
+		typeStr = "BranchEvaluated"
+		data = v
+	case InfiniteLoop:
+		typeStr = "InfiniteLoop"
+		data = v
 	case UndefinedBehavior:
 		typeStr = "UndefinedBehavior"
 		data = v
@@ -83,6 +137,12 @@ func UnmarshalEvent(dto EventDTO) (Event, error) {
 			return nil, err
 		}
 		return e, nil
+	case "VariableDestroyed":
+		var e VariableDestroyed
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
 	case "DeclareVar":
 		var e DeclareVar
 		if err := json.Unmarshal(dto.Data, &e); err != nil {
@@ -119,6 +179,12 @@ func UnmarshalEvent(dto EventDTO) (Event, error) {
 			return nil, err
 		}
 		return e, nil
+	case "ImplicitConversion":
+		var e ImplicitConversion
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
 	case "FunctionCall":
 		var e FunctionCall
 		if err := json.Unmarshal(dto.Data, &e); err != nil {
@@ -131,12 +197,108 @@ func UnmarshalEvent(dto EventDTO) (Event, error) {
 			return nil, err
 		}
 		return e, nil
+	case "HeapAlloc":
+		var e HeapAlloc
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "HeapBlockTyped":
+		var e HeapBlockTyped
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "HeapFree":
+		var e HeapFree
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "HeapElementChanged":
+		var e HeapElementChanged
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "HeapLeak":
+		var e HeapLeak
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "BuiltinCall":
+		var e BuiltinCall
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "AssertionFailed":
+		var e AssertionFailed
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
 	case "LineChanged":
 		var e LineChanged
 		if err := json.Unmarshal(dto.Data, &e); err != nil {
 			return nil, err
 		}
 		return e, nil
+	case "RangeChanged":
+		var e RangeChanged
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "ExprEvaluated":
+		var e ExprEvaluated
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "VarRead":
+		var e VarRead
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "ArrayElementRead":
+		var e ArrayElementRead
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "Array2DElementRead":
+		var e Array2DElementRead
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "HeapElementRead":
+		var e HeapElementRead
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "ArrayIndexed":
+		var e ArrayIndexed
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "BranchEvaluated":
+		var e BranchEvaluated
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case "InfiniteLoop":
+		var e InfiniteLoop
+		if err := json.Unmarshal(dto.Data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
 	case "UndefinedBehavior":
 		var e UndefinedBehavior
 		if err := json.Unmarshal(dto.Data, &e); err != nil {
@@ -1,28 +1,55 @@
 package events
 
-import "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+import (
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+)
 
+// Event - событие трассы. Поля с тегом json:"-" описывают состояние, которое событие перезаписало:
+// их заполняет снимок при применении события (Snapshot.Record), чтобы событие можно было отменить
 type Event interface{}
 
-type EnterScope struct{}
+// EnterScope - вход в область видимости: ее вид, узел-владелец и строки
+type EnterScope struct {
+	runtime.ScopeInfo
+}
 
-type ExitScope struct{}
+// ExitScope - выход из области видимости. Выход парный входу на любом пути выполнения:
+// при break, return и завершении функции без return
+type ExitScope struct {
+	Kind  runtime.ScopeKind `json:"kind,omitempty"`
+	Scope *runtime.Scope    `json:"-"` // закрытая область видимости
+}
+
+// VariableDestroyed - переменная или массив вышли из области видимости.
+// События уничтожения предшествуют ExitScope своей области, в порядке, обратном объявлению
+type VariableDestroyed struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+}
 
 type DeclareVar struct {
-	Name     string `json:"name"`
-	Value    *int   `json:"value"`
-	IsGlobal bool   `json:"isGlobal"`
+	ID       int            `json:"id,omitempty"` // уникальный идентификатор экземпляра объявления
+	Name     string         `json:"name"`
+	Type     runtime.Type   `json:"type"`
+	Value    *runtime.Value `json:"value"`
+	IsGlobal bool           `json:"isGlobal"`
+	Enum     *runtime.Enum  `json:"enum,omitempty"` // перечисление для enum-переменной
 }
 
 type DeclareArray struct {
+	ID       int                    `json:"id,omitempty"`
 	Name     string                 `json:"name"`
+	Type     runtime.Type           `json:"type"`
 	Value    []runtime.ArrayElement `json:"value"`
 	Size     int                    `json:"size"`
 	IsGlobal bool                   `json:"isGlobal"`
 }
 
 type DeclareArray2D struct {
+	ID       int             `json:"id,omitempty"`
 	Name     string          `json:"name"`
+	Type     runtime.Type    `json:"type"`
 	Value    []runtime.Array `json:"value"`
 	Size1    int             `json:"size1"`
 	Size2    int             `json:"size2"`
@@ -30,41 +57,235 @@ type DeclareArray2D struct {
 }
 
 type VarChanged struct {
-	Name  string `json:"name"`
-	Value int    `json:"value"`
+	ID    int           `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Value runtime.Value `json:"value"`
+	Prev  PrevValue     `json:"-"`
+}
+
+// PrevValue - значение и номер шага изменения, которые перезаписало событие изменения
+type PrevValue struct {
+	Value       *runtime.Value
+	StepChanged int
 }
 
 type ArrayElementChanged struct {
-	Name  string `json:"name"`
-	Ind   int    `json:"ind"`
-	Value int    `json:"value"`
+	ID    int           `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Ind   int           `json:"ind"`
+	Value runtime.Value `json:"value"`
+	Prev  PrevValue     `json:"-"`
 }
 
 type Array2DElementChanged struct {
-	Name  string `json:"name"`
-	Ind1  int    `json:"ind1"`
-	Ind2  int    `json:"ind2"`
-	Value int    `json:"value"`
+	ID    int           `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Ind1  int           `json:"ind1"`
+	Ind2  int           `json:"ind2"`
+	Value runtime.Value `json:"value"`
+	Prev  PrevValue     `json:"-"`
 }
 
+// ImplicitConversion - неявное преобразование значения к типу переменной,
+// параметра или возвращаемого значения (например, double -> int при присваивании)
+type ImplicitConversion struct {
+	From runtime.Value `json:"from"`
+	To   runtime.Value `json:"to"`
+}
+
+// FunctionCall - вызов функции из места Loc (nil для main). Arguments - значения аргументов в месте вызова,
+// до приведения к типам параметров; параметры объявляются следующими за вызовом DeclareVar
 type FunctionCall struct {
-	Name string `json:"name"`
-	// каждый parameter в ast будет порождать DeclareVar
+	Name      string              `json:"name"`
+	Loc       *converter.Location `json:"loc,omitempty"`
+	Arguments []Argument          `json:"arguments,omitempty"`
+}
+
+// Argument - значение аргумента, переданное параметру Name
+type Argument struct {
+	Name  string        `json:"name"`
+	Value runtime.Value `json:"value"`
 }
 
 type FunctionReturn struct {
-	Name        string `json:"name"`
-	ReturnValue *int   `json:"returnValue"` // nil если void функция
+	Name        string         `json:"name"`
+	ReturnValue *runtime.Value `json:"returnValue"`          // nil если void функция
+	CallerLine  int            `json:"callerLine,omitempty"` // строка вызова в вызывающей функции (0 для main)
+
+	Frame                *runtime.StackFrame `json:"-"` // снятый со стека кадр
+	PrevFrameReturnValue *runtime.Value      `json:"-"`
+	PrevFunctionName     string              `json:"-"` // функция, возврат из которой снимок показывал до события
+	PrevReturnValue      *runtime.Value      `json:"-"`
+}
+
+// HeapAlloc - выделение блока динамической памяти (malloc, calloc, realloc).
+// Для realloc Type - тип элементов нового блока, CopyFrom - адрес блока, из которого копируются значения
+type HeapAlloc struct {
+	Address  int          `json:"address"`
+	Size     int          `json:"size"`
+	Line     int          `json:"line"`
+	Zeroed   bool         `json:"zeroed"`
+	Type     runtime.Type `json:"type,omitempty"`
+	CopyFrom int          `json:"copyFrom,omitempty"`
+}
+
+// HeapBlockTyped - блок приведен к указателю на Type (void* -> T*)
+type HeapBlockTyped struct {
+	Address    int                    `json:"address"`
+	Type       runtime.Type           `json:"type"`
+	PrevType   runtime.Type           `json:"-"`
+	PrevValues []runtime.ArrayElement `json:"-"`
+}
+
+type HeapFree struct {
+	Address int `json:"address"`
+}
+
+type HeapElementChanged struct {
+	Address int           `json:"address"`
+	Ind     int           `json:"ind"`
+	Value   runtime.Value `json:"value"`
+	Prev    PrevValue     `json:"-"`
+}
+
+// HeapLeak - блок, не освобожденный к моменту завершения main
+type HeapLeak struct {
+	Address int `json:"address"`
+	Size    int `json:"size"`
+	Line    int `json:"line"`
+}
+
+// BuiltinCall - вызов встроенной функции. Arguments - значения аргументов после приведения
+// к типам параметров (nil для аргументов-массивов строковых функций)
+type BuiltinCall struct {
+	Name      string           `json:"name"`
+	Header    string           `json:"header,omitempty"`
+	Arguments []*runtime.Value `json:"arguments"`
+}
+
+// AssertionFailed - ложное условие assert, программа завершается
+type AssertionFailed struct {
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	PrevError string `json:"-"`
 }
 
 type LineChanged struct {
-	Line int `json:"line"`
+	Line int          `json:"line"`
+	Prev PrevPosition `json:"-"`
+}
+
+// PrevPosition - положение выполнения, которое перезаписало событие: строка, участок кода
+// и вычисленные подвыражения текущего кадра
+type PrevPosition struct {
+	Line      int
+	Range     *converter.Location
+	Evaluated []runtime.EvaluatedExpr
+}
+
+// RangeChanged - участок исходного кода активной конструкции: инструкции, условия
+// или фазы цикла for. Следует за LineChanged того же шага
+type RangeChanged struct {
+	Loc       converter.Location  `json:"loc"`
+	PrevRange *converter.Location `json:"-"`
+}
+
+// ExprEvaluated - вычислено подвыражение текущей инструкции (режим пошагового вычисления выражений)
+type ExprEvaluated struct {
+	Loc           converter.Location      `json:"loc"`
+	Value         runtime.Value           `json:"value"`
+	PrevEvaluated []runtime.EvaluatedExpr `json:"-"`
+}
+
+// ReadUse - операция, которой передано прочитанное значение
+type ReadUse string
+
+const (
+	ReadCompare    ReadUse = "compare"    // операнд сравнения
+	ReadArithmetic ReadUse = "arithmetic" // операнд арифметической операции, составного присваивания, ++/--
+	ReadIndex      ReadUse = "index"      // индекс массива
+	ReadValue      ReadUse = "value"      // прочие использования: присваивание, аргумент, условие, return
+)
+
+// VarRead - чтение переменной при вычислении выражения (режим событий чтения)
+type VarRead struct {
+	ID    int           `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Value runtime.Value `json:"value"`
+	Use   ReadUse       `json:"use"`
+}
+
+type ArrayElementRead struct {
+	ID    int           `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Ind   int           `json:"ind"`
+	Value runtime.Value `json:"value"`
+	Use   ReadUse       `json:"use"`
+}
+
+type Array2DElementRead struct {
+	ID    int           `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Ind1  int           `json:"ind1"`
+	Ind2  int           `json:"ind2"`
+	Value runtime.Value `json:"value"`
+	Use   ReadUse       `json:"use"`
+}
+
+type HeapElementRead struct {
+	Address int           `json:"address"`
+	Ind     int           `json:"ind"`
+	Value   runtime.Value `json:"value"`
+	Use     ReadUse       `json:"use"`
+}
+
+// ArrayIndexed - переменная Variable входит в индекс обращения к массиву Name (или к блоку кучи Address)
+// по измерению Dimension, обращение выполнено к элементу Index. Offset - разность Index и значения переменной:
+// для a[j+1] маркер j указывает на элемент j+1 с Offset 1 (режим маркеров индексов)
+type ArrayIndexed struct {
+	Variable  string `json:"variable"`
+	Name      string `json:"name,omitempty"`
+	Address   int    `json:"address,omitempty"`
+	Dimension int    `json:"dimension"`
+	Index     int    `json:"index"`
+	Offset    int    `json:"offset"`
+}
+
+// BranchConstruct - конструкция, условие которой вычислено
+type BranchConstruct string
+
+const (
+	BranchIf      BranchConstruct = "if"
+	BranchWhile   BranchConstruct = "while"
+	BranchDoWhile BranchConstruct = "do"
+	BranchFor     BranchConstruct = "for"
+)
+
+// BranchEvaluated - условие конструкции Construct на участке Loc вычислено в Taken:
+// для if выбрана ветка then, для цикла выполняется тело (режим событий ветвлений)
+type BranchEvaluated struct {
+	Construct BranchConstruct    `json:"construct"`
+	Loc       converter.Location `json:"loc"`
+	Taken     bool               `json:"taken"`
+}
+
+// InfiniteLoop - состояние программы на заголовке цикла Construct с условием на участке Loc повторилось
+// через Cycle итераций, поэтому цикл не завершится. Unchanged - переменные условия, не изменявшиеся за эти итерации
+type InfiniteLoop struct {
+	Construct BranchConstruct    `json:"construct"`
+	Loc       converter.Location `json:"loc"`
+	Cycle     int                `json:"cycle"`
+	Unchanged []string           `json:"unchanged,omitempty"`
+	Message   string             `json:"message"`
+	PrevError string             `json:"-"`
 }
 
 type UndefinedBehavior struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	PrevError string `json:"-"`
 }
 
 type RuntimeError struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	PrevError string `json:"-"`
 }
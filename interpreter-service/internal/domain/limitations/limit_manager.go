@@ -45,6 +45,15 @@ func (lm *LimitManager) AllocateArray2D(size1 int, size2 int) error {
 	return nil
 }
 
+// AllocateHeap учитывает блок динамической памяти размера sizeBytes:
+// каждые начатые 4 байта расходуют один элемент
+func (lm *LimitManager) AllocateHeap(sizeBytes int) error {
+	if sizeBytes <= 0 {
+		return NewErrLimitExceeded(AllocatingElement)
+	}
+	return lm.AllocateArray((sizeBytes + 3) / 4)
+}
+
 func (lm *LimitManager) MakeStep() error {
 	if lm.StepsRemained <= 0 {
 		return NewErrLimitExceeded(MakingStep)
@@ -121,6 +121,20 @@ func TestLimitManager_AllocateArray2D_Overflow(t *testing.T) {
 	assert.Equal(t, math.MaxInt, lm.AllocatedElementsRemained)
 }
 
+func TestLimitManager_AllocateHeap(t *testing.T) {
+	lm := &LimitManager{AllocatedElementsRemained: 5}
+
+	assert.NoError(t, lm.AllocateHeap(12))
+	assert.Equal(t, 2, lm.AllocatedElementsRemained)
+
+	assert.NoError(t, lm.AllocateHeap(1))
+	assert.Equal(t, 1, lm.AllocatedElementsRemained)
+
+	err := lm.AllocateHeap(5)
+	assert.EqualError(t, err, "too many allocations in program")
+	assert.Equal(t, 1, lm.AllocatedElementsRemained)
+}
+
 func TestLimitManager_MakeStep_Success(t *testing.T) {
 	lm := &LimitManager{StepsRemained: 2}
 
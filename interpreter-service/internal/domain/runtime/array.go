@@ -7,30 +7,33 @@ import (
 )
 
 type Array struct {
+	ID     int            `json:"id,omitempty"` // идентификатор объявления (0 для строки двумерного массива)
 	Name   string         `json:"name"`
+	Type   Type           `json:"type"` // тип элементов
 	Size   int            `json:"size"`
 	Values []ArrayElement `json:"values"`
 }
 
-func NewArray(name string, size int, value []ArrayElement, step int, isGlobal bool) *Array {
+func NewArray(name string, valueType Type, size int, value []ArrayElement, step int, isGlobal bool) *Array {
 	ret := &Array{}
 	ret.Name = name
+	ret.Type = valueType
 	ret.Size = size
 	if value != nil {
 		ret.Values = make([]ArrayElement, size)
 		for i := range ret.Values {
-			ret.Values[i] = *NewArrayElement(value[i].Value, step, isGlobal)
+			ret.Values[i] = *NewArrayElement(valueType, value[i].Value, step, isGlobal)
 		}
 	} else {
 		ret.Values = make([]ArrayElement, size)
 		for i := range ret.Values {
-			ret.Values[i] = *NewArrayElement(nil, step, isGlobal)
+			ret.Values[i] = *NewArrayElement(valueType, nil, step, isGlobal)
 		}
 	}
 	return ret
 }
 
-func (a *Array) ChangeElement(ind int, value int, step int) error {
+func (a *Array) ChangeElement(ind int, value Value, step int) error {
 	if ind < 0 || ind >= len(a.Values) {
 		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("index out of bounds in array %s", a.Name))
 	}
@@ -44,3 +47,10 @@ func (a *Array) GetElement(ind int) (*ArrayElement, error) {
 	}
 	return &a.Values[ind], nil
 }
+
+// Clone возвращает независимую копию массива
+func (a *Array) Clone() *Array {
+	clone := *a
+	clone.Values = cloneElements(a.Values)
+	return &clone
+}
@@ -7,22 +7,24 @@ import (
 )
 
 type Array2D struct {
+	ID     int     `json:"id,omitempty"`
 	Name   string  `json:"name"`
+	Type   Type    `json:"type"` // тип элементов
 	Size1  int     `json:"size1"`
 	Size2  int     `json:"size2"`
 	Values []Array `json:"values"`
 }
 
-func NewArray2D(name string, size1, size2 int, value []Array, step int, isGlobal bool) *Array2D {
-	ret := &Array2D{Name: name, Size1: size1, Size2: size2}
+func NewArray2D(name string, valueType Type, size1, size2 int, value []Array, step int, isGlobal bool) *Array2D {
+	ret := &Array2D{Name: name, Type: valueType, Size1: size1, Size2: size2}
 	if value != nil {
 		ret.Values = make([]Array, size1)
 		for i := 0; i < size1; i++ {
 			tmpArr := make([]ArrayElement, size2)
 			for j := 0; j < size2; j++ {
-				tmpArr[j] = *NewArrayElement(value[i].Values[j].Value, step, isGlobal)
+				tmpArr[j] = *NewArrayElement(valueType, value[i].Values[j].Value, step, isGlobal)
 			}
-			ret.Values[i] = *NewArray("", size2, tmpArr, step, isGlobal)
+			ret.Values[i] = *NewArray("", valueType, size2, tmpArr, step, isGlobal)
 		}
 		return ret
 	}
@@ -31,15 +33,15 @@ func NewArray2D(name string, size1, size2 int, value []Array, step int, isGlobal
 	for i := 0; i < size1; i++ {
 		tmpArr := make([]ArrayElement, size2)
 		for j := 0; j < size2; j++ {
-			tmpArr[j] = *NewArrayElement(nil, step, isGlobal)
+			tmpArr[j] = *NewArrayElement(valueType, nil, step, isGlobal)
 		}
-		ret.Values[i] = *NewArray("", size2, tmpArr, step, isGlobal)
+		ret.Values[i] = *NewArray("", valueType, size2, tmpArr, step, isGlobal)
 	}
 
 	return ret
 }
 
-func (a *Array2D) ChangeElement(ind1, ind2 int, value int, step int) error {
+func (a *Array2D) ChangeElement(ind1, ind2 int, value Value, step int) error {
 	if ind1 < 0 || ind1 >= len(a.Values) || ind2 < 0 || ind2 >= a.Values[ind1].Size {
 		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("index out of bounds in array2d %s", a.Name))
 	}
@@ -48,14 +50,14 @@ func (a *Array2D) ChangeElement(ind1, ind2 int, value int, step int) error {
 	return nil
 }
 
-func (a *Array2D) GetElement(ind1, ind2 int) (int, error) {
+func (a *Array2D) GetElement(ind1, ind2 int) (Value, error) {
 	if ind1 < 0 || ind1 >= len(a.Values) || ind2 < 0 || ind2 >= a.Values[ind1].Size {
-		return 0, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("index out of bounds in array2d %s", a.Name))
+		return Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("index out of bounds in array2d %s", a.Name))
 	}
 	el, _ := a.Values[ind1].GetElement(ind2)
 	val, err := el.GetValue()
 	if err != nil {
-		return 0, err
+		return Value{}, err
 	}
 	return val, nil
 }
@@ -66,3 +68,13 @@ func (a *Array2D) GetArray(ind int) (*Array, error) {
 	}
 	return &a.Values[ind], nil
 }
+
+// Clone возвращает независимую копию двумерного массива
+func (a *Array2D) Clone() *Array2D {
+	clone := *a
+	clone.Values = make([]Array, len(a.Values))
+	for ind := range a.Values {
+		clone.Values[ind] = *a.Values[ind].Clone()
+	}
+	return &clone
+}
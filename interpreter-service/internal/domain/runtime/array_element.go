@@ -3,35 +3,50 @@ package runtime
 import runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
 
 type ArrayElement struct {
-	Value       *int `json:"value,omitempty"`
-	StepChanged int  `json:"step_changed"` //для подстветки на фронте
+	Value       *Value `json:"value,omitempty"`
+	StepChanged int    `json:"step_changed"` //для подстветки на фронте
 }
 
-func NewArrayElement(value *int, step int, isGlobal bool) *ArrayElement {
-	var v *int
+func NewArrayElement(valueType Type, value *Value, step int, isGlobal bool) *ArrayElement {
+	var v *Value
 	if value != nil {
 		cv := *value
 		v = &cv
 	} else if isGlobal {
-		val := 0
+		val := ZeroValue(valueType)
 		v = &val
 	}
 	return &ArrayElement{Value: v, StepChanged: step}
 }
 
-func (ae *ArrayElement) ChangeValue(value int, step int) int {
+func (ae *ArrayElement) ChangeValue(value Value, step int) Value {
 	if ae.Value == nil {
-		ae.Value = new(int)
+		ae.Value = new(Value)
 	}
 	*ae.Value = value
 	ae.StepChanged = step
 	return value
 }
 
-func (ae *ArrayElement) GetValue() (int, error) {
+func (ae *ArrayElement) GetValue() (Value, error) {
 	if ae.Value == nil {
-		return 0, runtimeerrors.NewErrUndefinedBehavior("getting an uninitialized array element")
+		return Value{}, runtimeerrors.NewErrUndefinedBehavior("getting an uninitialized array element")
 	} else {
 		return *ae.Value, nil
 	}
 }
+
+func (ae ArrayElement) clone() ArrayElement {
+	return ArrayElement{Value: CloneValue(ae.Value), StepChanged: ae.StepChanged}
+}
+
+func cloneElements(elements []ArrayElement) []ArrayElement {
+	if elements == nil {
+		return nil
+	}
+	clone := make([]ArrayElement, len(elements))
+	for ind, el := range elements {
+		clone[ind] = el.clone()
+	}
+	return clone
+}
@@ -51,3 +51,16 @@ func (cs *CallStack) GetArrayInCurrentFrame(name string) (*Array, bool) {
 func (cs *CallStack) GetArray2DInCurrentFrame(name string) (*Array2D, bool) {
 	return cs.GetCurrentFrame().GetArray2D(name)
 }
+
+func (cs *CallStack) GetDeclaredInCurrentFrame(id int) (Declared, bool) {
+	return cs.GetCurrentFrame().GetByID(id)
+}
+
+// Clone возвращает копию стека вызовов, кадры которой используют глобальную область видимости globalScope
+func (cs *CallStack) Clone(globalScope *Scope) *CallStack {
+	clone := &CallStack{Frames: make([]*StackFrame, len(cs.Frames))}
+	for ind, frame := range cs.Frames {
+		clone.Frames[ind] = frame.Clone(globalScope)
+	}
+	return clone
+}
@@ -10,6 +10,20 @@ func (ds *DeclarationStack) Declare(d Declared) {
 	ds.Declarations = append(ds.Declarations, d)
 }
 
+// Undeclare удаляет последнее объявление
+func (ds *DeclarationStack) Undeclare() (Declared, bool) {
+	if len(ds.Declarations) == 0 {
+		return nil, false
+	}
+	last := ds.Declarations[len(ds.Declarations)-1]
+	ds.Declarations = ds.Declarations[:len(ds.Declarations)-1]
+	if len(ds.Declarations) == 0 {
+		// пустой стек совпадает с новым, в котором еще ничего не объявлено
+		ds.Declarations = nil
+	}
+	return last, true
+}
+
 func (ds *DeclarationStack) GetVariable(name string) (*Variable, bool) {
 	for i := range ds.Declarations {
 		if v, ok := ds.Declarations[i].(*Variable); ok {
@@ -42,3 +56,42 @@ func (ds *DeclarationStack) GetArray2D(name string) (*Array2D, bool) {
 	}
 	return nil, false
 }
+
+// GetByID возвращает переменную или массив с идентификатором объявления id
+func (ds *DeclarationStack) GetByID(id int) (Declared, bool) {
+	for i := range ds.Declarations {
+		switch v := ds.Declarations[i].(type) {
+		case *Variable:
+			if v.ID == id {
+				return v, true
+			}
+		case *Array:
+			if v.ID == id {
+				return v, true
+			}
+		case *Array2D:
+			if v.ID == id {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Clone возвращает копию стека объявлений с независимыми копиями переменных и массивов
+func (ds *DeclarationStack) Clone() DeclarationStack {
+	clone := DeclarationStack{}
+	for _, d := range ds.Declarations {
+		switch v := d.(type) {
+		case *Variable:
+			clone.Declare(v.Clone())
+		case *Array:
+			clone.Declare(v.Clone())
+		case *Array2D:
+			clone.Declare(v.Clone())
+		default:
+			clone.Declare(d)
+		}
+	}
+	return clone
+}
@@ -0,0 +1,27 @@
+package runtime
+
+// Enumerator - именованная целочисленная константа перечисления
+type Enumerator struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// Enum - перечисление с константами в порядке объявления
+type Enum struct {
+	Name        string       `json:"name"`
+	Enumerators []Enumerator `json:"enumerators"`
+}
+
+func NewEnum(name string, enumerators []Enumerator) *Enum {
+	return &Enum{Name: name, Enumerators: enumerators}
+}
+
+// EnumeratorName возвращает имя первой константы с данным значением
+func (e *Enum) EnumeratorName(value int) (string, bool) {
+	for _, en := range e.Enumerators {
+		if en.Value == value {
+			return en.Name, true
+		}
+	}
+	return "", false
+}
@@ -41,3 +41,27 @@ func NewErrRuntime(reason string) error {
 func (e ErrRuntime) Error() string {
 	return fmt.Sprintf("runtime error: %s", e.reason)
 }
+
+type ErrAssertionFailed struct {
+	reason string
+}
+
+func NewErrAssertionFailed(reason string) error {
+	return ErrAssertionFailed{reason: reason}
+}
+
+func (e ErrAssertionFailed) Error() string {
+	return fmt.Sprintf("assertion failed: %s", e.reason)
+}
+
+type ErrInfiniteLoop struct {
+	reason string
+}
+
+func NewErrInfiniteLoop(reason string) error {
+	return ErrInfiniteLoop{reason: reason}
+}
+
+func (e ErrInfiniteLoop) Error() string {
+	return fmt.Sprintf("infinite loop: %s", e.reason)
+}
@@ -0,0 +1,163 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// heapBase - адрес начала кучи
+	heapBase = 0x10000
+	// heapRegionSize - каждый блок занимает собственную область адресов, блок начинается в ее середине,
+	// поэтому адрес с выходом за границы блока в обе стороны однозначно относится к этому блоку
+	heapRegionSize = 0x10000
+	// MaxHeapBlockSize - наибольший размер блока в байтах, для большего запроса malloc возвращает NULL
+	MaxHeapBlockSize = heapRegionSize / 2
+)
+
+// FormatAddress возвращает отображение адреса указателя
+func FormatAddress(address int) string {
+	if address == 0 {
+		return "NULL"
+	}
+	return fmt.Sprintf("0x%x", address)
+}
+
+// HeapBlock - блок динамической памяти. Пока блок не приведен к типизированному указателю,
+// его тип пуст и элементов нет; после приведения к T* блок хранит Size / sizeof(T) элементов типа T
+type HeapBlock struct {
+	Address int            `json:"address"`
+	Size    int            `json:"size"` // размер в байтах
+	Type    Type           `json:"type,omitempty"`
+	Values  []ArrayElement `json:"values"`
+	Line    int            `json:"line"`   // строка выделения
+	Zeroed  bool           `json:"zeroed"` // блок выделен calloc и заполнен нулями
+	Freed   bool           `json:"-"`
+}
+
+// SetType задает тип элементов блока и создает элементы (нулевые для calloc, иначе неинициализированные)
+func (b *HeapBlock) SetType(t Type, step int) error {
+	size, err := SizeOf(t)
+	if err != nil {
+		return err
+	}
+	b.Type = t
+	b.Values = make([]ArrayElement, b.Size/size)
+	for ind := range b.Values {
+		b.Values[ind] = *NewArrayElement(t, nil, step, b.Zeroed)
+	}
+	return nil
+}
+
+// CopyFrom копирует значения из старого блока при realloc (не больше, чем помещается в новый блок)
+func (b *HeapBlock) CopyFrom(old *HeapBlock, step int) {
+	for ind := 0; ind < len(b.Values) && ind < len(old.Values); ind++ {
+		b.Values[ind] = ArrayElement{Value: CloneValue(old.Values[ind].Value), StepChanged: step}
+	}
+}
+
+// Heap - модель кучи. Адреса блоков не переиспользуются, поэтому освобожденный блок
+// остается известен и обращение к нему распознается как use-after-free
+type Heap struct {
+	Blocks []*HeapBlock `json:"blocks"` // неосвобожденные блоки в порядке выделения
+	all    map[int]*HeapBlock
+}
+
+func NewHeap() *Heap {
+	return &Heap{Blocks: []*HeapBlock{}, all: make(map[int]*HeapBlock)}
+}
+
+// NextAddress возвращает адрес, который получит следующий выделенный блок
+func (h *Heap) NextAddress() int {
+	return heapBase + len(h.all)*heapRegionSize + heapRegionSize/2
+}
+
+// Allocate добавляет блок размера size по адресу address
+func (h *Heap) Allocate(address, size, line int, zeroed bool) *HeapBlock {
+	block := &HeapBlock{Address: address, Size: size, Values: []ArrayElement{}, Line: line, Zeroed: zeroed}
+	h.all[regionOf(address)] = block
+	h.Blocks = append(h.Blocks, block)
+	sort.Slice(h.Blocks, func(a, b int) bool { return h.Blocks[a].Address < h.Blocks[b].Address })
+	return block
+}
+
+// Free помечает блок освобожденным и убирает его из списка неосвобожденных
+func (h *Heap) Free(block *HeapBlock) {
+	block.Freed = true
+	for ind, b := range h.Blocks {
+		if b == block {
+			h.Blocks = append(h.Blocks[:ind], h.Blocks[ind+1:]...)
+			return
+		}
+	}
+}
+
+// Unfree отменяет освобождение блока
+func (h *Heap) Unfree(block *HeapBlock) {
+	block.Freed = false
+	h.Blocks = append(h.Blocks, block)
+	sort.Slice(h.Blocks, func(a, b int) bool { return h.Blocks[a].Address < h.Blocks[b].Address })
+}
+
+// Remove отменяет выделение блока: блок и его область адресов перестают быть известны куче
+func (h *Heap) Remove(block *HeapBlock) {
+	delete(h.all, regionOf(block.Address))
+	for ind, b := range h.Blocks {
+		if b == block {
+			h.Blocks = append(h.Blocks[:ind], h.Blocks[ind+1:]...)
+			return
+		}
+	}
+}
+
+// Find возвращает блок, к области которого относится адрес (в том числе освобожденный)
+func (h *Heap) Find(address int) (*HeapBlock, bool) {
+	if address < heapBase {
+		return nil, false
+	}
+	block, ok := h.all[regionOf(address)]
+	return block, ok
+}
+
+func regionOf(address int) int {
+	return (address - heapBase) / heapRegionSize
+}
+
+// Clone возвращает независимую копию блока
+func (b *HeapBlock) Clone() *HeapBlock {
+	clone := *b
+	clone.Values = cloneElements(b.Values)
+	return &clone
+}
+
+// AllBlocks возвращает все блоки кучи, включая освобожденные, в порядке адресов
+func (h *Heap) AllBlocks() []*HeapBlock {
+	blocks := make([]*HeapBlock, 0, len(h.all))
+	for _, block := range h.all {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(a, b int) bool { return blocks[a].Address < blocks[b].Address })
+	return blocks
+}
+
+// RestoreHeap восстанавливает кучу по всем ее блокам, включая освобожденные
+func RestoreHeap(blocks []*HeapBlock) *Heap {
+	h := NewHeap()
+	for _, block := range blocks {
+		h.all[regionOf(block.Address)] = block
+		if !block.Freed {
+			h.Blocks = append(h.Blocks, block)
+		}
+	}
+	sort.Slice(h.Blocks, func(a, b int) bool { return h.Blocks[a].Address < h.Blocks[b].Address })
+	return h
+}
+
+// Clone возвращает независимую копию кучи
+func (h *Heap) Clone() *Heap {
+	blocks := h.AllBlocks()
+	for ind, block := range blocks {
+		blocks[ind] = block.Clone()
+	}
+	return RestoreHeap(blocks)
+}
@@ -1,6 +1,8 @@
 package runtimeinterfaces
 
+import "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+
 type Changeable interface {
-	ChangeValue(value int, step int) int
-	GetValue() (int, error)
+	ChangeValue(value runtime.Value, step int) runtime.Value
+	GetValue() (runtime.Value, error)
 }
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,18 +11,18 @@ import (
 // ============ Variable Tests ============
 
 func TestNewVariableWithInitialValue(t *testing.T) {
-	val := 42
-	variable := NewVariable("x", &val, 0, false)
+	val := NewIntValue(42)
+	variable := NewVariable("x", TypeInt, &val, 0, false)
 
 	require.NotNil(t, variable)
 	assert.Equal(t, "x", variable.Name)
 	assert.NotNil(t, variable.Value)
-	assert.Equal(t, 42, *variable.Value)
+	assert.Equal(t, NewIntValue(42), *variable.Value)
 	assert.Equal(t, 0, variable.StepChanged)
 }
 
 func TestNewVariableWithoutInit(t *testing.T) {
-	variable := NewVariable("y", nil, 1, false)
+	variable := NewVariable("y", TypeInt, nil, 1, false)
 
 	require.NotNil(t, variable)
 	assert.Equal(t, "y", variable.Name)
@@ -30,26 +31,26 @@ func TestNewVariableWithoutInit(t *testing.T) {
 }
 
 func TestNewVariableGlobal(t *testing.T) {
-	variable := NewVariable("global_x", nil, 0, true)
+	variable := NewVariable("global_x", TypeInt, nil, 0, true)
 
 	require.NotNil(t, variable)
 	assert.Equal(t, "global_x", variable.Name)
 	assert.NotNil(t, variable.Value)
-	assert.Equal(t, 0, *variable.Value)
+	assert.Equal(t, NewIntValue(0), *variable.Value)
 }
 
 func TestVariableGetValue(t *testing.T) {
-	val := 99
-	variable := NewVariable("x", &val, 0, false)
+	val := NewIntValue(99)
+	variable := NewVariable("x", TypeInt, &val, 0, false)
 
 	result, err := variable.GetValue()
 
 	assert.NoError(t, err)
-	assert.Equal(t, 99, result)
+	assert.Equal(t, NewIntValue(99), result)
 }
 
 func TestVariableGetValueUninitialized(t *testing.T) {
-	variable := NewVariable("x", nil, 0, false)
+	variable := NewVariable("x", TypeInt, nil, 0, false)
 
 	_, err := variable.GetValue()
 
@@ -59,62 +60,62 @@ func TestVariableGetValueUninitialized(t *testing.T) {
 }
 
 func TestVariableChangeValue(t *testing.T) {
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 
-	variable.ChangeValue(50, 2)
+	variable.ChangeValue(NewIntValue(50), 2)
 
 	assert.NotNil(t, variable.Value)
-	assert.Equal(t, 50, *variable.Value)
+	assert.Equal(t, NewIntValue(50), *variable.Value)
 	assert.Equal(t, 2, variable.StepChanged)
 }
 
 func TestVariableChangeValueOnNilAllocates(t *testing.T) {
-	variable := NewVariable("x", nil, 0, false)
+	variable := NewVariable("x", TypeInt, nil, 0, false)
 	assert.Nil(t, variable.Value)
 
-	variable.ChangeValue(75, 1)
+	variable.ChangeValue(NewIntValue(75), 1)
 
 	// Should allocate memory
 	assert.NotNil(t, variable.Value)
-	assert.Equal(t, 75, *variable.Value)
+	assert.Equal(t, NewIntValue(75), *variable.Value)
 	assert.Equal(t, 1, variable.StepChanged)
 }
 
 func TestVariableChangeValueMultipleTimes(t *testing.T) {
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 
-	variable.ChangeValue(10, 1)
-	assert.Equal(t, 10, *variable.Value)
+	variable.ChangeValue(NewIntValue(10), 1)
+	assert.Equal(t, NewIntValue(10), *variable.Value)
 
-	variable.ChangeValue(20, 2)
-	assert.Equal(t, 20, *variable.Value)
+	variable.ChangeValue(NewIntValue(20), 2)
+	assert.Equal(t, NewIntValue(20), *variable.Value)
 	assert.Equal(t, 2, variable.StepChanged)
 }
 
 func TestVariableChangeValueTracksStep(t *testing.T) {
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 
-	variable.ChangeValue(100, 5)
+	variable.ChangeValue(NewIntValue(100), 5)
 	assert.Equal(t, 5, variable.StepChanged)
 
-	variable.ChangeValue(200, 10)
+	variable.ChangeValue(NewIntValue(200), 10)
 	assert.Equal(t, 10, variable.StepChanged)
 }
 
 // ============ ArrayElement Tests ============
 
 func TestNewArrayElementWithInitialValue(t *testing.T) {
-	val := 123
-	element := NewArrayElement(&val, 0, false)
+	val := NewIntValue(123)
+	element := NewArrayElement(TypeInt, &val, 0, false)
 
 	require.NotNil(t, element)
 	assert.NotNil(t, element.Value)
-	assert.Equal(t, 123, *element.Value)
+	assert.Equal(t, NewIntValue(123), *element.Value)
 	assert.Equal(t, 0, element.StepChanged)
 }
 
 func TestNewArrayElementWithoutInit(t *testing.T) {
-	element := NewArrayElement(nil, 1, false)
+	element := NewArrayElement(TypeInt, nil, 1, false)
 
 	require.NotNil(t, element)
 	assert.Nil(t, element.Value)
@@ -122,25 +123,25 @@ func TestNewArrayElementWithoutInit(t *testing.T) {
 }
 
 func TestNewArrayElementGlobal(t *testing.T) {
-	element := NewArrayElement(nil, 0, true)
+	element := NewArrayElement(TypeInt, nil, 0, true)
 
 	require.NotNil(t, element)
 	assert.NotNil(t, element.Value)
-	assert.Equal(t, 0, *element.Value)
+	assert.Equal(t, NewIntValue(0), *element.Value)
 }
 
 func TestArrayElementGetValue(t *testing.T) {
-	val := 77
-	element := NewArrayElement(&val, 0, false)
+	val := NewIntValue(77)
+	element := NewArrayElement(TypeInt, &val, 0, false)
 
 	result, err := element.GetValue()
 
 	assert.NoError(t, err)
-	assert.Equal(t, 77, result)
+	assert.Equal(t, NewIntValue(77), result)
 }
 
 func TestArrayElementGetValueUninitialized(t *testing.T) {
-	element := NewArrayElement(nil, 0, false)
+	element := NewArrayElement(TypeInt, nil, 0, false)
 
 	_, err := element.GetValue()
 
@@ -149,52 +150,52 @@ func TestArrayElementGetValueUninitialized(t *testing.T) {
 }
 
 func TestArrayElementChangeValue(t *testing.T) {
-	element := NewArrayElement(nil, 0, true)
+	element := NewArrayElement(TypeInt, nil, 0, true)
 
-	element.ChangeValue(88, 3)
+	element.ChangeValue(NewIntValue(88), 3)
 
 	assert.NotNil(t, element.Value)
-	assert.Equal(t, 88, *element.Value)
+	assert.Equal(t, NewIntValue(88), *element.Value)
 	assert.Equal(t, 3, element.StepChanged)
 }
 
 func TestArrayElementChangeValueOnNilAllocates(t *testing.T) {
-	element := NewArrayElement(nil, 0, false)
+	element := NewArrayElement(TypeInt, nil, 0, false)
 	assert.Nil(t, element.Value)
 
-	element.ChangeValue(50, 1)
+	element.ChangeValue(NewIntValue(50), 1)
 
 	// Should allocate memory
 	assert.NotNil(t, element.Value)
-	assert.Equal(t, 50, *element.Value)
+	assert.Equal(t, NewIntValue(50), *element.Value)
 	assert.Equal(t, 1, element.StepChanged)
 }
 
 func TestArrayElementChangeValueMultipleTimes(t *testing.T) {
-	element := NewArrayElement(nil, 0, true)
+	element := NewArrayElement(TypeInt, nil, 0, true)
 
-	element.ChangeValue(5, 1)
-	assert.Equal(t, 5, *element.Value)
+	element.ChangeValue(NewIntValue(5), 1)
+	assert.Equal(t, NewIntValue(5), *element.Value)
 
-	element.ChangeValue(15, 2)
-	assert.Equal(t, 15, *element.Value)
+	element.ChangeValue(NewIntValue(15), 2)
+	assert.Equal(t, NewIntValue(15), *element.Value)
 	assert.Equal(t, 2, element.StepChanged)
 }
 
 func TestArrayElementChangeValueTracksStep(t *testing.T) {
-	element := NewArrayElement(nil, 0, true)
+	element := NewArrayElement(TypeInt, nil, 0, true)
 
-	element.ChangeValue(100, 7)
+	element.ChangeValue(NewIntValue(100), 7)
 	assert.Equal(t, 7, element.StepChanged)
 
-	element.ChangeValue(200, 14)
+	element.ChangeValue(NewIntValue(200), 14)
 	assert.Equal(t, 14, element.StepChanged)
 }
 
 // ============ Array Tests ============
 
 func TestNewArrayWithoutInit(t *testing.T) {
-	arr := NewArray("nums", 5, nil, 0, true)
+	arr := NewArray("nums", TypeInt, 5, nil, 0, true)
 
 	require.NotNil(t, arr)
 	assert.Equal(t, "nums", arr.Name)
@@ -204,12 +205,12 @@ func TestNewArrayWithoutInit(t *testing.T) {
 	// All elements should be initialized to 0 (global array)
 	for i, elem := range arr.Values {
 		assert.NotNil(t, elem.Value, "element at index %d should have value", i)
-		assert.Equal(t, 0, *elem.Value, "element at index %d should be 0", i)
+		assert.Equal(t, NewIntValue(0), *elem.Value, "element at index %d should be 0", i)
 	}
 }
 
 func TestNewArrayLocal(t *testing.T) {
-	arr := NewArray("local_nums", 3, nil, 0, false)
+	arr := NewArray("local_nums", TypeInt, 3, nil, 0, false)
 
 	require.NotNil(t, arr)
 	assert.Equal(t, "local_nums", arr.Name)
@@ -226,11 +227,11 @@ func TestNewArrayWithInitializedValues(t *testing.T) {
 	// Create pre-initialized array elements
 	elements := make([]ArrayElement, 3)
 	for i := range elements {
-		val := i * 10
-		elements[i] = *NewArrayElement(&val, 0, false)
+		val := NewIntValue(i * 10)
+		elements[i] = *NewArrayElement(TypeInt, &val, 0, false)
 	}
 
-	arr := NewArray("initialized_arr", 3, elements, 5, false)
+	arr := NewArray("initialized_arr", TypeInt, 3, elements, 5, false)
 
 	require.NotNil(t, arr)
 	assert.Equal(t, "initialized_arr", arr.Name)
@@ -240,45 +241,45 @@ func TestNewArrayWithInitializedValues(t *testing.T) {
 	// Check that the provided values are used
 	for i, elem := range arr.Values {
 		assert.NotNil(t, elem.Value)
-		assert.Equal(t, i*10, *elem.Value)
+		assert.Equal(t, NewIntValue(i*10), *elem.Value)
 	}
 }
 
 func TestArrayChangeElement(t *testing.T) {
-	arr := NewArray("arr", 5, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 
-	err := arr.ChangeElement(2, 42, 1)
+	err := arr.ChangeElement(2, NewIntValue(42), 1)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 42, *arr.Values[2].Value)
+	assert.Equal(t, NewIntValue(42), *arr.Values[2].Value)
 	assert.Equal(t, 1, arr.Values[2].StepChanged)
 }
 
 func TestArrayChangeElementOutOfBounds(t *testing.T) {
-	arr := NewArray("arr", 5, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 
-	errNegative := arr.ChangeElement(-1, 10, 1)
+	errNegative := arr.ChangeElement(-1, NewIntValue(10), 1)
 	assert.Error(t, errNegative)
 	assert.Contains(t, errNegative.Error(), "undefined behavior")
 
-	errTooLarge := arr.ChangeElement(10, 20, 1)
+	errTooLarge := arr.ChangeElement(10, NewIntValue(20), 1)
 	assert.Error(t, errTooLarge)
 	assert.Contains(t, errTooLarge.Error(), "undefined behavior")
 }
 
 func TestArrayGetElement(t *testing.T) {
-	arr := NewArray("arr", 5, nil, 0, true)
-	arr.ChangeElement(1, 99, 1)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
+	arr.ChangeElement(1, NewIntValue(99), 1)
 
 	val, err := arr.GetElement(1)
 
 	assert.NoError(t, err)
 	valInt, _ := val.GetValue()
-	assert.Equal(t, 99, valInt)
+	assert.Equal(t, NewIntValue(99), valInt)
 }
 
 func TestArrayGetElementOutOfBounds(t *testing.T) {
-	arr := NewArray("arr", 5, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 
 	_, errNegative := arr.GetElement(-1)
 	assert.Error(t, errNegative)
@@ -288,7 +289,7 @@ func TestArrayGetElementOutOfBounds(t *testing.T) {
 }
 
 func TestArrayGetElementUninitialized(t *testing.T) {
-	arr := NewArray("arr", 5, nil, 0, false)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, false)
 
 	val, err := arr.GetElement(0)
 	_, err = val.GetValue()
@@ -300,7 +301,7 @@ func TestArrayGetElementUninitialized(t *testing.T) {
 // ============ Array2D Tests ============
 
 func TestNewArray2DWithoutInitGlobal(t *testing.T) {
-	arr2d := NewArray2D("matrix", 2, 3, nil, 0, true)
+	arr2d := NewArray2D("matrix", TypeInt, 2, 3, nil, 0, true)
 
 	require.NotNil(t, arr2d)
 	assert.Equal(t, "matrix", arr2d.Name)
@@ -313,13 +314,13 @@ func TestNewArray2DWithoutInitGlobal(t *testing.T) {
 		assert.Len(t, row.Values, 3)
 		for j, el := range row.Values {
 			assert.NotNil(t, el.Value, "element at [%d][%d] should have value", i, j)
-			assert.Equal(t, 0, *el.Value, "element at [%d][%d] should be 0", i, j)
+			assert.Equal(t, NewIntValue(0), *el.Value, "element at [%d][%d] should be 0", i, j)
 		}
 	}
 }
 
 func TestNewArray2DWithoutInitLocal(t *testing.T) {
-	arr2d := NewArray2D("local_matrix", 2, 2, nil, 0, false)
+	arr2d := NewArray2D("local_matrix", TypeInt, 2, 2, nil, 0, false)
 
 	require.NotNil(t, arr2d)
 	assert.Equal(t, "local_matrix", arr2d.Name)
@@ -341,13 +342,13 @@ func TestNewArray2DWithInitializedValues(t *testing.T) {
 	for i := range rows {
 		elements := make([]ArrayElement, 3)
 		for j := range elements {
-			val := i*100 + j*10
-			elements[j] = *NewArrayElement(&val, 0, false)
+			val := NewIntValue(i*100 + j*10)
+			elements[j] = *NewArrayElement(TypeInt, &val, 0, false)
 		}
-		rows[i] = *NewArray("", 3, elements, 0, false)
+		rows[i] = *NewArray("", TypeInt, 3, elements, 0, false)
 	}
 
-	arr2d := NewArray2D("initialized_matrix", 2, 3, rows, 5, false)
+	arr2d := NewArray2D("initialized_matrix", TypeInt, 2, 3, rows, 5, false)
 
 	require.NotNil(t, arr2d)
 	assert.Equal(t, "initialized_matrix", arr2d.Name)
@@ -356,54 +357,54 @@ func TestNewArray2DWithInitializedValues(t *testing.T) {
 	for i, row := range arr2d.Values {
 		for j, el := range row.Values {
 			require.NotNil(t, el.Value)
-			assert.Equal(t, i*100+j*10, *el.Value)
+			assert.Equal(t, NewIntValue(i*100+j*10), *el.Value)
 		}
 	}
 }
 
 func TestArray2DChangeElement(t *testing.T) {
-	arr2d := NewArray2D("matrix", 2, 3, nil, 0, true)
+	arr2d := NewArray2D("matrix", TypeInt, 2, 3, nil, 0, true)
 
-	err := arr2d.ChangeElement(1, 2, 42, 3)
+	err := arr2d.ChangeElement(1, 2, NewIntValue(42), 3)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 42, *arr2d.Values[1].Values[2].Value)
+	assert.Equal(t, NewIntValue(42), *arr2d.Values[1].Values[2].Value)
 	assert.Equal(t, 3, arr2d.Values[1].Values[2].StepChanged)
 }
 
 func TestArray2DChangeElementOutOfBounds(t *testing.T) {
-	arr2d := NewArray2D("matrix", 2, 3, nil, 0, true)
+	arr2d := NewArray2D("matrix", TypeInt, 2, 3, nil, 0, true)
 
-	errRowNegative := arr2d.ChangeElement(-1, 0, 10, 1)
+	errRowNegative := arr2d.ChangeElement(-1, 0, NewIntValue(10), 1)
 	assert.Error(t, errRowNegative)
 	assert.Contains(t, errRowNegative.Error(), "undefined behavior")
 
-	errRowTooLarge := arr2d.ChangeElement(2, 0, 10, 1)
+	errRowTooLarge := arr2d.ChangeElement(2, 0, NewIntValue(10), 1)
 	assert.Error(t, errRowTooLarge)
 	assert.Contains(t, errRowTooLarge.Error(), "undefined behavior")
 
-	errColNegative := arr2d.ChangeElement(0, -1, 10, 1)
+	errColNegative := arr2d.ChangeElement(0, -1, NewIntValue(10), 1)
 	assert.Error(t, errColNegative)
 	assert.Contains(t, errColNegative.Error(), "undefined behavior")
 
-	errColTooLarge := arr2d.ChangeElement(0, 3, 10, 1)
+	errColTooLarge := arr2d.ChangeElement(0, 3, NewIntValue(10), 1)
 	assert.Error(t, errColTooLarge)
 	assert.Contains(t, errColTooLarge.Error(), "undefined behavior")
 }
 
 func TestArray2DGetElement(t *testing.T) {
-	arr2d := NewArray2D("matrix", 2, 3, nil, 0, true)
-	err := arr2d.ChangeElement(0, 1, 99, 1)
+	arr2d := NewArray2D("matrix", TypeInt, 2, 3, nil, 0, true)
+	err := arr2d.ChangeElement(0, 1, NewIntValue(99), 1)
 	require.NoError(t, err)
 
 	val, err := arr2d.GetElement(0, 1)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 99, val)
+	assert.Equal(t, NewIntValue(99), val)
 }
 
 func TestArray2DGetElementOutOfBounds(t *testing.T) {
-	arr2d := NewArray2D("matrix", 2, 3, nil, 0, true)
+	arr2d := NewArray2D("matrix", TypeInt, 2, 3, nil, 0, true)
 
 	_, errRowNegative := arr2d.GetElement(-1, 0)
 	assert.Error(t, errRowNegative)
@@ -419,7 +420,7 @@ func TestArray2DGetElementOutOfBounds(t *testing.T) {
 }
 
 func TestArray2DGetElementUninitialized(t *testing.T) {
-	arr2d := NewArray2D("matrix", 2, 3, nil, 0, false)
+	arr2d := NewArray2D("matrix", TypeInt, 2, 3, nil, 0, false)
 
 	_, err := arr2d.GetElement(0, 0)
 
@@ -431,7 +432,7 @@ func TestArray2DGetElementUninitialized(t *testing.T) {
 
 func TestDeclarationStackDeclareVariable(t *testing.T) {
 	ds := &DeclarationStack{}
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 
 	ds.Declare(variable)
 
@@ -440,9 +441,9 @@ func TestDeclarationStackDeclareVariable(t *testing.T) {
 
 func TestDeclarationStackDeclareMultiple(t *testing.T) {
 	ds := &DeclarationStack{}
-	var1 := NewVariable("x", nil, 0, true)
-	var2 := NewVariable("y", nil, 0, true)
-	arr := NewArray("arr", 5, nil, 0, true)
+	var1 := NewVariable("x", TypeInt, nil, 0, true)
+	var2 := NewVariable("y", TypeInt, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 
 	ds.Declare(var1)
 	ds.Declare(var2)
@@ -453,7 +454,7 @@ func TestDeclarationStackDeclareMultiple(t *testing.T) {
 
 func TestDeclarationStackGetVariable(t *testing.T) {
 	ds := &DeclarationStack{}
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 	ds.Declare(variable)
 
 	found, ok := ds.GetVariable("x")
@@ -465,7 +466,7 @@ func TestDeclarationStackGetVariable(t *testing.T) {
 
 func TestDeclarationStackGetVariableNotFound(t *testing.T) {
 	ds := &DeclarationStack{}
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 	ds.Declare(variable)
 
 	_, ok := ds.GetVariable("y")
@@ -475,7 +476,7 @@ func TestDeclarationStackGetVariableNotFound(t *testing.T) {
 
 func TestDeclarationStackGetArray(t *testing.T) {
 	ds := &DeclarationStack{}
-	arr := NewArray("numbers", 10, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 10, nil, 0, true)
 	ds.Declare(arr)
 
 	found, ok := ds.GetArray("numbers")
@@ -488,7 +489,7 @@ func TestDeclarationStackGetArray(t *testing.T) {
 
 func TestDeclarationStackGetArrayNotFound(t *testing.T) {
 	ds := &DeclarationStack{}
-	arr := NewArray("numbers", 10, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 10, nil, 0, true)
 	ds.Declare(arr)
 
 	_, ok := ds.GetArray("missing")
@@ -499,9 +500,9 @@ func TestDeclarationStackGetArrayNotFound(t *testing.T) {
 func TestDeclarationStackMixedTypes(t *testing.T) {
 	ds := &DeclarationStack{}
 
-	v1 := NewVariable("x", nil, 0, true)
-	v2 := NewVariable("y", nil, 0, true)
-	arr := NewArray("arr", 5, nil, 0, true)
+	v1 := NewVariable("x", TypeInt, nil, 0, true)
+	v2 := NewVariable("y", TypeInt, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 
 	ds.Declare(v1)
 	ds.Declare(arr)
@@ -527,7 +528,7 @@ func TestDeclarationStackMixedTypes(t *testing.T) {
 
 func TestDeclarationStackGetVariableWrongType(t *testing.T) {
 	ds := &DeclarationStack{}
-	arr := NewArray("arr", 5, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 	ds.Declare(arr)
 
 	// Trying to get array as variable should fail
@@ -538,7 +539,7 @@ func TestDeclarationStackGetVariableWrongType(t *testing.T) {
 
 func TestDeclarationStackGetArrayWrongType(t *testing.T) {
 	ds := &DeclarationStack{}
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 	ds.Declare(variable)
 
 	// Trying to get variable as array should fail
@@ -552,7 +553,7 @@ func TestDeclarationStackOrderPreservation(t *testing.T) {
 
 	names := []string{"first", "second", "third"}
 	for _, name := range names {
-		v := NewVariable(name, nil, 0, true)
+		v := NewVariable(name, TypeInt, nil, 0, true)
 		ds.Declare(v)
 	}
 
@@ -601,7 +602,7 @@ func TestNewScopeWithParent(t *testing.T) {
 
 func TestScopeDeclareVariable(t *testing.T) {
 	scope := NewScope(nil)
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 
 	scope.Declare(variable)
 
@@ -610,9 +611,9 @@ func TestScopeDeclareVariable(t *testing.T) {
 
 func TestScopeDeclareMultiple(t *testing.T) {
 	scope := NewScope(nil)
-	v1 := NewVariable("x", nil, 0, true)
-	v2 := NewVariable("y", nil, 0, true)
-	arr := NewArray("arr", 5, nil, 0, true)
+	v1 := NewVariable("x", TypeInt, nil, 0, true)
+	v2 := NewVariable("y", TypeInt, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
 
 	scope.Declare(v1)
 	scope.Declare(v2)
@@ -623,7 +624,7 @@ func TestScopeDeclareMultiple(t *testing.T) {
 
 func TestScopeGetVariable(t *testing.T) {
 	scope := NewScope(nil)
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 	scope.Declare(variable)
 
 	found, ok := scope.GetVariable("x")
@@ -635,7 +636,7 @@ func TestScopeGetVariable(t *testing.T) {
 
 func TestScopeGetVariableNotFound(t *testing.T) {
 	scope := NewScope(nil)
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 	scope.Declare(variable)
 
 	_, ok := scope.GetVariable("y")
@@ -647,11 +648,11 @@ func TestScopeGetVariableCurrentScopeOnly(t *testing.T) {
 	// Scope should only search in its own declarations
 	// Not in parent scope (that's StackFrame's responsibility)
 	parentScope := NewScope(nil)
-	parentVar := NewVariable("parent_var", nil, 0, true)
+	parentVar := NewVariable("parent_var", TypeInt, nil, 0, true)
 	parentScope.Declare(parentVar)
 
 	childScope := NewScope(parentScope)
-	childVar := NewVariable("child_var", nil, 0, true)
+	childVar := NewVariable("child_var", TypeInt, nil, 0, true)
 	childScope.Declare(childVar)
 
 	// Should find in child scope
@@ -666,7 +667,7 @@ func TestScopeGetVariableCurrentScopeOnly(t *testing.T) {
 
 func TestScopeGetArray(t *testing.T) {
 	scope := NewScope(nil)
-	arr := NewArray("numbers", 10, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 10, nil, 0, true)
 	scope.Declare(arr)
 
 	found, ok := scope.GetArray("numbers")
@@ -678,7 +679,7 @@ func TestScopeGetArray(t *testing.T) {
 
 func TestScopeGetArrayNotFound(t *testing.T) {
 	scope := NewScope(nil)
-	arr := NewArray("numbers", 10, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 10, nil, 0, true)
 	scope.Declare(arr)
 
 	_, ok := scope.GetArray("missing")
@@ -689,9 +690,9 @@ func TestScopeGetArrayNotFound(t *testing.T) {
 func TestScopeMixedDeclarations(t *testing.T) {
 	scope := NewScope(nil)
 
-	v1 := NewVariable("x", nil, 0, true)
-	arr := NewArray("arr", 5, nil, 0, true)
-	v2 := NewVariable("y", nil, 0, true)
+	v1 := NewVariable("x", TypeInt, nil, 0, true)
+	arr := NewArray("arr", TypeInt, 5, nil, 0, true)
+	v2 := NewVariable("y", TypeInt, nil, 0, true)
 
 	scope.Declare(v1)
 	scope.Declare(arr)
@@ -724,27 +725,27 @@ func TestScopeHierarchy(t *testing.T) {
 
 func TestScopeDeclareAndRetrieveSameVariable(t *testing.T) {
 	scope := NewScope(nil)
-	v := NewVariable("test", nil, 0, true)
+	v := NewVariable("test", TypeInt, nil, 0, true)
 	scope.Declare(v)
 
 	// Change the variable
-	v.ChangeValue(42, 1)
+	v.ChangeValue(NewIntValue(42), 1)
 
 	// Retrieve it and check the change is visible
 	found, ok := scope.GetVariable("test")
 	assert.True(t, ok)
 	val, err := found.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 42, val)
+	assert.Equal(t, NewIntValue(42), val)
 }
 
 func TestScopeDeclareAndRetrieveSameArray(t *testing.T) {
 	scope := NewScope(nil)
-	arr := NewArray("numbers", 5, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 5, nil, 0, true)
 	scope.Declare(arr)
 
 	// Change the array element
-	err := arr.ChangeElement(2, 99, 1)
+	err := arr.ChangeElement(2, NewIntValue(99), 1)
 	assert.NoError(t, err)
 
 	// Retrieve it and check the change is visible
@@ -753,7 +754,7 @@ func TestScopeDeclareAndRetrieveSameArray(t *testing.T) {
 	val, err := found.GetElement(2)
 	assert.NoError(t, err)
 	valInt, err := val.GetValue()
-	assert.Equal(t, 99, valInt)
+	assert.Equal(t, NewIntValue(99), valInt)
 }
 
 // ============ StackFrame Tests ============
@@ -827,7 +828,7 @@ func TestStackFrameExitScopeGlobal(t *testing.T) {
 func TestStackFrameDeclare(t *testing.T) {
 	globalScope := NewScope(nil)
 	stackFrame := NewStackFrame("main", globalScope)
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 
 	stackFrame.Declare(variable)
 
@@ -840,22 +841,22 @@ func TestStackFrameSetReturnValue(t *testing.T) {
 	globalScope := NewScope(nil)
 	stackFrame := NewStackFrame("func", globalScope)
 
-	stackFrame.SetReturnValue(42)
+	stackFrame.SetReturnValue(NewIntValue(42))
 
 	assert.NotNil(t, stackFrame.ReturnValue)
-	assert.Equal(t, 42, *stackFrame.ReturnValue)
+	assert.Equal(t, NewIntValue(42), *stackFrame.ReturnValue)
 }
 
 func TestStackFrameGetReturnValue(t *testing.T) {
 	globalScope := NewScope(nil)
 	stackFrame := NewStackFrame("func", globalScope)
-	stackFrame.SetReturnValue(99)
+	stackFrame.SetReturnValue(NewIntValue(99))
 
 	val, err := stackFrame.GetReturnValue()
 
 	assert.NoError(t, err)
 	assert.NotNil(t, val)
-	assert.Equal(t, 99, *val)
+	assert.Equal(t, NewIntValue(99), *val)
 }
 
 func TestStackFrameGetReturnValueNotSet(t *testing.T) {
@@ -872,16 +873,16 @@ func TestStackFrameSetReturnValueMultipleTimes(t *testing.T) {
 	globalScope := NewScope(nil)
 	stackFrame := NewStackFrame("func", globalScope)
 
-	stackFrame.SetReturnValue(10)
-	assert.Equal(t, 10, *stackFrame.ReturnValue)
+	stackFrame.SetReturnValue(NewIntValue(10))
+	assert.Equal(t, NewIntValue(10), *stackFrame.ReturnValue)
 
-	stackFrame.SetReturnValue(20)
-	assert.Equal(t, 20, *stackFrame.ReturnValue)
+	stackFrame.SetReturnValue(NewIntValue(20))
+	assert.Equal(t, NewIntValue(20), *stackFrame.ReturnValue)
 }
 
 func TestStackFrameGetVariableCurrentScope(t *testing.T) {
 	globalScope := NewScope(nil)
-	v := NewVariable("x", nil, 0, true)
+	v := NewVariable("x", TypeInt, nil, 0, true)
 	globalScope.Declare(v)
 
 	stackFrame := NewStackFrame("main", globalScope)
@@ -893,7 +894,7 @@ func TestStackFrameGetVariableCurrentScope(t *testing.T) {
 
 func TestStackFrameGetVariableFromParentScope(t *testing.T) {
 	globalScope := NewScope(nil)
-	globalVar := NewVariable("global_x", nil, 0, true)
+	globalVar := NewVariable("global_x", TypeInt, nil, 0, true)
 	globalScope.Declare(globalVar)
 
 	stackFrame := NewStackFrame("main", globalScope)
@@ -907,15 +908,15 @@ func TestStackFrameGetVariableFromParentScope(t *testing.T) {
 
 func TestStackFrameGetVariableShadowing(t *testing.T) {
 	globalScope := NewScope(nil)
-	globalVar := NewVariable("x", nil, 0, true)
-	globalVar.ChangeValue(10, 0)
+	globalVar := NewVariable("x", TypeInt, nil, 0, true)
+	globalVar.ChangeValue(NewIntValue(10), 0)
 	globalScope.Declare(globalVar)
 
 	stackFrame := NewStackFrame("main", globalScope)
 	stackFrame.EnterScope()
 
-	localVar := NewVariable("x", nil, 0, true)
-	localVar.ChangeValue(20, 0)
+	localVar := NewVariable("x", TypeInt, nil, 0, true)
+	localVar.ChangeValue(NewIntValue(20), 0)
 	stackFrame.GetCurrentScope().Declare(localVar)
 
 	// Should find the local variable, not global
@@ -923,12 +924,12 @@ func TestStackFrameGetVariableShadowing(t *testing.T) {
 	assert.True(t, ok)
 	val, err := found.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 20, val)
+	assert.Equal(t, NewIntValue(20), val)
 }
 
 func TestStackFrameGetArrayFromParentScope(t *testing.T) {
 	globalScope := NewScope(nil)
-	arr := NewArray("numbers", 5, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 5, nil, 0, true)
 	globalScope.Declare(arr)
 
 	stackFrame := NewStackFrame("main", globalScope)
@@ -941,7 +942,7 @@ func TestStackFrameGetArrayFromParentScope(t *testing.T) {
 
 func TestStackFrameGetVariableDeepHierarchy(t *testing.T) {
 	globalScope := NewScope(nil)
-	v := NewVariable("x", nil, 0, true)
+	v := NewVariable("x", TypeInt, nil, 0, true)
 	globalScope.Declare(v)
 
 	stackFrame := NewStackFrame("main", globalScope)
@@ -1068,7 +1069,7 @@ func TestCallStackDeclareInCurrentFrame(t *testing.T) {
 	globalScope := NewScope(nil)
 	callStack := NewCallStack(globalScope)
 
-	variable := NewVariable("x", nil, 0, true)
+	variable := NewVariable("x", TypeInt, nil, 0, true)
 	callStack.DeclareInCurrentFrame(variable)
 
 	frame := callStack.GetCurrentFrame()
@@ -1079,7 +1080,7 @@ func TestCallStackDeclareInCurrentFrame(t *testing.T) {
 
 func TestCallStackGetVariableInCurrentFrame(t *testing.T) {
 	globalScope := NewScope(nil)
-	v := NewVariable("x", nil, 0, true)
+	v := NewVariable("x", TypeInt, nil, 0, true)
 	globalScope.Declare(v)
 
 	callStack := NewCallStack(globalScope)
@@ -1091,7 +1092,7 @@ func TestCallStackGetVariableInCurrentFrame(t *testing.T) {
 
 func TestCallStackGetArrayInCurrentFrame(t *testing.T) {
 	globalScope := NewScope(nil)
-	arr := NewArray("numbers", 5, nil, 0, true)
+	arr := NewArray("numbers", TypeInt, 5, nil, 0, true)
 	globalScope.Declare(arr)
 
 	callStack := NewCallStack(globalScope)
@@ -1103,7 +1104,7 @@ func TestCallStackGetArrayInCurrentFrame(t *testing.T) {
 
 func TestCallStackMultipleFramesWithDifferentVariables(t *testing.T) {
 	globalScope := NewScope(nil)
-	globalVar := NewVariable("global", nil, 0, true)
+	globalVar := NewVariable("global", TypeInt, nil, 0, true)
 	globalScope.Declare(globalVar)
 
 	callStack := NewCallStack(globalScope)
@@ -1111,14 +1112,14 @@ func TestCallStackMultipleFramesWithDifferentVariables(t *testing.T) {
 	// First frame
 	func1Frame := NewStackFrame("func1", globalScope)
 	func1Frame.EnterScope()
-	var1 := NewVariable("local1", nil, 0, true)
+	var1 := NewVariable("local1", TypeInt, nil, 0, true)
 	func1Frame.GetCurrentScope().Declare(var1)
 	callStack.PushFrame(func1Frame)
 
 	// Second frame
 	func2Frame := NewStackFrame("func2", globalScope)
 	func2Frame.EnterScope()
-	var2 := NewVariable("local2", nil, 0, true)
+	var2 := NewVariable("local2", TypeInt, nil, 0, true)
 	func2Frame.GetCurrentScope().Declare(var2)
 	callStack.PushFrame(func2Frame)
 
@@ -1142,13 +1143,13 @@ func TestCallStackDeclareInDifferentFrames(t *testing.T) {
 
 	// Declare in main frame's local scope
 	callStack.GetCurrentFrame().EnterScope()
-	mainVar := NewVariable("main_var", nil, 0, true)
+	mainVar := NewVariable("main_var", TypeInt, nil, 0, true)
 	callStack.GetCurrentFrame().GetCurrentScope().Declare(mainVar)
 
 	// Create and push func1 frame
 	func1Frame := NewStackFrame("func1", globalScope)
 	func1Frame.EnterScope()
-	func1Var := NewVariable("func1_var", nil, 0, true)
+	func1Var := NewVariable("func1_var", TypeInt, nil, 0, true)
 	func1Frame.GetCurrentScope().Declare(func1Var)
 	callStack.PushFrame(func1Frame)
 
@@ -1173,3 +1174,121 @@ func TestCallStackDeclareInDifferentFrames(t *testing.T) {
 	_, ok4 := callStack.GetVariableInCurrentFrame("func1_var")
 	assert.False(t, ok4)
 }
+
+// ============ Value Tests ============
+
+func TestValueConvertTo(t *testing.T) {
+	d, err := NewIntValue(7).ConvertTo(TypeDouble)
+	require.NoError(t, err)
+	assert.Equal(t, NewDoubleValue(7), d)
+
+	i, err := NewDoubleValue(-2.9).ConvertTo(TypeInt)
+	require.NoError(t, err)
+	assert.Equal(t, NewIntValue(-2), i)
+
+	f, err := NewDoubleValue(0.1).ConvertTo(TypeFloat)
+	require.NoError(t, err)
+	assert.Equal(t, float64(float32(0.1)), f.Float)
+
+	_, err = NewDoubleValue(1e20).ConvertTo(TypeInt)
+	assert.Error(t, err)
+
+	_, err = VoidValue().ConvertTo(TypeDouble)
+	assert.Error(t, err)
+}
+
+func TestValueCommonType(t *testing.T) {
+	assert.Equal(t, TypeInt, CommonType(TypeInt, TypeInt))
+	assert.Equal(t, TypeFloat, CommonType(TypeInt, TypeFloat))
+	assert.Equal(t, TypeDouble, CommonType(TypeFloat, TypeDouble))
+	assert.Equal(t, TypeDouble, CommonType(TypeDouble, TypeInt))
+}
+
+func TestValueStringAndJSON(t *testing.T) {
+	assert.Equal(t, "2.500000", NewDoubleValue(2.5).String())
+	assert.Equal(t, "3", NewIntValue(3).String())
+
+	data, err := json.Marshal(NewFloatValue(0.1))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"float","value":0.1}`, string(data))
+
+	var restored Value
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, NewFloatValue(0.1), restored)
+
+	data, err = json.Marshal(NewDoubleValue(1.25))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, NewDoubleValue(1.25), restored)
+}
+
+func TestCharValue(t *testing.T) {
+	c := NewCharValue(300)
+	assert.Equal(t, 44, c.Int)
+	assert.Equal(t, "',' (44)", c.String())
+	assert.Equal(t, `\0`, NewCharValue(0).CharString())
+	assert.Equal(t, TypeInt, CommonType(TypeChar, TypeChar))
+
+	data, err := json.Marshal(NewCharValue('a'))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"char","value":97,"char":"a"}`, string(data))
+
+	var restored Value
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, NewCharValue('a'), restored)
+}
+
+func TestPointerValue(t *testing.T) {
+	p, err := NewPointerValue(TypeVoidPtr, 0x18000).ConvertTo("int*")
+	require.NoError(t, err)
+	assert.Equal(t, NewPointerValue("int*", 0x18000), p)
+	assert.Equal(t, "0x18000", p.String())
+	assert.Equal(t, TypeInt, p.Type.Pointee())
+
+	null, err := NewIntValue(0).ConvertTo("double*")
+	require.NoError(t, err)
+	assert.Equal(t, "NULL", null.String())
+	truth, err := null.Truth()
+	require.NoError(t, err)
+	assert.False(t, truth)
+
+	_, err = p.ConvertTo("char*")
+	assert.Error(t, err)
+	_, err = NewIntValue(5).ConvertTo("int*")
+	assert.Error(t, err)
+	_, err = p.ConvertTo(TypeInt)
+	assert.Error(t, err)
+
+	size, err := SizeOf("char*")
+	require.NoError(t, err)
+	assert.Equal(t, 8, size)
+}
+
+func TestHeapFindAndFree(t *testing.T) {
+	heap := NewHeap()
+
+	first := heap.Allocate(heap.NextAddress(), 8, 1, false)
+	second := heap.Allocate(heap.NextAddress(), 4, 2, true)
+	assert.NotEqual(t, first.Address, second.Address)
+
+	// адрес за границей блока относится к тому же блоку
+	block, ok := heap.Find(first.Address + 100)
+	require.True(t, ok)
+	assert.Same(t, first, block)
+	block, ok = heap.Find(second.Address - 4)
+	require.True(t, ok)
+	assert.Same(t, second, block)
+
+	require.NoError(t, second.SetType(TypeInt, 3))
+	require.Len(t, second.Values, 1)
+	assert.Equal(t, NewIntValue(0), *second.Values[0].Value)
+
+	heap.Free(first)
+	assert.True(t, first.Freed)
+	assert.Equal(t, []*HeapBlock{second}, heap.Blocks)
+	_, ok = heap.Find(first.Address)
+	assert.True(t, ok)
+
+	_, ok = heap.Find(0)
+	assert.False(t, ok)
+}
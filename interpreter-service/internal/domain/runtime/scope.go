@@ -1,8 +1,33 @@
 package runtime
 
+import "github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+
+// ScopeKind - конструкция, которой принадлежит область видимости
+type ScopeKind string
+
+const (
+	ScopeGlobal   ScopeKind = "global"
+	ScopeFunction ScopeKind = "function" // параметры функции
+	ScopeBody     ScopeKind = "body"     // тело функции
+	ScopeFor      ScopeKind = "for"      // заголовок цикла for
+	ScopeBlock    ScopeKind = "block"    // составной оператор { ... }
+)
+
+// ScopeInfo - вид области видимости, участок узла AST, которому она принадлежит,
+// и строки, на которых видны ее объявления
+type ScopeInfo struct {
+	Kind      ScopeKind           `json:"kind,omitempty"`
+	Owner     *converter.Location `json:"owner,omitempty"`
+	StartLine int                 `json:"startLine,omitempty"`
+	EndLine   int                 `json:"endLine,omitempty"`
+}
+
 type Scope struct {
+	ScopeInfo
 	Parent       *Scope           `json:"-"`
 	Declarations DeclarationStack `json:"declarations"`
+	enums        map[string]*Enum
+	constants    map[string]int
 }
 
 func NewScope(parent *Scope) *Scope {
@@ -11,6 +36,11 @@ func NewScope(parent *Scope) *Scope {
 	}
 }
 
+// NewGlobalScope создает глобальную область видимости программы
+func NewGlobalScope() *Scope {
+	return &Scope{ScopeInfo: ScopeInfo{Kind: ScopeGlobal}}
+}
+
 func (sc *Scope) Declare(decl Declared) {
 	sc.Declarations.Declare(decl)
 }
@@ -26,3 +56,47 @@ func (sc *Scope) GetArray(name string) (*Array, bool) {
 func (sc *Scope) GetArray2D(name string) (*Array2D, bool) {
 	return sc.Declarations.GetArray2D(name)
 }
+
+func (sc *Scope) GetByID(id int) (Declared, bool) {
+	return sc.Declarations.GetByID(id)
+}
+
+// DeclareEnum регистрирует перечисление (если у него есть имя) и его константы в области видимости
+func (sc *Scope) DeclareEnum(e *Enum) {
+	if sc.enums == nil {
+		sc.enums = make(map[string]*Enum)
+		sc.constants = make(map[string]int)
+	}
+	if e.Name != "" {
+		sc.enums[e.Name] = e
+	}
+	for _, en := range e.Enumerators {
+		sc.constants[en.Name] = en.Value
+	}
+}
+
+func (sc *Scope) GetEnum(name string) (*Enum, bool) {
+	e, ok := sc.enums[name]
+	return e, ok
+}
+
+func (sc *Scope) GetEnumConstant(name string) (int, bool) {
+	v, ok := sc.constants[name]
+	return v, ok
+}
+
+// Clone возвращает копию области видимости с родителем parent
+func (sc *Scope) Clone(parent *Scope) *Scope {
+	clone := &Scope{ScopeInfo: sc.ScopeInfo, Parent: parent, Declarations: sc.Declarations.Clone()}
+	if sc.enums != nil {
+		clone.enums = make(map[string]*Enum, len(sc.enums))
+		for name, e := range sc.enums {
+			clone.enums[name] = e
+		}
+		clone.constants = make(map[string]int, len(sc.constants))
+		for name, value := range sc.constants {
+			clone.constants[name] = value
+		}
+	}
+	return clone
+}
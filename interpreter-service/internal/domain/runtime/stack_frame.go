@@ -1,11 +1,21 @@
 package runtime
 
-import runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+import (
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+// EvaluatedExpr - вычисленное подвыражение: его положение в исходном коде и значение
+type EvaluatedExpr struct {
+	Loc   converter.Location `json:"loc"`
+	Value Value              `json:"value"`
+}
 
 type StackFrame struct {
-	FuncName    string   `json:"func_name"`
-	Scopes      []*Scope `json:"scopes"`
-	ReturnValue *int     `json:"return_value,omitempty"`
+	FuncName    string          `json:"func_name"`
+	Scopes      []*Scope        `json:"scopes"`
+	ReturnValue *Value          `json:"return_value,omitempty"`
+	Evaluated   []EvaluatedExpr `json:"evaluated,omitempty"` // частично вычисленное выражение текущей инструкции
 }
 
 func NewStackFrame(funcName string, globalScope *Scope) *StackFrame {
@@ -14,9 +24,11 @@ func NewStackFrame(funcName string, globalScope *Scope) *StackFrame {
 	return stackFrame
 }
 
-func (sf *StackFrame) EnterScope() {
+// EnterScope открывает вложенную область видимости и возвращает ее
+func (sf *StackFrame) EnterScope() *Scope {
 	scope := NewScope(sf.Scopes[len(sf.Scopes)-1])
 	sf.Scopes = append(sf.Scopes, scope)
+	return scope
 }
 
 func (sf *StackFrame) ExitScope() error {
@@ -27,6 +39,11 @@ func (sf *StackFrame) ExitScope() error {
 	return nil
 }
 
+// RestoreScope возвращает закрытую область видимости на вершину кадра
+func (sf *StackFrame) RestoreScope(scope *Scope) {
+	sf.Scopes = append(sf.Scopes, scope)
+}
+
 func (sf *StackFrame) GetCurrentScope() *Scope {
 	if len(sf.Scopes) > 0 {
 		return sf.Scopes[len(sf.Scopes)-1]
@@ -38,14 +55,55 @@ func (sf *StackFrame) Declare(decl Declared) {
 	sf.GetCurrentScope().Declare(decl)
 }
 
-func (sf *StackFrame) SetReturnValue(val int) {
+// AddEvaluated добавляет значение вычисленного подвыражения текущей инструкции.
+// Подвыражения вычисляются слева направо, и каждое следует за своими операндами, поэтому
+// ранее вычисленное подвыражение, которое не входит в новое и не предшествует ему,
+// относится к предыдущему вычислению (например, к прошлой итерации цикла на той же строке)
+func (sf *StackFrame) AddEvaluated(loc converter.Location, value Value) {
+	for _, e := range sf.Evaluated {
+		if !contains(loc, e.Loc) && !before(e.Loc, loc) {
+			sf.Evaluated = nil
+			break
+		}
+	}
+	sf.Evaluated = append(sf.Evaluated, EvaluatedExpr{Loc: loc, Value: value})
+}
+
+// contains сообщает, входит ли участок inner в участок outer
+func contains(outer, inner converter.Location) bool {
+	return !positionLess(inner.Line, inner.Column, outer.Line, outer.Column) &&
+		!positionLess(outer.EndLine, outer.EndColumn, inner.EndLine, inner.EndColumn)
+}
+
+// before сообщает, заканчивается ли участок a до начала участка b
+func before(a, b converter.Location) bool {
+	return !positionLess(b.Line, b.Column, a.EndLine, a.EndColumn)
+}
+
+func positionLess(line1, column1, line2, column2 uint32) bool {
+	return line1 < line2 || line1 == line2 && column1 < column2
+}
+
+// ClearEvaluated сбрасывает вычисленные подвыражения при переходе к строке line.
+// Строка, которую уже занимает частично вычисленное выражение, не сбрасывает его:
+// так выражение сохраняется при возврате из вызванной в нем функции
+func (sf *StackFrame) ClearEvaluated(line int) {
+	for _, e := range sf.Evaluated {
+		if int(e.Loc.Line) <= line && line <= int(e.Loc.EndLine) {
+			return
+		}
+	}
+	sf.Evaluated = nil
+}
+
+func (sf *StackFrame) SetReturnValue(val Value) {
 	if sf.ReturnValue == nil {
-		sf.ReturnValue = new(int)
+		sf.ReturnValue = new(Value)
 	}
 	*sf.ReturnValue = val
 }
 
-func (sf *StackFrame) GetReturnValue() (*int, error) {
+func (sf *StackFrame) GetReturnValue() (*Value, error) {
 	if sf.ReturnValue == nil {
 		return nil, runtimeerrors.NewErrUnexpectedInternalError("return value not set")
 	}
@@ -74,6 +132,28 @@ func (sf *StackFrame) GetArray(name string) (*Array, bool) {
 	return nil, false
 }
 
+func (sf *StackFrame) GetEnum(name string) (*Enum, bool) {
+	current := sf.GetCurrentScope()
+	for current != nil {
+		if e, ok := current.GetEnum(name); ok {
+			return e, true
+		}
+		current = current.Parent
+	}
+	return nil, false
+}
+
+func (sf *StackFrame) GetEnumConstant(name string) (int, bool) {
+	current := sf.GetCurrentScope()
+	for current != nil {
+		if v, ok := current.GetEnumConstant(name); ok {
+			return v, true
+		}
+		current = current.Parent
+	}
+	return 0, false
+}
+
 func (sf *StackFrame) GetArray2D(name string) (*Array2D, bool) {
 	current := sf.GetCurrentScope()
 	for current != nil {
@@ -84,3 +164,32 @@ func (sf *StackFrame) GetArray2D(name string) (*Array2D, bool) {
 	}
 	return nil, false
 }
+
+// GetByID ищет объявление с идентификатором id в областях видимости кадра, начиная с текущей
+func (sf *StackFrame) GetByID(id int) (Declared, bool) {
+	current := sf.GetCurrentScope()
+	for current != nil {
+		if d, ok := current.GetByID(id); ok {
+			return d, true
+		}
+		current = current.Parent
+	}
+	return nil, false
+}
+
+// Clone возвращает копию кадра стека. Первая область видимости кадра - глобальная,
+// она заменяется на globalScope, остальные копируются с сохранением вложенности
+func (sf *StackFrame) Clone(globalScope *Scope) *StackFrame {
+	clone := &StackFrame{FuncName: sf.FuncName, Scopes: make([]*Scope, len(sf.Scopes)), ReturnValue: CloneValue(sf.ReturnValue)}
+	for ind, scope := range sf.Scopes {
+		if ind == 0 {
+			clone.Scopes[ind] = globalScope
+			continue
+		}
+		clone.Scopes[ind] = scope.Clone(clone.Scopes[ind-1])
+	}
+	if sf.Evaluated != nil {
+		clone.Evaluated = append([]EvaluatedExpr(nil), sf.Evaluated...)
+	}
+	return clone
+}
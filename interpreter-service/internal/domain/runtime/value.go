@@ -0,0 +1,351 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
+)
+
+// Type описывает C-тип значения времени выполнения
+type Type string
+
+const (
+	TypeInt    Type = "int"
+	TypeChar   Type = "char"
+	TypeFloat  Type = "float"
+	TypeDouble Type = "double"
+	TypeVoid   Type = "void"
+
+	// TypeVoidPtr - тип результата malloc/calloc/realloc и NULL
+	TypeVoidPtr Type = "void*"
+)
+
+// IsPointer сообщает, является ли тип указателем
+func (t Type) IsPointer() bool {
+	return strings.HasSuffix(string(t), "*")
+}
+
+// Pointee возвращает тип, на который указывает указатель
+func (t Type) Pointee() Type {
+	return Type(strings.TrimSuffix(string(t), "*"))
+}
+
+// PointerTo возвращает тип указателя на t
+func PointerTo(t Type) Type {
+	return t + "*"
+}
+
+// SizeOf возвращает размер типа в байтах, как на типичной 64-битной платформе
+func SizeOf(t Type) (int, error) {
+	if t.IsPointer() {
+		return 8, nil
+	}
+	switch t {
+	case TypeChar:
+		return 1, nil
+	case TypeInt, TypeFloat:
+		return 4, nil
+	case TypeDouble:
+		return 8, nil
+	default:
+		return 0, runtimeerrors.NewErrRuntime(fmt.Sprintf("invalid application of sizeof to type %s", t))
+	}
+}
+
+// IsFloating сообщает, является ли тип вещественным
+func (t Type) IsFloating() bool {
+	return t == TypeFloat || t == TypeDouble
+}
+
+// rank - ранг типа для обычных арифметических преобразований
+func (t Type) rank() int {
+	switch t {
+	case TypeDouble:
+		return 2
+	case TypeFloat:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsInteger сообщает, является ли тип целочисленным (char хранится как код символа)
+func (t Type) IsInteger() bool {
+	return t == TypeInt || t == TypeChar
+}
+
+// CommonType возвращает тип результата бинарной операции над операндами типов a и b
+// (обычные арифметические преобразования C; char продвигается до int)
+func CommonType(a, b Type) Type {
+	if !a.IsFloating() && !b.IsFloating() {
+		return TypeInt
+	}
+	if a.rank() >= b.rank() {
+		return a
+	}
+	return b
+}
+
+// Value представляет типизированное значение: C-тип и его представление.
+// Целые и char хранятся в Int (char - знаковый 8-битный код),
+// вещественные - в Float (float округляется до одинарной точности)
+type Value struct {
+	Type  Type
+	Int   int
+	Float float64
+}
+
+type valueDTO struct {
+	Type  Type            `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Char  string          `json:"char,omitempty"` // отображение символа для char
+}
+
+func NewIntValue(v int) Value {
+	return Value{Type: TypeInt, Int: v}
+}
+
+// NewCharValue создает значение char, усекая код до знакового байта
+func NewCharValue(v int) Value {
+	return Value{Type: TypeChar, Int: int(int8(v))}
+}
+
+func NewFloatValue(v float64) Value {
+	return Value{Type: TypeFloat, Float: float64(float32(v))}
+}
+
+func NewDoubleValue(v float64) Value {
+	return Value{Type: TypeDouble, Float: v}
+}
+
+// NewPointerValue создает указатель типа t на адрес address (0 - NULL)
+func NewPointerValue(t Type, address int) Value {
+	return Value{Type: t, Int: address}
+}
+
+// NullValue возвращает NULL - нулевой указатель типа void*
+func NullValue() Value {
+	return NewPointerValue(TypeVoidPtr, 0)
+}
+
+func VoidValue() Value {
+	return Value{Type: TypeVoid}
+}
+
+// ZeroValue возвращает значение по умолчанию для типа (инициализация глобальных переменных)
+func ZeroValue(t Type) Value {
+	return Value{Type: t}
+}
+
+func (v Value) IsVoid() bool {
+	return v.Type == TypeVoid
+}
+
+// AsInt возвращает целочисленное представление значения
+func (v Value) AsInt() (int, error) {
+	switch v.Type {
+	case TypeInt, TypeChar:
+		return v.Int, nil
+	case TypeVoid:
+		return 0, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
+	default:
+		return 0, runtimeerrors.NewErrRuntime(fmt.Sprintf("value of type %s is not an integer", v.Type))
+	}
+}
+
+// AsFloat возвращает вещественное представление числового значения
+func (v Value) AsFloat() (float64, error) {
+	switch v.Type {
+	case TypeInt, TypeChar:
+		return float64(v.Int), nil
+	case TypeFloat, TypeDouble:
+		return v.Float, nil
+	case TypeVoid:
+		return 0, runtimeerrors.NewErrRuntime("void value not ignored as it ought to be")
+	default:
+		return 0, runtimeerrors.NewErrRuntime(fmt.Sprintf("value of type %s is not a number", v.Type))
+	}
+}
+
+// ConvertTo выполняет неявное преобразование значения к типу t, как при присваивании в C.
+// Вещественное значение приводится к int отбрасыванием дробной части
+func (v Value) ConvertTo(t Type) (Value, error) {
+	if v.Type == t {
+		return v, nil
+	}
+	if t.IsPointer() || v.Type.IsPointer() {
+		return v.convertPointer(t)
+	}
+	switch t {
+	case TypeInt:
+		f, err := v.AsFloat()
+		if err != nil {
+			return Value{}, err
+		}
+		if math.IsNaN(f) || f >= math.MaxInt32+1 || f <= math.MinInt32-1 {
+			return Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("value %s is out of range of int", v))
+		}
+		return NewIntValue(int(f)), nil
+	case TypeChar:
+		i, err := v.ConvertTo(TypeInt)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewCharValue(i.Int), nil
+	case TypeFloat:
+		f, err := v.AsFloat()
+		if err != nil {
+			return Value{}, err
+		}
+		if math.IsNaN(f) || math.IsInf(float64(float32(f)), 0) {
+			return Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("value %g is out of range of float", f))
+		}
+		return NewFloatValue(f), nil
+	case TypeDouble:
+		f, err := v.AsFloat()
+		if err != nil {
+			return Value{}, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("value %g is out of range of double", f))
+		}
+		return NewDoubleValue(f), nil
+	default:
+		return Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("cannot convert %s to %s", v.Type, t))
+	}
+}
+
+// convertPointer преобразует указатель к другому типу указателя или целый 0 к NULL.
+// Допустимы только преобразования через void*: T* -> void* и void* -> T*
+func (v Value) convertPointer(t Type) (Value, error) {
+	if !t.IsPointer() {
+		return Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("cannot convert pointer %s to %s", v.Type, t))
+	}
+	if v.Type.IsPointer() {
+		if v.Type != TypeVoidPtr && t != TypeVoidPtr {
+			return Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("incompatible pointer types: cannot convert %s to %s", v.Type, t))
+		}
+		return NewPointerValue(t, v.Int), nil
+	}
+	if v.Type.IsInteger() && v.Int == 0 {
+		return NewPointerValue(t, 0), nil
+	}
+	return Value{}, runtimeerrors.NewErrRuntime(fmt.Sprintf("cannot convert %s to pointer %s", v.Type, t))
+}
+
+// Truth возвращает истинность значения в условиях (ненулевое значение - истина)
+func (v Value) Truth() (bool, error) {
+	if v.Type.IsPointer() {
+		return v.Int != 0, nil
+	}
+	if v.Type.IsFloating() {
+		return v.Float != 0, nil
+	}
+	val, err := v.AsInt()
+	if err != nil {
+		return false, err
+	}
+	return val != 0, nil
+}
+
+func (v Value) String() string {
+	switch v.Type {
+	case TypeInt:
+		return strconv.Itoa(v.Int)
+	case TypeChar:
+		return fmt.Sprintf("'%s' (%d)", v.CharString(), v.Int)
+	case TypeFloat, TypeDouble:
+		// как printf("%f")
+		return strconv.FormatFloat(v.Float, 'f', 6, 64)
+	case TypeVoid:
+		return "void"
+	default:
+		if v.Type.IsPointer() {
+			return FormatAddress(v.Int)
+		}
+		return fmt.Sprintf("<%s>", v.Type)
+	}
+}
+
+// CharString возвращает отображение символа: печатный символ как есть,
+// управляющие - escape-последовательностью
+func (v Value) CharString() string {
+	switch byte(v.Int) {
+	case 0:
+		return `\0`
+	case '\n':
+		return `\n`
+	case '\t':
+		return `\t`
+	case '\r':
+		return `\r`
+	case '\\':
+		return `\\`
+	case '\'':
+		return `\'`
+	}
+	if v.Int >= 32 && v.Int < 127 {
+		return string(rune(v.Int))
+	}
+	return fmt.Sprintf(`\x%02x`, byte(v.Int))
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	dto := valueDTO{Type: v.Type}
+	var payload interface{}
+	switch v.Type {
+	case TypeVoid:
+		return json.Marshal(dto)
+	case TypeFloat:
+		payload = float32(v.Float)
+	case TypeDouble:
+		payload = v.Float
+	case TypeChar:
+		payload = v.Int
+		dto.Char = v.CharString()
+	default:
+		payload = v.Int
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	dto.Value = raw
+	return json.Marshal(dto)
+}
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var dto valueDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return err
+	}
+	v.Type = dto.Type
+	v.Int = 0
+	v.Float = 0
+	if dto.Type == TypeVoid || len(dto.Value) == 0 {
+		return nil
+	}
+	if dto.Type.IsFloating() {
+		if err := json.Unmarshal(dto.Value, &v.Float); err != nil {
+			return err
+		}
+		if dto.Type == TypeFloat {
+			v.Float = float64(float32(v.Float))
+		}
+		return nil
+	}
+	return json.Unmarshal(dto.Value, &v.Int)
+}
+
+// CloneValue создает копию значения по указателю
+func CloneValue(src *Value) *Value {
+	if src == nil {
+		return nil
+	}
+	val := *src
+	return &val
+}
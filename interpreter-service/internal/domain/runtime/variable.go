@@ -1,42 +1,72 @@
 package runtime
 
 import (
+	"encoding/json"
 	"fmt"
 
 	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
 )
 
 type Variable struct {
+	ID          int    `json:"id,omitempty"` // идентификатор экземпляра объявления, общий для всех шагов
 	Name        string `json:"name"`
-	Value       *int   `json:"value,omitempty"`
+	Type        Type   `json:"type"`
+	Value       *Value `json:"value,omitempty"`
 	StepChanged int    `json:"step_changed"` //для подстветки на фронте
+	Enum        *Enum  `json:"-"`            // перечисление, если переменная объявлена как enum
 }
 
-func NewVariable(name string, value *int, step int, isGlobal bool) *Variable {
-	var v *int
+func NewVariable(name string, valueType Type, value *Value, step int, isGlobal bool) *Variable {
+	var v *Value
 	if value != nil {
 		cv := *value
 		v = &cv
 	} else if isGlobal {
-		val := 0
+		val := ZeroValue(valueType)
 		v = &val
 	}
-	return &Variable{Name: name, Value: v, StepChanged: step}
+	return &Variable{Name: name, Type: valueType, Value: v, StepChanged: step}
 }
 
-func (v *Variable) ChangeValue(value int, step int) int {
+// MarshalJSON дополняет enum-переменную именем перечисления и именем константы с текущим значением
+func (v Variable) MarshalJSON() ([]byte, error) {
+	type plainVariable Variable
+	out := struct {
+		plainVariable
+		EnumName   string `json:"enum,omitempty"`
+		Enumerator string `json:"enumerator,omitempty"`
+	}{plainVariable: plainVariable(v)}
+
+	if v.Enum != nil {
+		out.EnumName = v.Enum.Name
+		if v.Value != nil {
+			out.Enumerator, _ = v.Enum.EnumeratorName(v.Value.Int)
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+func (v *Variable) ChangeValue(value Value, step int) Value {
 	if v.Value == nil {
-		v.Value = new(int)
+		v.Value = new(Value)
 	}
 	*v.Value = value
 	v.StepChanged = step
 	return value
 }
 
-func (v *Variable) GetValue() (int, error) {
+func (v *Variable) GetValue() (Value, error) {
 	if v.Value == nil {
-		return 0, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("getting an uninitialized variable %s", v.Name))
+		return Value{}, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("getting an uninitialized variable %s", v.Name))
 	} else {
 		return *v.Value, nil
 	}
 }
+
+// Clone возвращает независимую копию переменной (перечисление неизменяемо и не копируется)
+func (v *Variable) Clone() *Variable {
+	clone := *v
+	clone.Value = CloneValue(v.Value)
+	return &clone
+}
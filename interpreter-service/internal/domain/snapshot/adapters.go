@@ -0,0 +1,206 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+)
+
+// SnapshotDTO - сериализуемое представление снимка, из которого он восстанавливается полностью
+// (в отличие от JSON-ответа, в котором теряются типы объявлений, перечисления и освобожденные блоки кучи)
+type SnapshotDTO struct {
+	Global       ScopeDTO            `json:"global"`
+	Frames       []FrameDTO          `json:"frames"`
+	Line         int                 `json:"line"`
+	Range        *converter.Location `json:"range,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	FunctionName string              `json:"functionName,omitempty"`
+	ReturnValue  *runtime.Value      `json:"returnValue,omitempty"`
+	HeapBlocks   []HeapBlockDTO      `json:"heapBlocks,omitempty"` // все блоки, включая освобожденные
+	Leaks        []int               `json:"leaks,omitempty"`      // адреса неосвобожденных блоков
+	Accessed     []Access            `json:"accessed,omitempty"`
+	IndexMarkers []IndexMarker       `json:"indexMarkers,omitempty"`
+	Destroyed    []Destroyed         `json:"destroyed,omitempty"`
+}
+
+// FrameDTO - кадр стека без глобальной области видимости, с которой начинается каждый кадр
+type FrameDTO struct {
+	FuncName    string                  `json:"funcName"`
+	Scopes      []ScopeDTO              `json:"scopes"`
+	ReturnValue *runtime.Value          `json:"returnValue,omitempty"`
+	Evaluated   []runtime.EvaluatedExpr `json:"evaluated,omitempty"`
+}
+
+type ScopeDTO struct {
+	runtime.ScopeInfo
+	Declarations []DeclaredDTO `json:"declarations"`
+}
+
+// DeclaredDTO - объявление в области видимости, задано ровно одно поле
+type DeclaredDTO struct {
+	Variable *VariableDTO     `json:"variable,omitempty"`
+	Array    *runtime.Array   `json:"array,omitempty"`
+	Array2D  *runtime.Array2D `json:"array2d,omitempty"`
+}
+
+type VariableDTO struct {
+	ID          int            `json:"id,omitempty"`
+	Name        string         `json:"name"`
+	Type        runtime.Type   `json:"type"`
+	Value       *runtime.Value `json:"value,omitempty"`
+	StepChanged int            `json:"stepChanged"`
+	Enum        *runtime.Enum  `json:"enum,omitempty"`
+}
+
+type HeapBlockDTO struct {
+	Block *runtime.HeapBlock `json:"block"`
+	Freed bool               `json:"freed,omitempty"`
+}
+
+type CheckpointDTO struct {
+	Step     int         `json:"step"`
+	Snapshot SnapshotDTO `json:"snapshot"`
+}
+
+func MarshalSnapshot(sn *Snapshot) (SnapshotDTO, error) {
+	global, err := marshalScope(sn.GlobalScope)
+	if err != nil {
+		return SnapshotDTO{}, err
+	}
+
+	dto := SnapshotDTO{
+		Global:       global,
+		Frames:       make([]FrameDTO, len(sn.CallStack.Frames)),
+		Line:         sn.Line,
+		Range:        sn.Range,
+		Error:        sn.Error,
+		FunctionName: sn.FunctionName,
+		ReturnValue:  sn.ReturnValue,
+		Accessed:     sn.Accessed,
+		IndexMarkers: sn.IndexMarkers,
+		Destroyed:    sn.Destroyed,
+	}
+	for ind, frame := range sn.CallStack.Frames {
+		frameDTO := FrameDTO{FuncName: frame.FuncName, ReturnValue: frame.ReturnValue, Evaluated: frame.Evaluated}
+		for _, scope := range frame.Scopes[1:] {
+			scopeDTO, err := marshalScope(scope)
+			if err != nil {
+				return SnapshotDTO{}, err
+			}
+			frameDTO.Scopes = append(frameDTO.Scopes, scopeDTO)
+		}
+		dto.Frames[ind] = frameDTO
+	}
+	for _, block := range sn.Heap.AllBlocks() {
+		dto.HeapBlocks = append(dto.HeapBlocks, HeapBlockDTO{Block: block, Freed: block.Freed})
+	}
+	for _, leak := range sn.Leaks {
+		dto.Leaks = append(dto.Leaks, leak.Address)
+	}
+
+	return dto, nil
+}
+
+func UnmarshalSnapshot(dto SnapshotDTO) (*Snapshot, error) {
+	globalScope, err := unmarshalScope(dto.Global, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	callStack := &runtime.CallStack{Frames: make([]*runtime.StackFrame, len(dto.Frames))}
+	for ind, frameDTO := range dto.Frames {
+		frame := runtime.NewStackFrame(frameDTO.FuncName, globalScope)
+		frame.ReturnValue, frame.Evaluated = frameDTO.ReturnValue, frameDTO.Evaluated
+		for _, scopeDTO := range frameDTO.Scopes {
+			scope, err := unmarshalScope(scopeDTO, frame.GetCurrentScope())
+			if err != nil {
+				return nil, err
+			}
+			frame.Scopes = append(frame.Scopes, scope)
+		}
+		callStack.Frames[ind] = frame
+	}
+
+	blocks := make([]*runtime.HeapBlock, len(dto.HeapBlocks))
+	for ind, blockDTO := range dto.HeapBlocks {
+		blocks[ind] = blockDTO.Block
+		blocks[ind].Freed = blockDTO.Freed
+	}
+
+	sn := &Snapshot{
+		CallStack:    callStack,
+		GlobalScope:  globalScope,
+		Line:         dto.Line,
+		Range:        dto.Range,
+		Error:        dto.Error,
+		FunctionName: dto.FunctionName,
+		ReturnValue:  dto.ReturnValue,
+		Heap:         runtime.RestoreHeap(blocks),
+		Accessed:     dto.Accessed,
+		IndexMarkers: dto.IndexMarkers,
+		Destroyed:    dto.Destroyed,
+	}
+	for _, address := range dto.Leaks {
+		block, err := sn.findHeapBlock(address)
+		if err != nil {
+			return nil, err
+		}
+		sn.Leaks = append(sn.Leaks, block)
+	}
+
+	return sn, nil
+}
+
+func MarshalCheckpoint(c Checkpoint) (CheckpointDTO, error) {
+	dto, err := MarshalSnapshot(c.Snapshot)
+	if err != nil {
+		return CheckpointDTO{}, err
+	}
+	return CheckpointDTO{Step: c.Step, Snapshot: dto}, nil
+}
+
+func UnmarshalCheckpoint(dto CheckpointDTO) (Checkpoint, error) {
+	sn, err := UnmarshalSnapshot(dto.Snapshot)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	return Checkpoint{Step: dto.Step, Snapshot: sn}, nil
+}
+
+func marshalScope(scope *runtime.Scope) (ScopeDTO, error) {
+	dto := ScopeDTO{ScopeInfo: scope.ScopeInfo, Declarations: make([]DeclaredDTO, len(scope.Declarations.Declarations))}
+	for ind, d := range scope.Declarations.Declarations {
+		switch v := d.(type) {
+		case *runtime.Variable:
+			dto.Declarations[ind] = DeclaredDTO{Variable: &VariableDTO{ID: v.ID, Name: v.Name, Type: v.Type, Value: v.Value, StepChanged: v.StepChanged, Enum: v.Enum}}
+		case *runtime.Array:
+			dto.Declarations[ind] = DeclaredDTO{Array: v}
+		case *runtime.Array2D:
+			dto.Declarations[ind] = DeclaredDTO{Array2D: v}
+		default:
+			return ScopeDTO{}, fmt.Errorf("unknown declaration type: %T", d)
+		}
+	}
+	return dto, nil
+}
+
+func unmarshalScope(dto ScopeDTO, parent *runtime.Scope) (*runtime.Scope, error) {
+	scope := runtime.NewScope(parent)
+	scope.ScopeInfo = dto.ScopeInfo
+	for _, d := range dto.Declarations {
+		switch {
+		case d.Variable != nil:
+			v := d.Variable
+			scope.Declare(&runtime.Variable{ID: v.ID, Name: v.Name, Type: v.Type, Value: v.Value, StepChanged: v.StepChanged, Enum: v.Enum})
+		case d.Array != nil:
+			scope.Declare(d.Array)
+		case d.Array2D != nil:
+			scope.Declare(d.Array2D)
+		default:
+			return nil, errors.New("empty declaration")
+		}
+	}
+	return scope, nil
+}
@@ -2,65 +2,178 @@ package snapshot
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	runtimeerrors "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime/errors"
 )
 
 type Snapshot struct {
-	CallStack    *runtime.CallStack `json:"call_stack"`
-	GlobalScope  *runtime.Scope     `json:"global_scope"`
-	Line         int                `json:"line"`
-	Error        string             `json:"error"`
-	FunctionName string             `json:"function_name"`
-	ReturnValue  *int               `json:"return_value"`
+	CallStack    *runtime.CallStack   `json:"call_stack"`
+	GlobalScope  *runtime.Scope       `json:"global_scope"`
+	Line         int                  `json:"line"`
+	Range        *converter.Location  `json:"range,omitempty"` // участок кода активной конструкции
+	Error        string               `json:"error"`
+	FunctionName string               `json:"function_name"`
+	ReturnValue  *runtime.Value       `json:"return_value"`
+	Heap         *runtime.Heap        `json:"heap"`
+	Leaks        []*runtime.HeapBlock `json:"leaks,omitempty"`         // блоки, не освобожденные к завершению main
+	Accessed     []Access             `json:"accessed,omitempty"`      // чтения на текущем шаге (режим событий чтения)
+	IndexMarkers []IndexMarker        `json:"index_markers,omitempty"` // переменные в индексах обращений текущего шага
+	Destroyed    []Destroyed          `json:"destroyed,omitempty"`     // переменные, вышедшие из области видимости на текущем шаге
+}
+
+// Destroyed - переменная или массив кадра Frame, вышедшие из области видимости
+type Destroyed struct {
+	Frame int    `json:"frame"`
+	ID    int    `json:"id,omitempty"`
+	Name  string `json:"name"`
+}
+
+// IndexMarker - переменная Variable кадра Frame указывает на элемент Index массива Name
+// (или блока кучи Address) по измерению Dimension; Offset - разность Index и значения переменной
+type IndexMarker struct {
+	Frame     int    `json:"frame"`
+	Variable  string `json:"variable"`
+	Name      string `json:"name,omitempty"`
+	Address   int    `json:"address,omitempty"`
+	Dimension int    `json:"dimension"`
+	Index     int    `json:"index"`
+	Offset    int    `json:"offset"`
+}
+
+// Access - чтение переменной, элемента массива (Index) или элемента блока кучи (Address, Index)
+// в кадре Frame и операция, которой передано значение
+type Access struct {
+	Frame   int            `json:"frame"`
+	ID      int            `json:"id,omitempty"` // идентификатор объявления переменной или массива
+	Name    string         `json:"name,omitempty"`
+	Address int            `json:"address,omitempty"`
+	Index   []int          `json:"index,omitempty"`
+	Use     events.ReadUse `json:"use"`
 }
 
 func NewSnapshot() *Snapshot {
-	globalScope := runtime.NewScope(nil)
+	globalScope := runtime.NewGlobalScope()
 	return &Snapshot{
 		CallStack:   runtime.NewCallStack(globalScope),
 		GlobalScope: globalScope,
 		Line:        0,
+		Heap:        runtime.NewHeap(),
 	}
 }
 
 func (sn *Snapshot) NewStep() {
 	sn.FunctionName = ""
 	sn.ReturnValue = nil
+	sn.Accessed = nil
+	sn.IndexMarkers = nil
+	sn.Destroyed = nil
 }
 
+// Apply применяет событие к снимку
 func (sn *Snapshot) Apply(event events.Event, step int) error {
+	_, err := sn.Record(event, step)
+	return err
+}
+
+// Record применяет событие к снимку и возвращает его с заполненным состоянием, которое событие перезаписало.
+// Возвращенное событие отменяется через Unapply
+func (sn *Snapshot) Record(event events.Event, step int) (events.Event, error) {
 	switch e := event.(type) {
 	case events.EnterScope:
-		return sn.applyEnterScope()
+		return e, sn.applyEnterScope(e)
 	case events.ExitScope:
-		return sn.applyExitScope()
+		err := sn.applyExitScope(&e)
+		return e, err
+	case events.VariableDestroyed:
+		sn.Destroyed = append(sn.Destroyed, Destroyed{Frame: sn.CallStack.FramesCount() - 1, ID: e.ID, Name: e.Name})
+		return e, nil
 	case events.DeclareVar:
-		return sn.applyDeclareVar(e, step)
+		return e, sn.applyDeclareVar(e, step)
 	case events.DeclareArray:
-		return sn.applyDeclareArray(e, step)
+		return e, sn.applyDeclareArray(e, step)
 	case events.DeclareArray2D:
-		return sn.applyDeclareArray2D(e, step)
+		return e, sn.applyDeclareArray2D(e, step)
 	case events.VarChanged:
-		return sn.applyVarChanged(e, step)
+		err := sn.applyVarChanged(&e, step)
+		return e, err
 	case events.ArrayElementChanged:
-		return sn.applyArrayElementChanged(e, step)
+		err := sn.applyArrayElementChanged(&e, step)
+		return e, err
 	case events.Array2DElementChanged:
-		return sn.applyArray2DElementChanged(e, step)
+		err := sn.applyArray2DElementChanged(&e, step)
+		return e, err
+	case events.ImplicitConversion:
+		// преобразование только отображается, состояние меняет следующее событие изменения
+		return e, nil
+	case events.BuiltinCall:
+		// встроенная функция не создает кадр стека, ее действия описываются собственными событиями
+		return e, nil
+	case events.AssertionFailed:
+		e.PrevError = sn.Error
+		return e, sn.applyAssertionFailed(e)
+	case events.InfiniteLoop:
+		e.PrevError = sn.Error
+		sn.Error = e.Message
+		return e, nil
 	case events.FunctionCall:
-		return sn.applyFunctionCall(e)
+		return e, sn.applyFunctionCall(e)
 	case events.FunctionReturn:
-		return sn.applyFunctionReturn(e)
+		err := sn.applyFunctionReturn(&e)
+		return e, err
+	case events.HeapAlloc:
+		return e, sn.applyHeapAlloc(e, step)
+	case events.HeapBlockTyped:
+		err := sn.applyHeapBlockTyped(&e, step)
+		return e, err
+	case events.HeapFree:
+		return e, sn.applyHeapFree(e)
+	case events.HeapElementChanged:
+		err := sn.applyHeapElementChanged(&e, step)
+		return e, err
+	case events.HeapLeak:
+		return e, sn.applyHeapLeak(e)
 	case events.LineChanged:
-		return sn.applyLineChanged(e)
+		err := sn.applyLineChanged(&e)
+		return e, err
+	case events.RangeChanged:
+		e.PrevRange = sn.Range
+		return e, sn.applyRangeChanged(e)
+	case events.ExprEvaluated:
+		err := sn.applyExprEvaluated(&e)
+		return e, err
+	case events.VarRead:
+		sn.addAccess(Access{ID: e.ID, Name: e.Name, Use: e.Use})
+		return e, nil
+	case events.ArrayElementRead:
+		sn.addAccess(Access{ID: e.ID, Name: e.Name, Index: []int{e.Ind}, Use: e.Use})
+		return e, nil
+	case events.Array2DElementRead:
+		sn.addAccess(Access{ID: e.ID, Name: e.Name, Index: []int{e.Ind1, e.Ind2}, Use: e.Use})
+		return e, nil
+	case events.HeapElementRead:
+		sn.addAccess(Access{Address: e.Address, Index: []int{e.Ind}, Use: e.Use})
+		return e, nil
+	case events.ArrayIndexed:
+		sn.IndexMarkers = append(sn.IndexMarkers, IndexMarker{
+			Frame: sn.CallStack.FramesCount() - 1, Variable: e.Variable, Name: e.Name, Address: e.Address,
+			Dimension: e.Dimension, Index: e.Index, Offset: e.Offset,
+		})
+		return e, nil
+	case events.BranchEvaluated:
+		// исход условия не меняет состояние программы
+		return e, nil
 	case events.UndefinedBehavior:
-		return sn.applyUndefinedBehavior(e, step)
+		e.PrevError = sn.Error
+		return e, sn.applyUndefinedBehavior(e, step)
 	case events.RuntimeError:
-		return sn.applyRuntimeError(e, step)
+		e.PrevError = sn.Error
+		return e, sn.applyRuntimeError(e, step)
 	default:
-		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("unknown event type: %T", e))
+		return e, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("unknown event type: %T", e))
 	}
 }
 
@@ -69,71 +182,123 @@ func (sn *Snapshot) applyUndefinedBehavior(e events.UndefinedBehavior, step int)
 	return nil
 }
 
+func (sn *Snapshot) applyAssertionFailed(e events.AssertionFailed) error {
+	sn.Error = e.Message
+	return nil
+}
+
 func (sn *Snapshot) applyRuntimeError(e events.RuntimeError, step int) error {
 	sn.Error = e.Message
 	return nil
 }
 
-func (sn *Snapshot) applyEnterScope() error {
+func (sn *Snapshot) applyEnterScope(e events.EnterScope) error {
 	frame := sn.CallStack.GetCurrentFrame()
 	if frame == nil {
 		return runtimeerrors.NewErrUnexpectedInternalError("no current frame for enter scope")
 	}
-	frame.EnterScope()
+	frame.EnterScope().ScopeInfo = e.ScopeInfo
 	return nil
 }
 
-func (sn *Snapshot) applyExitScope() error {
+func (sn *Snapshot) applyExitScope(e *events.ExitScope) error {
 	frame := sn.CallStack.GetCurrentFrame()
 	if frame == nil {
 		return runtimeerrors.NewErrUnexpectedInternalError("no current frame for exit scope")
 	}
+	e.Scope = frame.GetCurrentScope()
 	return frame.ExitScope()
 }
 
 func (sn *Snapshot) applyDeclareVar(e events.DeclareVar, step int) error {
-	variable := runtime.NewVariable(e.Name, e.Value, step, e.IsGlobal)
+	variable := runtime.NewVariable(e.Name, e.Type, e.Value, step, e.IsGlobal)
+	variable.ID = e.ID
+	variable.Enum = e.Enum
 	sn.CallStack.DeclareInCurrentFrame(variable)
 	return nil
 }
 
 func (sn *Snapshot) applyDeclareArray(e events.DeclareArray, step int) error {
-	arr := runtime.NewArray(e.Name, e.Size, e.Value, step, e.IsGlobal)
+	arr := runtime.NewArray(e.Name, e.Type, e.Size, e.Value, step, e.IsGlobal)
+	arr.ID = e.ID
 	sn.CallStack.DeclareInCurrentFrame(arr)
 	return nil
 }
 
 func (sn *Snapshot) applyDeclareArray2D(e events.DeclareArray2D, step int) error {
-	arr := runtime.NewArray2D(e.Name, e.Size1, e.Size2, e.Value, step, e.IsGlobal)
+	arr := runtime.NewArray2D(e.Name, e.Type, e.Size1, e.Size2, e.Value, step, e.IsGlobal)
+	arr.ID = e.ID
 	sn.CallStack.DeclareInCurrentFrame(arr)
 	return nil
 }
 
-func (sn *Snapshot) applyVarChanged(e events.VarChanged, step int) error {
-	variable, ok := sn.CallStack.GetVariableInCurrentFrame(e.Name)
+func (sn *Snapshot) applyVarChanged(e *events.VarChanged, step int) error {
+	variable, ok := sn.findVariable(e.ID, e.Name)
 	if !ok {
 		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("variable %s not found", e.Name))
 	}
+	e.Prev = events.PrevValue{Value: runtime.CloneValue(variable.Value), StepChanged: variable.StepChanged}
 	variable.ChangeValue(e.Value, step)
 	return nil
 }
 
-func (sn *Snapshot) applyArrayElementChanged(e events.ArrayElementChanged, step int) error {
-	arr, ok := sn.CallStack.GetArrayInCurrentFrame(e.Name)
+func (sn *Snapshot) applyArrayElementChanged(e *events.ArrayElementChanged, step int) error {
+	arr, ok := sn.findArray(e.ID, e.Name)
 	if !ok {
 		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("array %s not found", e.Name))
 	}
+	if el, err := arr.GetElement(e.Ind); err == nil {
+		e.Prev = prevValue(el)
+	}
 	return arr.ChangeElement(e.Ind, e.Value, step)
 }
 
-func (sn *Snapshot) applyArray2DElementChanged(e events.Array2DElementChanged, step int) error {
-	arr, ok := sn.CallStack.GetArray2DInCurrentFrame(e.Name)
+func (sn *Snapshot) applyArray2DElementChanged(e *events.Array2DElementChanged, step int) error {
+	arr, ok := sn.findArray2D(e.ID, e.Name)
 	if !ok {
 		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("array2d %s not found", e.Name))
 	}
+	if row, err := arr.GetArray(e.Ind1); err == nil {
+		if el, err := row.GetElement(e.Ind2); err == nil {
+			e.Prev = prevValue(el)
+		}
+	}
 	return arr.ChangeElement(e.Ind1, e.Ind2, e.Value, step)
 }
 
+// findVariable ищет переменную текущего кадра по идентификатору объявления. События без идентификатора
+// (трассы, записанные до его появления) ищут переменную по имени
+func (sn *Snapshot) findVariable(id int, name string) (*runtime.Variable, bool) {
+	if id == 0 {
+		return sn.CallStack.GetVariableInCurrentFrame(name)
+	}
+	d, _ := sn.CallStack.GetDeclaredInCurrentFrame(id)
+	variable, ok := d.(*runtime.Variable)
+	return variable, ok
+}
+
+func (sn *Snapshot) findArray(id int, name string) (*runtime.Array, bool) {
+	if id == 0 {
+		return sn.CallStack.GetArrayInCurrentFrame(name)
+	}
+	d, _ := sn.CallStack.GetDeclaredInCurrentFrame(id)
+	arr, ok := d.(*runtime.Array)
+	return arr, ok
+}
+
+func (sn *Snapshot) findArray2D(id int, name string) (*runtime.Array2D, bool) {
+	if id == 0 {
+		return sn.CallStack.GetArray2DInCurrentFrame(name)
+	}
+	d, _ := sn.CallStack.GetDeclaredInCurrentFrame(id)
+	arr, ok := d.(*runtime.Array2D)
+	return arr, ok
+}
+
+func prevValue(el *runtime.ArrayElement) events.PrevValue {
+	return events.PrevValue{Value: runtime.CloneValue(el.Value), StepChanged: el.StepChanged}
+}
+
 func (sn *Snapshot) applyFunctionCall(e events.FunctionCall) error {
 	if len(sn.CallStack.Frames) == 0 {
 		return runtimeerrors.NewErrUnexpectedInternalError("no frames in call stack")
@@ -144,11 +309,13 @@ func (sn *Snapshot) applyFunctionCall(e events.FunctionCall) error {
 	return nil
 }
 
-func (sn *Snapshot) applyFunctionReturn(e events.FunctionReturn) error {
+func (sn *Snapshot) applyFunctionReturn(e *events.FunctionReturn) error {
 	frame := sn.CallStack.GetCurrentFrame()
 	if frame == nil {
 		return runtimeerrors.NewErrUnexpectedInternalError("no current frame for function return")
 	}
+	e.Frame, e.PrevFrameReturnValue = frame, runtime.CloneValue(frame.ReturnValue)
+	e.PrevFunctionName, e.PrevReturnValue = sn.FunctionName, sn.ReturnValue
 	sn.FunctionName = e.Name
 	sn.ReturnValue = e.ReturnValue
 	if e.ReturnValue != nil {
@@ -157,16 +324,307 @@ func (sn *Snapshot) applyFunctionReturn(e events.FunctionReturn) error {
 	return sn.CallStack.PopFrame()
 }
 
-func (sn *Snapshot) applyLineChanged(e events.LineChanged) error {
+func (sn *Snapshot) applyHeapAlloc(e events.HeapAlloc, step int) error {
+	block := sn.Heap.Allocate(e.Address, e.Size, e.Line, e.Zeroed)
+	if e.Type != "" {
+		if err := block.SetType(e.Type, step); err != nil {
+			return err
+		}
+	}
+	if e.CopyFrom != 0 {
+		old, err := sn.findHeapBlock(e.CopyFrom)
+		if err != nil {
+			return err
+		}
+		block.CopyFrom(old, step)
+	}
+	return nil
+}
+
+func (sn *Snapshot) applyHeapBlockTyped(e *events.HeapBlockTyped, step int) error {
+	block, err := sn.findHeapBlock(e.Address)
+	if err != nil {
+		return err
+	}
+	// SetType заменяет срез элементов, поэтому прежний срез не изменится
+	e.PrevType, e.PrevValues = block.Type, block.Values
+	return block.SetType(e.Type, step)
+}
+
+func (sn *Snapshot) applyHeapFree(e events.HeapFree) error {
+	block, err := sn.findHeapBlock(e.Address)
+	if err != nil {
+		return err
+	}
+	sn.Heap.Free(block)
+	return nil
+}
+
+func (sn *Snapshot) applyHeapElementChanged(e *events.HeapElementChanged, step int) error {
+	block, err := sn.findHeapBlock(e.Address)
+	if err != nil {
+		return err
+	}
+	if e.Ind < 0 || e.Ind >= len(block.Values) {
+		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("index out of bounds in heap block %s", runtime.FormatAddress(e.Address)))
+	}
+	e.Prev = prevValue(&block.Values[e.Ind])
+	block.Values[e.Ind].ChangeValue(e.Value, step)
+	return nil
+}
+
+func (sn *Snapshot) applyHeapLeak(e events.HeapLeak) error {
+	block, err := sn.findHeapBlock(e.Address)
+	if err != nil {
+		return err
+	}
+	sn.Leaks = append(sn.Leaks, block)
+	return nil
+}
+
+func (sn *Snapshot) findHeapBlock(address int) (*runtime.HeapBlock, error) {
+	block, ok := sn.Heap.Find(address)
+	if !ok || block.Address != address {
+		return nil, runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("heap block %s not found", runtime.FormatAddress(address)))
+	}
+	return block, nil
+}
+
+func (sn *Snapshot) applyLineChanged(e *events.LineChanged) error {
+	e.Prev = events.PrevPosition{Line: sn.Line, Range: sn.Range}
 	sn.Line = e.Line
+	sn.Range = nil
+	if frame := sn.CallStack.GetCurrentFrame(); frame != nil {
+		e.Prev.Evaluated = frame.Evaluated
+		frame.ClearEvaluated(e.Line)
+	}
+	return nil
+}
+
+func (sn *Snapshot) applyRangeChanged(e events.RangeChanged) error {
+	loc := e.Loc
+	sn.Range = &loc
 	return nil
 }
 
+func (sn *Snapshot) applyExprEvaluated(e *events.ExprEvaluated) error {
+	frame := sn.CallStack.GetCurrentFrame()
+	if frame == nil {
+		return runtimeerrors.NewErrUnexpectedInternalError("no current frame for evaluated expression")
+	}
+	e.PrevEvaluated = frame.Evaluated
+	frame.AddEvaluated(e.Loc, e.Value)
+	return nil
+}
+
+// addAccess отмечает чтение в текущем кадре
+func (sn *Snapshot) addAccess(access Access) {
+	access.Frame = sn.CallStack.FramesCount() - 1
+	sn.Accessed = append(sn.Accessed, access)
+}
+
+// Unapply отменяет событие, возвращенное Record: восстанавливает состояние, которое событие перезаписало.
+// События шага отменяются в порядке, обратном применению
+func (sn *Snapshot) Unapply(event events.Event) error {
+	frame := sn.CallStack.GetCurrentFrame()
+	if frame == nil {
+		return runtimeerrors.NewErrUnexpectedInternalError("no current frame for unapply")
+	}
+
+	switch e := event.(type) {
+	case events.EnterScope:
+		return frame.ExitScope()
+	case events.ExitScope:
+		if e.Scope == nil {
+			return runtimeerrors.NewErrUnexpectedInternalError("exit scope has no recorded scope")
+		}
+		frame.RestoreScope(e.Scope)
+		return nil
+	case events.DeclareVar, events.DeclareArray, events.DeclareArray2D:
+		if _, ok := frame.GetCurrentScope().Declarations.Undeclare(); !ok {
+			return runtimeerrors.NewErrUnexpectedInternalError("no declaration to unapply")
+		}
+		return nil
+	case events.VarChanged:
+		variable, ok := sn.findVariable(e.ID, e.Name)
+		if !ok {
+			return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("variable %s not found", e.Name))
+		}
+		variable.Value, variable.StepChanged = runtime.CloneValue(e.Prev.Value), e.Prev.StepChanged
+		return nil
+	case events.ArrayElementChanged:
+		arr, ok := sn.findArray(e.ID, e.Name)
+		if !ok {
+			return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("array %s not found", e.Name))
+		}
+		el, err := arr.GetElement(e.Ind)
+		if err != nil {
+			return err
+		}
+		restoreElement(el, e.Prev)
+		return nil
+	case events.Array2DElementChanged:
+		arr, ok := sn.findArray2D(e.ID, e.Name)
+		if !ok {
+			return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("array2d %s not found", e.Name))
+		}
+		row, err := arr.GetArray(e.Ind1)
+		if err != nil {
+			return err
+		}
+		el, err := row.GetElement(e.Ind2)
+		if err != nil {
+			return err
+		}
+		restoreElement(el, e.Prev)
+		return nil
+	case events.ImplicitConversion, events.BuiltinCall:
+		return nil
+	case events.AssertionFailed:
+		sn.Error = e.PrevError
+		return nil
+	case events.InfiniteLoop:
+		sn.Error = e.PrevError
+		return nil
+	case events.FunctionCall:
+		return sn.CallStack.PopFrame()
+	case events.FunctionReturn:
+		if e.Frame == nil {
+			return runtimeerrors.NewErrUnexpectedInternalError("function return has no recorded frame")
+		}
+		e.Frame.ReturnValue = runtime.CloneValue(e.PrevFrameReturnValue)
+		sn.CallStack.PushFrame(e.Frame)
+		sn.FunctionName, sn.ReturnValue = e.PrevFunctionName, e.PrevReturnValue
+		return nil
+	case events.HeapAlloc:
+		block, err := sn.findHeapBlock(e.Address)
+		if err != nil {
+			return err
+		}
+		sn.Heap.Remove(block)
+		return nil
+	case events.HeapBlockTyped:
+		block, err := sn.findHeapBlock(e.Address)
+		if err != nil {
+			return err
+		}
+		block.Type, block.Values = e.PrevType, e.PrevValues
+		return nil
+	case events.HeapFree:
+		block, err := sn.findHeapBlock(e.Address)
+		if err != nil {
+			return err
+		}
+		sn.Heap.Unfree(block)
+		return nil
+	case events.HeapElementChanged:
+		block, err := sn.findHeapBlock(e.Address)
+		if err != nil {
+			return err
+		}
+		if e.Ind < 0 || e.Ind >= len(block.Values) {
+			return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("index out of bounds in heap block %s", runtime.FormatAddress(e.Address)))
+		}
+		restoreElement(&block.Values[e.Ind], e.Prev)
+		return nil
+	case events.HeapLeak:
+		if len(sn.Leaks) == 0 {
+			return runtimeerrors.NewErrUnexpectedInternalError("no heap leak to unapply")
+		}
+		sn.Leaks = sn.Leaks[:len(sn.Leaks)-1]
+		return nil
+	case events.LineChanged:
+		sn.Line, sn.Range = e.Prev.Line, e.Prev.Range
+		// срез ограничивается по длине, чтобы следующие AddEvaluated не писали в общий с событием массив
+		frame.Evaluated = slices.Clip(e.Prev.Evaluated)
+		return nil
+	case events.RangeChanged:
+		sn.Range = e.PrevRange
+		return nil
+	case events.ExprEvaluated:
+		frame.Evaluated = slices.Clip(e.PrevEvaluated)
+		return nil
+	case events.VarRead, events.ArrayElementRead, events.Array2DElementRead, events.HeapElementRead:
+		if len(sn.Accessed) == 0 {
+			return runtimeerrors.NewErrUnexpectedInternalError("no read to unapply")
+		}
+		sn.Accessed = slices.Clip(sn.Accessed[:len(sn.Accessed)-1])
+		return nil
+	case events.ArrayIndexed:
+		if len(sn.IndexMarkers) == 0 {
+			return runtimeerrors.NewErrUnexpectedInternalError("no index marker to unapply")
+		}
+		sn.IndexMarkers = slices.Clip(sn.IndexMarkers[:len(sn.IndexMarkers)-1])
+		return nil
+	case events.VariableDestroyed:
+		if len(sn.Destroyed) == 0 {
+			return runtimeerrors.NewErrUnexpectedInternalError("no destroyed variable to unapply")
+		}
+		sn.Destroyed = slices.Clip(sn.Destroyed[:len(sn.Destroyed)-1])
+		return nil
+	case events.BranchEvaluated:
+		return nil
+	case events.UndefinedBehavior:
+		sn.Error = e.PrevError
+		return nil
+	case events.RuntimeError:
+		sn.Error = e.PrevError
+		return nil
+	default:
+		return runtimeerrors.NewErrUndefinedBehavior(fmt.Sprintf("unknown event type: %T", e))
+	}
+}
+
+func restoreElement(el *runtime.ArrayElement, prev events.PrevValue) {
+	el.Value, el.StepChanged = runtime.CloneValue(prev.Value), prev.StepChanged
+}
+
 func (sn *Snapshot) Reset() {
-	sn.GlobalScope = runtime.NewScope(nil)
+	sn.GlobalScope = runtime.NewGlobalScope()
 	sn.CallStack = runtime.NewCallStack(sn.GlobalScope)
 	sn.Line = -1
+	sn.Range = nil
 	sn.Error = ""
+	sn.Heap = runtime.NewHeap()
+	sn.Leaks = nil
+	sn.Accessed = nil
+	sn.IndexMarkers = nil
+	sn.Destroyed = nil
+}
+
+// Clone возвращает независимую копию снимка
+func (sn *Snapshot) Clone() *Snapshot {
+	globalScope := sn.GlobalScope.Clone(nil)
+	clone := &Snapshot{
+		CallStack:    sn.CallStack.Clone(globalScope),
+		GlobalScope:  globalScope,
+		Line:         sn.Line,
+		Error:        sn.Error,
+		FunctionName: sn.FunctionName,
+		ReturnValue:  runtime.CloneValue(sn.ReturnValue),
+		Heap:         sn.Heap.Clone(),
+	}
+	for _, access := range sn.Accessed {
+		access.Index = slices.Clone(access.Index)
+		clone.Accessed = append(clone.Accessed, access)
+	}
+	clone.IndexMarkers = slices.Clone(sn.IndexMarkers)
+	clone.Destroyed = slices.Clone(sn.Destroyed)
+	if sn.Range != nil {
+		loc := *sn.Range
+		clone.Range = &loc
+	}
+	for _, leak := range sn.Leaks {
+		block, _ := clone.Heap.Find(leak.Address)
+		clone.Leaks = append(clone.Leaks, block)
+	}
+	return clone
+}
+
+// Checkpoint - сохраненная копия состояния после шага Step (нумерация от начала main)
+type Checkpoint struct {
+	Step     int
+	Snapshot *Snapshot
 }
 
 // Методы для чтения текущего состояния
@@ -1,8 +1,10 @@
 package snapshot
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
 	"github.com/stretchr/testify/assert"
@@ -13,7 +15,7 @@ import (
 func makeArrayElements(values []int) []runtime.ArrayElement {
 	result := make([]runtime.ArrayElement, len(values))
 	for i, v := range values {
-		val := v
+		val := runtime.NewIntValue(v)
 		result[i] = runtime.ArrayElement{Value: &val, StepChanged: 0}
 	}
 	return result
@@ -62,7 +64,7 @@ func TestSnapshotApplyDeclareVar(t *testing.T) {
 func TestSnapshotApplyDeclareVarWithInitialValue(t *testing.T) {
 	sn := NewSnapshot()
 
-	val := 42
+	val := runtime.NewIntValue(42)
 	event := events.DeclareVar{
 		Name:  "x",
 		Value: &val,
@@ -75,7 +77,7 @@ func TestSnapshotApplyDeclareVarWithInitialValue(t *testing.T) {
 	assert.True(t, ok)
 	value, err := variable.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 42, value)
+	assert.Equal(t, runtime.NewIntValue(42), value)
 }
 
 func TestSnapshotApplyDeclareArray(t *testing.T) {
@@ -160,7 +162,7 @@ func TestSnapshotApplyVarChanged(t *testing.T) {
 	// Change variable
 	changeEvent := events.VarChanged{
 		Name:  "x",
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err = sn.Apply(changeEvent, 0)
 	require.NoError(t, err)
@@ -169,7 +171,150 @@ func TestSnapshotApplyVarChanged(t *testing.T) {
 	assert.True(t, ok)
 	value, err := variable.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 100, value)
+	assert.Equal(t, runtime.NewIntValue(100), value)
+}
+
+func TestSnapshotEnumVariableShowsEnumeratorName(t *testing.T) {
+	sn := NewSnapshot()
+
+	color := runtime.NewEnum("Color", []runtime.Enumerator{{Name: "RED", Value: 0}, {Name: "GREEN", Value: 5}})
+	val := runtime.NewIntValue(0)
+	require.NoError(t, sn.Apply(events.DeclareVar{Name: "c", Type: runtime.TypeInt, Value: &val, Enum: color}, 0))
+
+	variable, ok := sn.GetVariable("c")
+	require.True(t, ok)
+	data, err := json.Marshal(variable)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"c","type":"int","value":{"type":"int","value":0},"step_changed":0,"enum":"Color","enumerator":"RED"}`, string(data))
+
+	require.NoError(t, sn.Apply(events.VarChanged{Name: "c", Value: runtime.NewIntValue(5)}, 1))
+	data, err = json.Marshal(variable)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"enumerator":"GREEN"`)
+
+	// значение без соответствующей константы показывается только числом
+	require.NoError(t, sn.Apply(events.VarChanged{Name: "c", Value: runtime.NewIntValue(7)}, 2))
+	data, err = json.Marshal(variable)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"enumerator"`)
+}
+
+func TestSnapshotHeapBlocksAndLeaks(t *testing.T) {
+	sn := NewSnapshot()
+
+	require.NoError(t, sn.Apply(events.HeapAlloc{Address: 0x18000, Size: 8, Line: 2, Zeroed: true}, 1))
+	require.NoError(t, sn.Apply(events.HeapBlockTyped{Address: 0x18000, Type: runtime.TypeInt}, 1))
+	require.NoError(t, sn.Apply(events.HeapElementChanged{Address: 0x18000, Ind: 1, Value: runtime.NewIntValue(7)}, 2))
+
+	require.Len(t, sn.Heap.Blocks, 1)
+	block := sn.Heap.Blocks[0]
+	assert.Equal(t, 2, block.Line)
+	require.Len(t, block.Values, 2)
+	assert.Equal(t, runtime.NewIntValue(0), *block.Values[0].Value)
+	assert.Equal(t, runtime.NewIntValue(7), *block.Values[1].Value)
+	assert.Equal(t, 2, block.Values[1].StepChanged)
+
+	// realloc: новый блок того же типа со скопированными значениями, старый освобождается
+	require.NoError(t, sn.Apply(events.HeapAlloc{Address: 0x28000, Size: 12, Line: 4, Type: runtime.TypeInt, CopyFrom: 0x18000}, 3))
+	require.NoError(t, sn.Apply(events.HeapFree{Address: 0x18000}, 3))
+
+	require.Len(t, sn.Heap.Blocks, 1)
+	block = sn.Heap.Blocks[0]
+	assert.Equal(t, 0x28000, block.Address)
+	require.Len(t, block.Values, 3)
+	assert.Equal(t, runtime.NewIntValue(7), *block.Values[1].Value)
+	assert.Nil(t, block.Values[2].Value)
+
+	require.NoError(t, sn.Apply(events.HeapLeak{Address: 0x28000, Size: 12, Line: 4}, 4))
+	require.Len(t, sn.Leaks, 1)
+	assert.Equal(t, 4, sn.Leaks[0].Line)
+
+	assert.Error(t, sn.Apply(events.HeapFree{Address: 0x38000}, 5))
+
+	sn.Reset()
+	assert.Empty(t, sn.Heap.Blocks)
+	assert.Nil(t, sn.Leaks)
+}
+
+func TestSnapshotRangeChanged(t *testing.T) {
+	sn := NewSnapshot()
+
+	cond := converter.Location{Line: 3, Column: 17, EndLine: 3, EndColumn: 22}
+	require.NoError(t, sn.Apply(events.LineChanged{Line: 3}, 1))
+	require.NoError(t, sn.Apply(events.RangeChanged{Loc: cond}, 1))
+	assert.Equal(t, 3, sn.Line)
+	require.NotNil(t, sn.Range)
+	assert.Equal(t, cond, *sn.Range)
+
+	// шаг без участка (возврат из main) сбрасывает участок предыдущего шага
+	require.NoError(t, sn.Apply(events.LineChanged{Line: -1}, 2))
+	assert.Nil(t, sn.Range)
+
+	require.NoError(t, sn.Apply(events.RangeChanged{Loc: cond}, 3))
+	sn.Reset()
+	assert.Nil(t, sn.Range)
+}
+
+func TestSnapshotExprEvaluated(t *testing.T) {
+	sn := NewSnapshot()
+	loc := func(line, column, endColumn uint32) converter.Location {
+		return converter.Location{Line: line, Column: column, EndLine: line, EndColumn: endColumn}
+	}
+
+	// int x = a * f(3) + 1; на строке 7
+	require.NoError(t, sn.Apply(events.LineChanged{Line: 7}, 1))
+	require.NoError(t, sn.Apply(events.ExprEvaluated{Loc: loc(7, 9, 10), Value: runtime.NewIntValue(2)}, 2))
+	require.NoError(t, sn.Apply(events.FunctionCall{Name: "f"}, 3))
+	require.NoError(t, sn.Apply(events.LineChanged{Line: 2}, 3))
+	require.NoError(t, sn.Apply(events.ExprEvaluated{Loc: loc(2, 8, 13), Value: runtime.NewIntValue(6)}, 4))
+	assert.Len(t, sn.GetCurrentFrame().Evaluated, 1)
+
+	// возврат на строку вызова сохраняет частично вычисленное выражение вызывающей функции
+	require.NoError(t, sn.Apply(events.FunctionReturn{Name: "f"}, 5))
+	require.NoError(t, sn.Apply(events.LineChanged{Line: 7}, 5))
+	require.NoError(t, sn.Apply(events.ExprEvaluated{Loc: loc(7, 13, 17), Value: runtime.NewIntValue(6)}, 6))
+	require.NoError(t, sn.Apply(events.ExprEvaluated{Loc: loc(7, 9, 17), Value: runtime.NewIntValue(12)}, 7))
+
+	evaluated := sn.GetCurrentFrame().Evaluated
+	require.Len(t, evaluated, 3)
+	assert.Equal(t, runtime.NewIntValue(2), evaluated[0].Value)
+	assert.Equal(t, runtime.NewIntValue(12), evaluated[2].Value)
+
+	// повторное вычисление того же участка (следующая итерация цикла на одной строке) начинает выражение заново
+	require.NoError(t, sn.Apply(events.ExprEvaluated{Loc: loc(7, 9, 10), Value: runtime.NewIntValue(3)}, 8))
+	require.Len(t, sn.GetCurrentFrame().Evaluated, 1)
+
+	// переход к другой строке сбрасывает выражение
+	require.NoError(t, sn.Apply(events.LineChanged{Line: 8}, 9))
+	assert.Empty(t, sn.GetCurrentFrame().Evaluated)
+}
+
+func TestSnapshotBuiltinCallAndAssertionFailed(t *testing.T) {
+	sn := NewSnapshot()
+
+	arg := runtime.NewIntValue(0)
+	require.NoError(t, sn.Apply(events.BuiltinCall{Name: "assert", Header: "assert.h", Arguments: []*runtime.Value{&arg}}, 0))
+	assert.Empty(t, sn.Error)
+	assert.Equal(t, 1, sn.GetFramesCount())
+
+	require.NoError(t, sn.Apply(events.AssertionFailed{Message: "assertion failed: assert at line 3", Line: 3}, 0))
+	assert.Equal(t, "assertion failed: assert at line 3", sn.Error)
+}
+
+func TestSnapshotInfiniteLoop(t *testing.T) {
+	sn := NewSnapshot()
+
+	loop, err := sn.Record(events.InfiniteLoop{
+		Construct: events.BranchWhile,
+		Cycle:     1,
+		Unchanged: []string{"i"},
+		Message:   "infinite loop: line 4: i is never modified in this while loop",
+	}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "infinite loop: line 4: i is never modified in this while loop", sn.Error)
+
+	require.NoError(t, sn.Unapply(loop))
+	assert.Empty(t, sn.Error)
 }
 
 func TestSnapshotApplyVarChangedNotFound(t *testing.T) {
@@ -177,7 +322,7 @@ func TestSnapshotApplyVarChangedNotFound(t *testing.T) {
 
 	changeEvent := events.VarChanged{
 		Name:  "nonexistent",
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -199,7 +344,7 @@ func TestSnapshotApplyArrayElementChanged(t *testing.T) {
 	changeEvent := events.ArrayElementChanged{
 		Name:  "arr",
 		Ind:   1,
-		Value: 42,
+		Value: runtime.NewIntValue(42),
 	}
 	err = sn.Apply(changeEvent, 0)
 	require.NoError(t, err)
@@ -209,7 +354,7 @@ func TestSnapshotApplyArrayElementChanged(t *testing.T) {
 	value, err := arr.GetElement(1)
 	assert.NoError(t, err)
 	valInt, err := value.GetValue()
-	assert.Equal(t, 42, valInt)
+	assert.Equal(t, runtime.NewIntValue(42), valInt)
 }
 
 func TestSnapshotApplyArray2DElementChanged(t *testing.T) {
@@ -230,7 +375,7 @@ func TestSnapshotApplyArray2DElementChanged(t *testing.T) {
 		Name:  "matrix",
 		Ind1:  1,
 		Ind2:  1,
-		Value: 99,
+		Value: runtime.NewIntValue(99),
 	}
 	err = sn.Apply(changeEvent, 0)
 	require.NoError(t, err)
@@ -239,7 +384,7 @@ func TestSnapshotApplyArray2DElementChanged(t *testing.T) {
 	assert.True(t, ok)
 	value, err := arr2d.GetElement(1, 1)
 	assert.NoError(t, err)
-	assert.Equal(t, 99, value)
+	assert.Equal(t, runtime.NewIntValue(99), value)
 }
 
 func TestSnapshotApplyFunctionCallAndReturn(t *testing.T) {
@@ -261,7 +406,7 @@ func TestSnapshotApplyFunctionCallAndReturn(t *testing.T) {
 	assert.Equal(t, "myFunc", frame.FuncName)
 
 	// Function return
-	retVal := 55
+	retVal := runtime.NewIntValue(55)
 	returnEvent := events.FunctionReturn{
 		Name:        "myFunc",
 		ReturnValue: &retVal,
@@ -351,7 +496,7 @@ func TestSnapshotComplexScenario(t *testing.T) {
 	assert.NoError(t, sn.Apply(declareArrayEvent, 0))
 
 	// Change variable
-	varChangeEvent := events.VarChanged{Name: "x", Value: 5}
+	varChangeEvent := events.VarChanged{Name: "x", Value: runtime.NewIntValue(5)}
 	assert.NoError(t, sn.Apply(varChangeEvent, 0))
 
 	// Function call
@@ -367,7 +512,7 @@ func TestSnapshotComplexScenario(t *testing.T) {
 	assert.NoError(t, sn.Apply(localVarEvent, 0))
 
 	// Change array element
-	arrChangeEvent := events.ArrayElementChanged{Name: "arr", Ind: 0, Value: 100}
+	arrChangeEvent := events.ArrayElementChanged{Name: "arr", Ind: 0, Value: runtime.NewIntValue(100)}
 	assert.NoError(t, sn.Apply(arrChangeEvent, 0))
 
 	// Exit scope
@@ -375,7 +520,7 @@ func TestSnapshotComplexScenario(t *testing.T) {
 	assert.NoError(t, sn.Apply(exitScopeEvent, 0))
 
 	// Return from function
-	returnVal := 42
+	returnVal := runtime.NewIntValue(42)
 	returnEvent := events.FunctionReturn{Name: "process", ReturnValue: &returnVal}
 	assert.NoError(t, sn.Apply(returnEvent, 0))
 
@@ -388,14 +533,14 @@ func TestSnapshotComplexScenario(t *testing.T) {
 	assert.True(t, ok)
 	xVal, err := x.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 5, xVal)
+	assert.Equal(t, runtime.NewIntValue(5), xVal)
 
 	arr, ok := sn.GetArray("arr")
 	assert.True(t, ok)
 	elemVal, err := arr.GetElement(0)
 	assert.NoError(t, err)
 	elemValInt, err := elemVal.GetValue()
-	assert.Equal(t, 100, elemValInt)
+	assert.Equal(t, runtime.NewIntValue(100), elemValInt)
 }
 
 func TestSnapshotGetArrayNotFound(t *testing.T) {
@@ -465,7 +610,7 @@ func TestScenarioFactorial(t *testing.T) {
 	sn.Apply(events.LineChanged{Line: 1}, 0)
 
 	// n = 5
-	sn.Apply(events.VarChanged{Name: "n", Value: 5}, 0)
+	sn.Apply(events.VarChanged{Name: "n", Value: runtime.NewIntValue(5)}, 0)
 	sn.Apply(events.LineChanged{Line: 2}, 0)
 
 	// Объявление результата
@@ -481,7 +626,7 @@ func TestScenarioFactorial(t *testing.T) {
 	sn.Apply(events.DeclareVar{Name: "n", Value: nil}, 0)
 	sn.Apply(events.LineChanged{Line: 10}, 0)
 
-	sn.Apply(events.VarChanged{Name: "n", Value: 5}, 0)
+	sn.Apply(events.VarChanged{Name: "n", Value: runtime.NewIntValue(5)}, 0)
 
 	// if (n == 1) return 1
 	sn.Apply(events.LineChanged{Line: 11}, 0)
@@ -493,7 +638,7 @@ func TestScenarioFactorial(t *testing.T) {
 	// =================== factorial(4) ===================
 	sn.Apply(events.EnterScope{}, 0)
 	sn.Apply(events.DeclareVar{Name: "n", Value: nil}, 0)
-	sn.Apply(events.VarChanged{Name: "n", Value: 4}, 0)
+	sn.Apply(events.VarChanged{Name: "n", Value: runtime.NewIntValue(4)}, 0)
 
 	// Ещё один рекурсивный вызов factorial(3)
 	sn.Apply(events.FunctionCall{Name: "factorial"}, 0)
@@ -501,7 +646,7 @@ func TestScenarioFactorial(t *testing.T) {
 	// =================== factorial(3) ===================
 	sn.Apply(events.EnterScope{}, 0)
 	sn.Apply(events.DeclareVar{Name: "n", Value: nil}, 0)
-	sn.Apply(events.VarChanged{Name: "n", Value: 3}, 0)
+	sn.Apply(events.VarChanged{Name: "n", Value: runtime.NewIntValue(3)}, 0)
 
 	// Рекурсивный вызов factorial(2)
 	sn.Apply(events.FunctionCall{Name: "factorial"}, 0)
@@ -509,7 +654,7 @@ func TestScenarioFactorial(t *testing.T) {
 	// =================== factorial(2) ===================
 	sn.Apply(events.EnterScope{}, 0)
 	sn.Apply(events.DeclareVar{Name: "n", Value: nil}, 0)
-	sn.Apply(events.VarChanged{Name: "n", Value: 2}, 0)
+	sn.Apply(events.VarChanged{Name: "n", Value: runtime.NewIntValue(2)}, 0)
 
 	// Рекурсивный вызов factorial(1)
 	sn.Apply(events.FunctionCall{Name: "factorial"}, 0)
@@ -517,34 +662,34 @@ func TestScenarioFactorial(t *testing.T) {
 	// =================== factorial(1) - базовый случай ===================
 	sn.Apply(events.EnterScope{}, 0)
 	sn.Apply(events.DeclareVar{Name: "n", Value: nil}, 0)
-	sn.Apply(events.VarChanged{Name: "n", Value: 1}, 0)
+	sn.Apply(events.VarChanged{Name: "n", Value: runtime.NewIntValue(1)}, 0)
 
 	// return 1
-	retVal1 := 1
+	retVal1 := runtime.NewIntValue(1)
 	sn.Apply(events.FunctionReturn{Name: "factorial", ReturnValue: &retVal1}, 0)
 
 	// =================== Возврат из factorial(2) ===================
 	sn.Apply(events.ExitScope{}, 0)
-	retVal2 := 2 // 2 * 1
+	retVal2 := runtime.NewIntValue(2) // 2 * 1
 	sn.Apply(events.FunctionReturn{Name: "factorial", ReturnValue: &retVal2}, 0)
 
 	// =================== Возврат из factorial(3) ===================
 	sn.Apply(events.ExitScope{}, 0)
-	retVal6 := 6 // 3 * 2
+	retVal6 := runtime.NewIntValue(6) // 3 * 2
 	sn.Apply(events.FunctionReturn{Name: "factorial", ReturnValue: &retVal6}, 0)
 
 	// =================== Возврат из factorial(4) ===================
 	sn.Apply(events.ExitScope{}, 0)
-	retVal24 := 24 // 4 * 6
+	retVal24 := runtime.NewIntValue(24) // 4 * 6
 	sn.Apply(events.FunctionReturn{Name: "factorial", ReturnValue: &retVal24}, 0)
 
 	// =================== Возврат из factorial(5) ===================
 	sn.Apply(events.ExitScope{}, 0)
-	retVal120 := 120 // 5 * 24
+	retVal120 := runtime.NewIntValue(120) // 5 * 24
 	sn.Apply(events.FunctionReturn{Name: "factorial", ReturnValue: &retVal120}, 0)
 
 	// Присвоение результата
-	sn.Apply(events.VarChanged{Name: "result", Value: 120}, 0)
+	sn.Apply(events.VarChanged{Name: "result", Value: runtime.NewIntValue(120)}, 0)
 	sn.Apply(events.LineChanged{Line: 5}, 0)
 
 	// Проверяем финальное состояние
@@ -552,7 +697,7 @@ func TestScenarioFactorial(t *testing.T) {
 	assert.True(t, ok)
 	resultVal, err := result.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 120, resultVal)
+	assert.Equal(t, runtime.NewIntValue(120), resultVal)
 	assert.Equal(t, 1, sn.GetFramesCount()) // Вернулись в main frame
 }
 
@@ -576,42 +721,42 @@ func TestScenarioBubbleSort(t *testing.T) {
 
 	// Bubble sort: две вложенные ячейки
 	// i = 0
-	sn.Apply(events.VarChanged{Name: "i", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(0)}, 0)
 	sn.Apply(events.LineChanged{Line: 4}, 0)
 
 	// j = 0, arr[0]=5 > arr[1]=2 -> swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 0}, 0)
-	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 0, Value: 2}, 0)
-	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 1, Value: 5}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(0)}, 0)
+	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 0, Value: runtime.NewIntValue(2)}, 0)
+	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 1, Value: runtime.NewIntValue(5)}, 0)
 
 	// j = 1, arr[1]=5 < arr[2]=8 -> no swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 1}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(1)}, 0)
 
 	// j = 2, arr[2]=8 > arr[3]=1 -> swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 2}, 0)
-	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 2, Value: 1}, 0)
-	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 3, Value: 8}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(2)}, 0)
+	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 2, Value: runtime.NewIntValue(1)}, 0)
+	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 3, Value: runtime.NewIntValue(8)}, 0)
 
 	// j = 3, arr[3]=8 < arr[4]=9 -> no swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 3}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(3)}, 0)
 
 	// Второй проход (i = 1)
-	sn.Apply(events.VarChanged{Name: "i", Value: 1}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(1)}, 0)
 	sn.Apply(events.LineChanged{Line: 4}, 0)
 
 	// j = 0, arr[0]=2 < arr[1]=5 -> no swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(0)}, 0)
 
 	// j = 1, arr[1]=5 > arr[2]=1 -> swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 1}, 0)
-	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 1, Value: 1}, 0)
-	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 2, Value: 5}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(1)}, 0)
+	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 1, Value: runtime.NewIntValue(1)}, 0)
+	sn.Apply(events.ArrayElementChanged{Name: "arr", Ind: 2, Value: runtime.NewIntValue(5)}, 0)
 
 	// j = 2, arr[2]=5 < arr[3]=8 -> no swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 2}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(2)}, 0)
 
 	// j = 3, arr[3]=8 < arr[4]=9 -> no swap
-	sn.Apply(events.VarChanged{Name: "j", Value: 3}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(3)}, 0)
 
 	// После нескольких итераций проверяем состояние
 	arr, ok := sn.GetArray("arr")
@@ -624,9 +769,9 @@ func TestScenarioBubbleSort(t *testing.T) {
 	val0Int, _ := val0.GetValue()
 	val1Int, _ := val1.GetValue()
 	val2Int, _ := val2.GetValue()
-	assert.Equal(t, 2, val0Int)
-	assert.Equal(t, 1, val1Int)
-	assert.Equal(t, 5, val2Int)
+	assert.Equal(t, runtime.NewIntValue(2), val0Int)
+	assert.Equal(t, runtime.NewIntValue(1), val1Int)
+	assert.Equal(t, runtime.NewIntValue(5), val2Int)
 }
 
 // Тест: Поиск в матрице (2D массив)
@@ -650,32 +795,32 @@ func TestScenarioMatrix2DSearch(t *testing.T) {
 	sn.Apply(events.LineChanged{Line: 2}, 0)
 
 	// target = 5
-	sn.Apply(events.VarChanged{Name: "target", Value: 5}, 0)
-	sn.Apply(events.VarChanged{Name: "found", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "target", Value: runtime.NewIntValue(5)}, 0)
+	sn.Apply(events.VarChanged{Name: "found", Value: runtime.NewIntValue(0)}, 0)
 
 	// Поиск по матрице
 	// i = 0
-	sn.Apply(events.VarChanged{Name: "i", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(0)}, 0)
 	sn.Apply(events.LineChanged{Line: 5}, 0)
 
 	// j = 0, matrix[0][0] = 1 != 5
-	sn.Apply(events.VarChanged{Name: "j", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(0)}, 0)
 
 	// j = 1, matrix[0][1] = 2 != 5
-	sn.Apply(events.VarChanged{Name: "j", Value: 1}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(1)}, 0)
 
 	// j = 2, matrix[0][2] = 3 != 5
-	sn.Apply(events.VarChanged{Name: "j", Value: 2}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(2)}, 0)
 
 	// i = 1
-	sn.Apply(events.VarChanged{Name: "i", Value: 1}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(1)}, 0)
 
 	// j = 0, matrix[1][0] = 4 != 5
-	sn.Apply(events.VarChanged{Name: "j", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(0)}, 0)
 
 	// j = 1, matrix[1][1] = 5 == 5 -> НАЙДЕНО!
-	sn.Apply(events.VarChanged{Name: "j", Value: 1}, 0)
-	sn.Apply(events.VarChanged{Name: "found", Value: 1}, 0)
+	sn.Apply(events.VarChanged{Name: "j", Value: runtime.NewIntValue(1)}, 0)
+	sn.Apply(events.VarChanged{Name: "found", Value: runtime.NewIntValue(1)}, 0)
 	sn.Apply(events.LineChanged{Line: 8}, 0)
 
 	// Проверяем финальное состояние
@@ -683,13 +828,13 @@ func TestScenarioMatrix2DSearch(t *testing.T) {
 	assert.True(t, ok)
 	foundVal, err := found.GetValue()
 	assert.NoError(t, err)
-	assert.Equal(t, 1, foundVal) // Найдено
+	assert.Equal(t, runtime.NewIntValue(1), foundVal) // Найдено
 
 	matrix, ok := sn.GetArray2D("matrix")
 	assert.True(t, ok)
 	matVal, err := matrix.GetElement(1, 1)
 	assert.NoError(t, err)
-	assert.Equal(t, 5, matVal) // Правильный элемент матрицы
+	assert.Equal(t, runtime.NewIntValue(5), matVal) // Правильный элемент матрицы
 }
 
 // Тест: Вычисление суммы и среднего (с тремя вложенными функциями)
@@ -716,28 +861,28 @@ func TestScenarioNotationCalculations(t *testing.T) {
 	sn.Apply(events.EnterScope{}, 0)
 	sn.Apply(events.DeclareVar{Name: "total", Value: nil}, 0)
 	sn.Apply(events.DeclareVar{Name: "i", Value: nil}, 0)
-	sn.Apply(events.VarChanged{Name: "total", Value: 0}, 0)
+	sn.Apply(events.VarChanged{Name: "total", Value: runtime.NewIntValue(0)}, 0)
 
 	// Цикл суммирования
-	sn.Apply(events.VarChanged{Name: "i", Value: 0}, 0)
-	sn.Apply(events.VarChanged{Name: "total", Value: 10}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(0)}, 0)
+	sn.Apply(events.VarChanged{Name: "total", Value: runtime.NewIntValue(10)}, 0)
 
-	sn.Apply(events.VarChanged{Name: "i", Value: 1}, 0)
-	sn.Apply(events.VarChanged{Name: "total", Value: 30}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(1)}, 0)
+	sn.Apply(events.VarChanged{Name: "total", Value: runtime.NewIntValue(30)}, 0)
 
-	sn.Apply(events.VarChanged{Name: "i", Value: 2}, 0)
-	sn.Apply(events.VarChanged{Name: "total", Value: 60}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(2)}, 0)
+	sn.Apply(events.VarChanged{Name: "total", Value: runtime.NewIntValue(60)}, 0)
 
-	sn.Apply(events.VarChanged{Name: "i", Value: 3}, 0)
-	sn.Apply(events.VarChanged{Name: "total", Value: 100}, 0)
+	sn.Apply(events.VarChanged{Name: "i", Value: runtime.NewIntValue(3)}, 0)
+	sn.Apply(events.VarChanged{Name: "total", Value: runtime.NewIntValue(100)}, 0)
 
 	// Возврат из calculateSum
-	retSum := 100
+	retSum := runtime.NewIntValue(100)
 	sn.Apply(events.FunctionReturn{Name: "calculateSum", ReturnValue: &retSum}, 0)
-	sn.Apply(events.VarChanged{Name: "sum", Value: 100}, 0)
+	sn.Apply(events.VarChanged{Name: "sum", Value: runtime.NewIntValue(100)}, 0)
 
 	// count = 4
-	sn.Apply(events.VarChanged{Name: "count", Value: 4}, 0)
+	sn.Apply(events.VarChanged{Name: "count", Value: runtime.NewIntValue(4)}, 0)
 
 	// avg = calculateAverage(sum, count)
 	sn.Apply(events.FunctionCall{Name: "calculateAverage"}, 0)
@@ -746,25 +891,25 @@ func TestScenarioNotationCalculations(t *testing.T) {
 	sn.Apply(events.EnterScope{}, 0)
 
 	// Возврат из calculateAverage
-	retAvg := 25
+	retAvg := runtime.NewIntValue(25)
 	sn.Apply(events.FunctionReturn{Name: "calculateAverage", ReturnValue: &retAvg}, 0)
-	sn.Apply(events.VarChanged{Name: "avg", Value: 25}, 0)
+	sn.Apply(events.VarChanged{Name: "avg", Value: runtime.NewIntValue(25)}, 0)
 
 	// Проверяем финальное состояние
 	sum, ok := sn.GetVariable("sum")
 	assert.True(t, ok)
 	sumVal, _ := sum.GetValue()
-	assert.Equal(t, 100, sumVal)
+	assert.Equal(t, runtime.NewIntValue(100), sumVal)
 
 	avg, ok := sn.GetVariable("avg")
 	assert.True(t, ok)
 	avgVal, _ := avg.GetValue()
-	assert.Equal(t, 25, avgVal)
+	assert.Equal(t, runtime.NewIntValue(25), avgVal)
 
 	count, ok := sn.GetVariable("count")
 	assert.True(t, ok)
 	countVal, _ := count.GetValue()
-	assert.Equal(t, 4, countVal)
+	assert.Equal(t, runtime.NewIntValue(4), countVal)
 
 	assert.Equal(t, 1, sn.GetFramesCount()) // Вернулись в main
 }
@@ -777,7 +922,7 @@ func TestUndefinedBehaviorVarNotFound(t *testing.T) {
 	// Попытка изменить переменную, которая не была объявлена
 	changeEvent := events.VarChanged{
 		Name:  "nonexistent",
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -791,7 +936,7 @@ func TestUndefinedBehaviorArrayNotFound(t *testing.T) {
 	changeEvent := events.ArrayElementChanged{
 		Name:  "nonexistent_array",
 		Ind:   0,
-		Value: 42,
+		Value: runtime.NewIntValue(42),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -806,7 +951,7 @@ func TestUndefinedBehaviorArray2DNotFound(t *testing.T) {
 		Name:  "nonexistent_matrix",
 		Ind1:  0,
 		Ind2:  0,
-		Value: 42,
+		Value: runtime.NewIntValue(42),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -827,7 +972,7 @@ func TestUndefinedBehaviorArrayOutOfBoundsPositive(t *testing.T) {
 	changeEvent := events.ArrayElementChanged{
 		Name:  "arr",
 		Ind:   5,
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -848,7 +993,7 @@ func TestUndefinedBehaviorArrayOutOfBoundsNegative(t *testing.T) {
 	changeEvent := events.ArrayElementChanged{
 		Name:  "arr",
 		Ind:   -1,
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -871,7 +1016,7 @@ func TestUndefinedBehaviorArray2DOutOfBoundsRow(t *testing.T) {
 		Name:  "matrix",
 		Ind1:  5,
 		Ind2:  0,
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -894,7 +1039,7 @@ func TestUndefinedBehaviorArray2DOutOfBoundsCol(t *testing.T) {
 		Name:  "matrix",
 		Ind1:  0,
 		Ind2:  10,
-		Value: 100,
+		Value: runtime.NewIntValue(100),
 	}
 	err := sn.Apply(changeEvent, 0)
 	assert.Error(t, err)
@@ -928,7 +1073,7 @@ func TestUndefinedBehaviorEventApplyMultiple(t *testing.T) {
 
 	// Объявляем переменную
 	sn.Apply(events.DeclareVar{Name: "x", Value: nil}, 0)
-	sn.Apply(events.VarChanged{Name: "x", Value: 10}, 0)
+	sn.Apply(events.VarChanged{Name: "x", Value: runtime.NewIntValue(10)}, 0)
 
 	// Применяем второе событие UndefinedBehavior (перезаписываем ошибку)
 	ubEvent2 := events.UndefinedBehavior{
@@ -955,14 +1100,14 @@ func TestUndefinedBehaviorVarNotFoundAfterDeclaration(t *testing.T) {
 
 	// Объявляем переменную x
 	sn.Apply(events.DeclareVar{Name: "x", Value: nil}, 0)
-	assert.NoError(t, sn.Apply(events.VarChanged{Name: "x", Value: 5}, 0))
+	assert.NoError(t, sn.Apply(events.VarChanged{Name: "x", Value: runtime.NewIntValue(5)}, 0))
 
 	// Входим в новую область видимости
 	sn.Apply(events.EnterScope{}, 0)
 
 	// Пытаемся изменить x (она должна найтись из родительской области)
 	// но если реализация требует локальную переменную, то будет ошибка
-	err := sn.Apply(events.VarChanged{Name: "y", Value: 10}, 0)
+	err := sn.Apply(events.VarChanged{Name: "y", Value: runtime.NewIntValue(10)}, 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "variable y not found")
 }
@@ -998,15 +1143,15 @@ func TestUndefinedBehaviorComplexErrorScenario(t *testing.T) {
 	}, 0)
 
 	// Нормальные операции
-	assert.NoError(t, sn.Apply(events.VarChanged{Name: "count", Value: 3}, 0))
-	assert.NoError(t, sn.Apply(events.ArrayElementChanged{Name: "data", Ind: 1, Value: 25}, 0))
+	assert.NoError(t, sn.Apply(events.VarChanged{Name: "count", Value: runtime.NewIntValue(3)}, 0))
+	assert.NoError(t, sn.Apply(events.ArrayElementChanged{Name: "data", Ind: 1, Value: runtime.NewIntValue(25)}, 0))
 
 	// Эмулируем ошибку: попытка доступа к несуществующему элементу
-	err1 := sn.Apply(events.ArrayElementChanged{Name: "data", Ind: 10, Value: 50}, 0)
+	err1 := sn.Apply(events.ArrayElementChanged{Name: "data", Ind: 10, Value: runtime.NewIntValue(50)}, 0)
 	assert.Error(t, err1)
 
 	// Эмулируем ошибку: изменение несуществующей переменной
-	err2 := sn.Apply(events.VarChanged{Name: "undefined_var", Value: 999}, 0)
+	err2 := sn.Apply(events.VarChanged{Name: "undefined_var", Value: runtime.NewIntValue(999)}, 0)
 	assert.Error(t, err2)
 
 	// Проверяем, что массив и переменная всё ещё доступны несмотря на ошибки
@@ -1017,7 +1162,7 @@ func TestUndefinedBehaviorComplexErrorScenario(t *testing.T) {
 	count, ok := sn.GetVariable("count")
 	assert.True(t, ok)
 	countVal, _ := count.GetValue()
-	assert.Equal(t, 3, countVal)
+	assert.Equal(t, runtime.NewIntValue(3), countVal)
 }
 
 func TestUndefinedBehaviorGetNonexistentVariable(t *testing.T) {
@@ -1046,3 +1191,205 @@ func TestUndefinedBehaviorGetNonexistentArray2D(t *testing.T) {
 	assert.False(t, ok)
 	assert.Nil(t, arr2d)
 }
+
+func TestSnapshotCloneIsIndependent(t *testing.T) {
+	sn := NewSnapshot()
+	val := runtime.NewIntValue(1)
+	require.NoError(t, sn.Apply(events.DeclareVar{Name: "g", Type: runtime.TypeInt, Value: &val, IsGlobal: true}, 0))
+	require.NoError(t, sn.Apply(events.FunctionCall{Name: "main"}, 0))
+	require.NoError(t, sn.Apply(events.EnterScope{}, 0))
+	require.NoError(t, sn.Apply(events.DeclareArray{Name: "a", Type: runtime.TypeInt, Size: 2, Value: makeArrayElements([]int{1, 2})}, 0))
+	require.NoError(t, sn.Apply(events.HeapAlloc{Address: 0x18000, Size: 8, Line: 3, Type: runtime.TypeInt}, 0))
+
+	clone := sn.Clone()
+	require.NoError(t, clone.Apply(events.VarChanged{Name: "g", Value: runtime.NewIntValue(7)}, 1))
+	require.NoError(t, clone.Apply(events.ArrayElementChanged{Name: "a", Ind: 0, Value: runtime.NewIntValue(9)}, 1))
+	require.NoError(t, clone.Apply(events.HeapElementChanged{Address: 0x18000, Ind: 1, Value: runtime.NewIntValue(5)}, 1))
+	require.NoError(t, clone.Apply(events.ExitScope{}, 1))
+
+	g, ok := sn.GetVariable("g")
+	require.True(t, ok)
+	assert.Equal(t, 1, g.Value.Int)
+	a, ok := sn.GetArray("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, a.Values[0].Value.Int)
+	assert.Nil(t, sn.Heap.Blocks[0].Values[1].Value)
+	assert.Len(t, sn.GetCurrentFrame().Scopes, 2)
+
+	// глобальная область видимости копии общая для всех ее кадров
+	cg, ok := clone.GetVariable("g")
+	require.True(t, ok)
+	assert.Same(t, clone.GlobalScope, clone.GetCurrentFrame().Scopes[0])
+	assert.Equal(t, 7, cg.Value.Int)
+}
+
+func TestSnapshotAccessed(t *testing.T) {
+	sn := NewSnapshot()
+	for _, e := range []events.Event{
+		events.FunctionCall{Name: "main"},
+		events.EnterScope{},
+		events.DeclareArray{Name: "a", Type: runtime.TypeInt, Size: 2, Value: makeArrayElements([]int{3, 1})},
+		events.DeclareVar{Name: "j", Type: runtime.TypeInt, Value: &runtime.Value{Type: runtime.TypeInt}},
+	} {
+		require.NoError(t, sn.Apply(e, 0))
+	}
+
+	sn.NewStep()
+	require.NoError(t, sn.Apply(events.VarRead{Name: "j", Use: events.ReadIndex}, 1))
+	require.NoError(t, sn.Apply(events.ArrayElementRead{Name: "a", Ind: 0, Value: runtime.NewIntValue(3), Use: events.ReadCompare}, 1))
+	assert.Equal(t, []Access{
+		{Frame: 1, Name: "j", Use: events.ReadIndex},
+		{Frame: 1, Name: "a", Index: []int{0}, Use: events.ReadCompare},
+	}, sn.Accessed)
+
+	require.NoError(t, sn.Apply(events.ArrayIndexed{Variable: "j", Name: "a", Index: 1, Offset: 1}, 1))
+	assert.Equal(t, []IndexMarker{{Frame: 1, Variable: "j", Name: "a", Index: 1, Offset: 1}}, sn.IndexMarkers)
+
+	// чтения и маркеры относятся только к своему шагу
+	clone := sn.Clone()
+	sn.NewStep()
+	assert.Nil(t, sn.Accessed)
+	assert.Nil(t, sn.IndexMarkers)
+	assert.Len(t, clone.Accessed, 2)
+	assert.Len(t, clone.IndexMarkers, 1)
+}
+
+func TestSnapshotChangesResolvedByDeclarationID(t *testing.T) {
+	sn := NewSnapshot()
+	for _, e := range []events.Event{
+		events.FunctionCall{Name: "main"},
+		events.EnterScope{},
+		events.DeclareVar{ID: 1, Name: "x", Type: runtime.TypeInt},
+		events.DeclareArray{ID: 2, Name: "a", Type: runtime.TypeInt, Size: 2},
+		events.EnterScope{},
+		events.DeclareVar{ID: 3, Name: "x", Type: runtime.TypeInt},
+	} {
+		require.NoError(t, sn.Apply(e, 0))
+	}
+
+	// событие с идентификатором изменяет затененную переменную, а не ближайшую по имени
+	require.NoError(t, sn.Apply(events.VarChanged{ID: 1, Name: "x", Value: runtime.NewIntValue(1)}, 1))
+	require.NoError(t, sn.Apply(events.VarChanged{ID: 3, Name: "x", Value: runtime.NewIntValue(3)}, 1))
+	require.NoError(t, sn.Apply(events.ArrayElementChanged{ID: 2, Name: "a", Ind: 1, Value: runtime.NewIntValue(5)}, 1))
+
+	scopes := sn.GetCurrentFrame().Scopes
+	outer, ok := scopes[1].GetByID(1)
+	require.True(t, ok)
+	assert.Equal(t, 1, outer.(*runtime.Variable).Value.Int)
+	inner, ok := sn.GetVariable("x")
+	require.True(t, ok)
+	assert.Equal(t, 3, inner.ID)
+	assert.Equal(t, 3, inner.Value.Int)
+
+	// неизвестный идентификатор не подменяется поиском по имени
+	assert.Error(t, sn.Apply(events.VarChanged{ID: 7, Name: "x", Value: runtime.NewIntValue(0)}, 1))
+
+	// идентификаторы сохраняются при сериализации снимка
+	dto, err := MarshalSnapshot(sn)
+	require.NoError(t, err)
+	restored, err := UnmarshalSnapshot(dto)
+	require.NoError(t, err)
+	v, ok := restored.GetVariable("x")
+	require.True(t, ok)
+	assert.Equal(t, 3, v.ID)
+	arr, ok := restored.GetArray("a")
+	require.True(t, ok)
+	assert.Equal(t, 2, arr.ID)
+}
+
+func TestSnapshotScopeInfoAndDestroyed(t *testing.T) {
+	owner := converter.Location{Line: 2, EndLine: 4}
+	loop := runtime.ScopeInfo{Kind: runtime.ScopeFor, Owner: &owner, StartLine: 2, EndLine: 4}
+
+	sn := NewSnapshot()
+	for _, e := range []events.Event{
+		events.FunctionCall{Name: "main"},
+		events.EnterScope{ScopeInfo: runtime.ScopeInfo{Kind: runtime.ScopeFunction}},
+		events.EnterScope{ScopeInfo: loop},
+		events.DeclareVar{ID: 1, Name: "i", Type: runtime.TypeInt},
+	} {
+		require.NoError(t, sn.Apply(e, 0))
+	}
+	assert.Equal(t, runtime.ScopeGlobal, sn.GlobalScope.Kind)
+	assert.Equal(t, loop, sn.GetCurrentFrame().GetCurrentScope().ScopeInfo)
+
+	sn.NewStep()
+	destroyed, err := sn.Record(events.VariableDestroyed{ID: 1, Name: "i"}, 1)
+	require.NoError(t, err)
+	exit, err := sn.Record(events.ExitScope{Kind: runtime.ScopeFor}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []Destroyed{{Frame: 1, ID: 1, Name: "i"}}, sn.Destroyed)
+	assert.Equal(t, runtime.ScopeFunction, sn.GetCurrentFrame().GetCurrentScope().Kind)
+
+	// вид области видимости и уничтоженные переменные сохраняются при сериализации
+	dto, err := MarshalSnapshot(sn)
+	require.NoError(t, err)
+	restored, err := UnmarshalSnapshot(dto)
+	require.NoError(t, err)
+	assert.Equal(t, sn.Destroyed, restored.Destroyed)
+	assert.Equal(t, runtime.ScopeGlobal, restored.GlobalScope.Kind)
+	assert.Equal(t, runtime.ScopeFunction, restored.GetCurrentFrame().GetCurrentScope().Kind)
+
+	require.NoError(t, sn.Unapply(exit))
+	require.NoError(t, sn.Unapply(destroyed))
+	assert.Empty(t, sn.Destroyed)
+	assert.Equal(t, loop, sn.GetCurrentFrame().GetCurrentScope().ScopeInfo)
+	_, ok := sn.GetVariable("i")
+	assert.True(t, ok)
+}
+
+func TestSnapshotUnapplyRestoresPreviousState(t *testing.T) {
+	one, two := runtime.NewIntValue(1), runtime.NewIntValue(2)
+	evs := []events.Event{
+		events.DeclareVar{Name: "g", Type: runtime.TypeInt, IsGlobal: true},
+		events.FunctionCall{Name: "main"},
+		events.EnterScope{},
+		events.LineChanged{Line: 3},
+		events.DeclareVar{Name: "x", Type: runtime.TypeInt},
+		events.VarChanged{Name: "x", Value: one},
+		events.VarChanged{Name: "g", Value: two},
+		events.DeclareArray2D{Name: "m", Type: runtime.TypeInt, Size1: 1, Size2: 2, Value: []runtime.Array{{Values: makeArrayElements([]int{1, 2})}}},
+		events.Array2DElementChanged{Name: "m", Ind1: 0, Ind2: 1, Value: one},
+		events.HeapAlloc{Address: 0x18000, Size: 8, Line: 4},
+		events.HeapBlockTyped{Address: 0x18000, Type: runtime.TypeInt},
+		events.HeapElementChanged{Address: 0x18000, Ind: 0, Value: two},
+		events.VarRead{Name: "x", Value: one, Use: events.ReadIndex},
+		events.Array2DElementRead{Name: "m", Ind1: 0, Ind2: 1, Value: one, Use: events.ReadCompare},
+		events.HeapElementRead{Address: 0x18000, Ind: 0, Value: two, Use: events.ReadArithmetic},
+		events.ArrayIndexed{Variable: "x", Name: "m", Dimension: 1, Index: 1},
+		events.RangeChanged{Loc: converter.Location{Line: 5, Column: 2, EndLine: 5, EndColumn: 9}},
+		events.ExprEvaluated{Loc: converter.Location{Line: 5, Column: 2, EndLine: 5, EndColumn: 3}, Value: one},
+		events.FunctionCall{Name: "f"},
+		events.EnterScope{},
+		events.DeclareVar{Name: "y", Type: runtime.TypeInt, Value: &two},
+		events.VarRead{Name: "y", Value: two, Use: events.ReadValue},
+		events.ExitScope{},
+		events.FunctionReturn{Name: "f", ReturnValue: &two},
+		events.HeapFree{Address: 0x18000},
+		events.RuntimeError{Message: "runtime error: division by zero"},
+		events.ExitScope{},
+	}
+
+	sn := NewSnapshot()
+	states := make([]string, 0, len(evs))
+	recorded := make([]events.Event, 0, len(evs))
+	for ind, e := range evs {
+		data, err := json.Marshal(sn)
+		require.NoError(t, err)
+		states = append(states, string(data))
+
+		r, err := sn.Record(e, ind)
+		require.NoError(t, err, "event %d", ind)
+		recorded = append(recorded, r)
+	}
+
+	for ind := len(recorded) - 1; ind >= 0; ind-- {
+		require.NoError(t, sn.Unapply(recorded[ind]), "event %d", ind)
+		data, err := json.Marshal(sn)
+		require.NoError(t, err)
+		assert.JSONEq(t, states[ind], string(data), "before event %d", ind)
+	}
+
+	_, ok := sn.Heap.Find(0x18000)
+	assert.False(t, ok)
+}
@@ -5,6 +5,10 @@ import "github.com/Oleja123/code-vizualization/interpreter-service/internal/doma
 type StepDTO struct {
 	Events     []events.EventDTO `json:"events"`
 	StepNumber int               `json:"stepNumber"`
+	Depth      int               `json:"depth"`
+	Function   string            `json:"function,omitempty"`
+	Line       int               `json:"line"`
+	Kind       Kind              `json:"kind"`
 }
 
 func MarshalStep(s Step) (StepDTO, error) {
@@ -20,6 +24,10 @@ func MarshalStep(s Step) (StepDTO, error) {
 	return StepDTO{
 		Events:     eventDTOs,
 		StepNumber: s.StepNumber,
+		Depth:      s.Depth,
+		Function:   s.Function,
+		Line:       s.Line,
+		Kind:       s.Kind,
 	}, nil
 }
 
@@ -36,5 +44,9 @@ func UnmarshalStep(dto StepDTO) (Step, error) {
 	return Step{
 		Events:     eventsSlice,
 		StepNumber: dto.StepNumber,
+		Depth:      dto.Depth,
+		Function:   dto.Function,
+		Line:       dto.Line,
+		Kind:       dto.Kind,
 	}, nil
 }
@@ -0,0 +1,66 @@
+package step
+
+import "fmt"
+
+// Action - команда навигации по шагам
+type Action string
+
+const (
+	ActionStepInto Action = "step_into" // следующий шаг, в том числе внутри вызываемой функции
+	ActionStepOver Action = "step_over" // следующий шаг в той же функции, вызовы выполняются целиком
+	ActionStepOut  Action = "step_out"  // первый шаг после возврата из текущей функции
+	ActionNextLine Action = "next_line" // следующий шаг на заданной строке
+	ActionPrevLine Action = "prev_line" // предыдущий шаг на заданной строке
+	ActionRunToEnd Action = "run_to_end"
+)
+
+// Actions - все команды навигации
+var Actions = []Action{ActionStepInto, ActionStepOver, ActionStepOut, ActionNextLine, ActionPrevLine, ActionRunToEnd}
+
+// Navigate возвращает индекс шага, к которому ведет команда action из шага from.
+// line используется командами next_line и prev_line. Если подходящего шага нет,
+// step_into, step_over и step_out останавливаются на последнем шаге,
+// а поиск строки возвращает ошибку
+func Navigate(steps []Step, from int, action Action, line int) (int, error) {
+	if from < 0 || from >= len(steps) {
+		return 0, fmt.Errorf("invalid step index: %d (total steps: %d)", from, len(steps))
+	}
+	last := len(steps) - 1
+	depth := steps[from].Depth
+
+	switch action {
+	case ActionStepInto:
+		return min(from+1, last), nil
+	case ActionStepOver:
+		return findForward(steps, from, last, func(s Step) bool { return s.Depth <= depth }), nil
+	case ActionStepOut:
+		return findForward(steps, from, last, func(s Step) bool { return s.Depth < depth }), nil
+	case ActionNextLine:
+		ind := findForward(steps, from, -1, func(s Step) bool { return s.Line == line })
+		if ind < 0 {
+			return 0, fmt.Errorf("line %d is not reached after step %d", line, from)
+		}
+		return ind, nil
+	case ActionPrevLine:
+		for ind := from - 1; ind >= 0; ind-- {
+			if steps[ind].Line == line {
+				return ind, nil
+			}
+		}
+		return 0, fmt.Errorf("line %d is not reached before step %d", line, from)
+	case ActionRunToEnd:
+		return last, nil
+	default:
+		return 0, fmt.Errorf("unknown navigation action: %s", action)
+	}
+}
+
+// findForward возвращает первый шаг после from, удовлетворяющий условию, или notFound
+func findForward(steps []Step, from, notFound int, match func(Step) bool) int {
+	for ind := from + 1; ind < len(steps); ind++ {
+		if match(steps[ind]) {
+			return ind
+		}
+	}
+	return notFound
+}
@@ -0,0 +1,60 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// main (строки 7-9) вызывает f (строки 2-3) из строки 8
+var navigationSteps = []Step{
+	{Depth: 1, Function: "main", Line: 7, Kind: KindDeclaration},
+	{Depth: 2, Function: "f", Line: 2, Kind: KindDeclaration},
+	{Depth: 2, Function: "f", Line: 3, Kind: KindReturn},
+	{Depth: 1, Function: "main", Line: 8, Kind: KindCallReturn},
+	{Depth: 1, Function: "main", Line: 9, Kind: KindReturn},
+	{Depth: 0, Line: -1, Kind: KindProgramEnd},
+}
+
+func TestNavigate(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   int
+		action Action
+		line   int
+		want   int
+	}{
+		{"step into enters call", 0, ActionStepInto, 0, 1},
+		{"step into stays on last step", 5, ActionStepInto, 0, 5},
+		{"step over skips call", 0, ActionStepOver, 0, 3},
+		{"step over inside function", 1, ActionStepOver, 0, 2},
+		{"step out returns to caller", 1, ActionStepOut, 0, 3},
+		{"step out of main ends program", 3, ActionStepOut, 0, 5},
+		{"next line", 0, ActionNextLine, 9, 4},
+		{"previous line", 4, ActionPrevLine, 2, 1},
+		{"run to end", 1, ActionRunToEnd, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Navigate(navigationSteps, tt.from, tt.action, tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNavigateErrors(t *testing.T) {
+	_, err := Navigate(navigationSteps, 6, ActionStepInto, 0)
+	assert.EqualError(t, err, "invalid step index: 6 (total steps: 6)")
+
+	_, err = Navigate(navigationSteps, 4, ActionNextLine, 2)
+	assert.EqualError(t, err, "line 2 is not reached after step 4")
+
+	_, err = Navigate(navigationSteps, 1, ActionPrevLine, 9)
+	assert.EqualError(t, err, "line 9 is not reached before step 1")
+
+	_, err = Navigate(navigationSteps, 0, Action("jump"), 0)
+	assert.EqualError(t, err, "unknown navigation action: jump")
+}
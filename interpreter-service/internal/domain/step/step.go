@@ -2,7 +2,28 @@ package step
 
 import "github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
 
+// Kind - вид конструкции, на которой остановлено выполнение после шага
+type Kind string
+
+const (
+	KindDeclaration   Kind = "declaration"
+	KindExpression    Kind = "expression"
+	KindCondition     Kind = "condition" // условие if, while, do-while или for
+	KindReturn        Kind = "return"
+	KindBreak         Kind = "break"
+	KindContinue      Kind = "continue"
+	KindCallReturn    Kind = "call_return"   // возврат из функции в строку вызова
+	KindSubexpression Kind = "subexpression" // вычислено подвыражение (пошаговое вычисление выражений)
+	KindProgramEnd    Kind = "program_end"   // возврат из main, exit или отчет об утечках
+	KindError         Kind = "error"         // выполнение прервано ошибкой
+)
+
+// Step - шаг выполнения: события и положение, в котором выполнение останавливается после них
 type Step struct {
 	Events     []events.Event `json:"events"`
 	StepNumber int            `json:"stepNumber"`
+	Depth      int            `json:"depth"`              // число кадров вызовов функций (main - 1)
+	Function   string         `json:"function,omitempty"` // функция текущего кадра
+	Line       int            `json:"line"`
+	Kind       Kind           `json:"kind"`
 }
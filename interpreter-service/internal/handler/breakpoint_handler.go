@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/breakpoints"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// BreakpointRequest - поиск шага, на котором срабатывает точка останова или точка наблюдения,
+// от шага Step в направлении Direction (по умолчанию вперед)
+type BreakpointRequest struct {
+	Code            string                   `json:"code"`
+	Step            int                      `json:"step"`
+	Direction       breakpoints.Direction    `json:"direction,omitempty"`
+	Breakpoints     []breakpoints.Breakpoint `json:"breakpoints,omitempty"`
+	Watchpoints     []string                 `json:"watchpoints,omitempty"` // переменные и элементы массивов, например sum или a[3]
+	ExpressionSteps bool                     `json:"expression_steps,omitempty"`
+	ReadEvents      bool                     `json:"read_events,omitempty"`
+	IndexMarkers    bool                     `json:"index_markers,omitempty"`
+	Watch           []string                 `json:"watch,omitempty"`
+}
+
+// BreakpointResponse - снимок состояния на найденном шаге и сработавшая точка (nil, если ни одна не сработала)
+type BreakpointResponse struct {
+	SnapshotResponse
+	Hit *breakpoints.Hit `json:"hit,omitempty"`
+}
+
+func NewBreakpointHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, SnapshotResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req BreakpointRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "code is required"})
+			return
+		}
+
+		if req.Step < 0 {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "step must be non-negative"})
+			return
+		}
+
+		if len(req.Breakpoints) == 0 && len(req.Watchpoints) == 0 {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "breakpoints or watchpoints are required"})
+			return
+		}
+
+		if req.Direction == "" {
+			req.Direction = breakpoints.DirectionForward
+		}
+		if req.Direction != breakpoints.DirectionForward && req.Direction != breakpoints.DirectionBackward {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "direction must be forward or backward"})
+			return
+		}
+
+		searcher, err := breakpoints.NewSearcher(req.Breakpoints, req.Watchpoints)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps, readEvents: req.ReadEvents, indexMarkers: req.IndexMarkers})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		target, hit, err := searcher.Find(exec.steps, exec.stepBegin, req.Step, req.Direction)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		resp, reqErr := snapshotAt(exec, target, req.Watch)
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+		writeJSON(w, http.StatusOK, BreakpointResponse{SnapshotResponse: resp, Hit: hit})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/breakpoints"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const breakpointCode = `int main() {
+	int sum = 0;
+	for (int i = 0; i < 10; i++) {
+		sum = sum + i;
+	}
+	return sum;
+}`
+
+func findBreakpoint(t *testing.T, body BreakpointRequest) (int, BreakpointResponse) {
+	t.Helper()
+
+	h := NewBreakpointHandler(config.Default(), nil)
+
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/breakpoints", bytes.NewReader(payload))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	var resp BreakpointResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	return rr.Code, resp
+}
+
+func TestNewBreakpointHandler_ConditionalBreakpoint(t *testing.T) {
+	code, resp := findBreakpoint(t, BreakpointRequest{
+		Code:        breakpointCode,
+		Step:        0,
+		Breakpoints: []breakpoints.Breakpoint{{Line: 4, Condition: "i == 7"}},
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, resp.Success)
+	require.NotNil(t, resp.Hit)
+	assert.Equal(t, breakpoints.HitBreakpoint, resp.Hit.Kind)
+	assert.Equal(t, 4, resp.Snapshot.GetCurrentLine())
+
+	assert.Equal(t, "i == 7", resp.Hit.Breakpoint.Condition)
+
+	// в обратном направлении от найденного шага условие больше не выполняется
+	code, back := findBreakpoint(t, BreakpointRequest{
+		Code:        breakpointCode,
+		Step:        resp.CurrentStep,
+		Direction:   breakpoints.DirectionBackward,
+		Breakpoints: []breakpoints.Breakpoint{{Line: 4, Condition: "i == 7"}},
+	})
+	require.Equal(t, http.StatusOK, code)
+	assert.Nil(t, back.Hit)
+	assert.Equal(t, 0, back.CurrentStep)
+}
+
+func TestNewBreakpointHandler_Watchpoint(t *testing.T) {
+	code, resp := findBreakpoint(t, BreakpointRequest{Code: breakpointCode, Step: 2, Watchpoints: []string{"sum"}})
+	require.Equal(t, http.StatusOK, code)
+	require.NotNil(t, resp.Hit)
+	assert.Equal(t, "sum", resp.Hit.Watch)
+	require.NotNil(t, resp.Hit.NewValue)
+	assert.Equal(t, 1, resp.Hit.NewValue.Int)
+}
+
+func TestNewBreakpointHandler_Errors(t *testing.T) {
+	code, resp := findBreakpoint(t, BreakpointRequest{Code: breakpointCode})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "breakpoints or watchpoints are required", resp.Error)
+
+	code, resp = findBreakpoint(t, BreakpointRequest{Code: breakpointCode, Direction: "up", Watchpoints: []string{"sum"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "direction must be forward or backward", resp.Error)
+
+	code, resp = findBreakpoint(t, BreakpointRequest{Code: breakpointCode, Breakpoints: []breakpoints.Breakpoint{{Line: 4, Condition: "sum = 1"}}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, resp.Error, "assignment is not allowed")
+
+	code, _ = findBreakpoint(t, BreakpointRequest{Code: breakpointCode, Step: 1000, Watchpoints: []string{"sum"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
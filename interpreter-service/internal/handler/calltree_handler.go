@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/calltree"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// CallTreeRequest - запрос дерева вызовов функций программы
+type CallTreeRequest struct {
+	Code            string `json:"code"`
+	ExpressionSteps bool   `json:"expression_steps,omitempty"`
+}
+
+// CallTreeResponse - дерево вызовов с корнем в main и общее число вызовов
+type CallTreeResponse struct {
+	Success    bool           `json:"success"`
+	Error      string         `json:"error,omitempty"`
+	StepsCount int            `json:"steps_count,omitempty"`
+	Calls      int            `json:"calls,omitempty"`
+	Root       *calltree.Node `json:"root,omitempty"`
+}
+
+func NewCallTreeHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, CallTreeResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req CallTreeRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, CallTreeResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, CallTreeResponse{Success: false, Error: "code is required"})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, CallTreeResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		root, calls, err := calltree.Build(exec.steps, exec.stepBegin)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, CallTreeResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, CallTreeResponse{Success: true, StepsCount: max(len(exec.steps)-exec.stepBegin, 0), Calls: calls, Root: root})
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCallTreeHandler_Recursion(t *testing.T) {
+	code := `void hanoi(int n, int from, int to) {
+	if (n == 0) {
+		return;
+	}
+	hanoi(n - 1, from, 6 - from - to);
+	hanoi(n - 1, 6 - from - to, to);
+}
+int main() {
+	hanoi(2, 1, 3);
+	return 0;
+}`
+
+	rr := doJSON(t, NewCallTreeHandler(config.Default(), nil), http.MethodPost, "/calltree", CallTreeRequest{Code: code})
+	resp := decodeJSON[CallTreeResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, resp.Success)
+	require.NotNil(t, resp.Root)
+
+	// hanoi(2) вызывает hanoi(1) дважды, каждый hanoi(1) - hanoi(0) дважды
+	assert.Equal(t, 8, resp.Calls)
+	assert.Equal(t, "main", resp.Root.Function)
+	require.Len(t, resp.Root.Children, 1)
+	top := resp.Root.Children[0]
+	require.Len(t, top.Arguments, 3)
+	assert.Equal(t, "n", top.Arguments[0].Name)
+	assert.Equal(t, 2, top.Arguments[0].Value.Int)
+	assert.Equal(t, 3, top.Arguments[2].Value.Int)
+	assert.Equal(t, uint32(9), top.Loc.Line)
+	assert.Nil(t, top.ReturnValue)
+	assert.True(t, top.Returned)
+	require.Len(t, top.Children, 2)
+	assert.Equal(t, 2, top.Children[1].Arguments[1].Value.Int)
+	assert.Len(t, top.Children[1].Children, 2)
+	assert.Less(t, top.StepTo, resp.StepsCount)
+}
+
+func TestNewCallTreeHandler_Validation(t *testing.T) {
+	rr := doJSON(t, NewCallTreeHandler(config.Default(), nil), http.MethodGet, "/calltree", CallTreeRequest{Code: "int main() { return 0; }"})
+	resp := decodeJSON[CallTreeResponse](t, rr)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.False(t, resp.Success)
+
+	rr = doJSON(t, NewCallTreeHandler(config.Default(), nil), http.MethodPost, "/calltree", CallTreeRequest{Code: " "})
+	resp = decodeJSON[CallTreeResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "code is required", resp.Error)
+
+	rr = doJSON(t, NewCallTreeHandler(config.Default(), nil), http.MethodPost, "/calltree", map[string]any{"code": "int main() { return 0; }", "depth": 2})
+	resp = decodeJSON[CallTreeResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "invalid request body")
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/complexity"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/limitations"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/Oleja123/code-vizualization/semantic-analyzer-service/pkg/validator"
+)
+
+// ComplexityRequest - запрос оценки сложности: код с размером входных данных {{n}} выполняется
+// для каждого размера из Sizes, метрика Metric приближается классами роста
+type ComplexityRequest struct {
+	Code   string `json:"code"`
+	Sizes  []int  `json:"sizes"`
+	Metric string `json:"metric,omitempty"`
+}
+
+// ComplexityResponse - выполнения по размерам, приближения всеми классами роста и лучший класс
+type ComplexityResponse struct {
+	Success bool              `json:"success"`
+	Error   string            `json:"error,omitempty"`
+	Metric  complexity.Metric `json:"metric,omitempty"`
+	Runs    []complexity.Run  `json:"runs,omitempty"`
+	Fits    []complexity.Fit  `json:"fits,omitempty"`
+	Best    complexity.Class  `json:"best,omitempty"`
+}
+
+func NewComplexityHandler(cfg *configinfra.Config) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, ComplexityResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req ComplexityRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: "code is required"})
+			return
+		}
+		if !strings.Contains(req.Code, complexity.Placeholder) {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: "code must contain " + complexity.Placeholder})
+			return
+		}
+		if err := complexity.ValidateSizes(req.Sizes); err != nil {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: err.Error()})
+			return
+		}
+		if err := complexity.ValidateBounds(req.Sizes, cfg.ComplexityMaxSizes, cfg.ComplexityMaxN); err != nil {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: err.Error()})
+			return
+		}
+		metric, err := complexity.ParseMetric(req.Metric)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		// {{n}} подставляется в текст программы и может попасть в размер массива, идентификатор или строку,
+		// поэтому семантическая проверка выполняется для каждого размера. Удаленная компиляция выполняется
+		// один раз, для наибольшего размера
+		largest := slices.Max(req.Sizes)
+		local := validator.New()
+		programs := make([]*converter.Program, len(req.Sizes))
+		for ind, n := range req.Sizes {
+			sizeVal := local
+			if n == largest {
+				sizeVal = val
+			}
+			program, reqErr := parseProgram(sizeVal, complexity.Substitute(req.Code, n))
+			if reqErr != nil {
+				writeJSON(w, reqErr.status, ComplexityResponse{Success: false, Error: fmt.Sprintf("n = %d: %s", n, reqErr.message)})
+				return
+			}
+			programs[ind] = program
+		}
+
+		runs := make([]complexity.Run, len(req.Sizes))
+		for ind, n := range req.Sizes {
+			runner := interpreter.NewInterpreterWithLimits(cfg.ComplexityMaxAllocatedElements, cfg.ComplexityMaxSteps)
+			_, _, _, err := runner.ExecuteProgram(programs[ind])
+			var limitErr limitations.ErrLimitExceeded
+			if err != nil && !errors.As(err, &limitErr) {
+				writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: fmt.Sprintf("n = %d: error: %s", n, err.Error())})
+				return
+			}
+
+			ops := runner.Operations()
+			// выполнение, прерванное лимитом, учитывается с шагами и операциями до прерывания
+			runs[ind] = complexity.Run{N: n, Operations: ops, Total: ops.Total(), Steps: runner.MainSteps(), Truncated: err != nil}
+		}
+
+		fits, best, err := complexity.Estimate(runs, metric)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ComplexityResponse{Success: false, Error: err.Error(), Metric: metric, Runs: runs})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ComplexityResponse{Success: true, Metric: metric, Runs: runs, Fits: fits, Best: best})
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/complexity"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const complexityHandlerCode = `int main() {
+	int n = {{n}};
+	int s = 0;
+	for (int i = 0; i < n; i++) {
+		s += i;
+	}
+	return s;
+}`
+
+// complexityTestConfig уменьшает бюджет шагов, чтобы проверка усеченных прогонов оставалась быстрой
+func complexityTestConfig() *config.Config {
+	cfg := config.Default()
+	cfg.ComplexityMaxSteps = 10000
+	return cfg
+}
+
+func TestNewComplexityHandler_Linear(t *testing.T) {
+	rr := doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{5, 10, 20, 40}})
+	resp := decodeJSON[ComplexityResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code, resp.Error)
+	require.True(t, resp.Success)
+
+	assert.Equal(t, complexity.MetricTotal, resp.Metric)
+	assert.Equal(t, complexity.ClassLinear, resp.Best)
+	require.Len(t, resp.Runs, 4)
+	assert.Equal(t, 40, resp.Runs[3].N)
+	assert.Equal(t, 41, resp.Runs[3].Operations.Comparisons)
+	assert.Equal(t, resp.Runs[3].Operations.Total(), resp.Runs[3].Total)
+	assert.Greater(t, resp.Runs[3].Steps, resp.Runs[2].Steps)
+	assert.NotEmpty(t, resp.Fits)
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{5, 10, 20}, Metric: "array_accesses"})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, complexity.ClassConstant, resp.Best)
+}
+
+func TestNewComplexityHandler_Errors(t *testing.T) {
+	rr := doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodGet, "/complexity", ComplexityRequest{Code: complexityHandlerCode})
+	resp := decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.False(t, resp.Success)
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: "int main() { return 0; }", Sizes: []int{1, 2, 3}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "{{n}}")
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{1, 2}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "at least 3 sizes")
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{1, 2, 3}, Metric: "swaps"})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "unknown metric")
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "too many sizes: 11, at most 10 are allowed", resp.Error)
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{1, 2, 1000000000}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "size 1000000000 exceeds the limit 100000", resp.Error)
+	assert.Empty(t, resp.Runs)
+
+	// из трех выполнений лимит шагов не превышает только одно
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{10, 10000, 20000}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "2 of 3 runs hit the limits")
+	require.Len(t, resp.Runs, 3)
+	assert.True(t, resp.Runs[2].Truncated)
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: "int main() { return {{n}} }", Sizes: []int{1, 2, 3}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "n = 1: parse error")
+
+	// программа проверяется для каждого размера, а не только для наибольшего
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: "enum { LIMIT = 2147483649 - {{n}} };\nint main() { return LIMIT; }", Sizes: []int{1, 2, 3}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "n = 1: semantic error")
+	assert.Empty(t, resp.Runs)
+
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: "int main() { int x = {{n}}; return x / 0; }", Sizes: []int{1, 2, 3}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "n = 1: error")
+}
+
+func TestNewComplexityHandler_TruncatedRun(t *testing.T) {
+	// для наибольшего размера выполнение превышает лимит шагов и не участвует в приближении
+	rr := doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: complexityHandlerCode, Sizes: []int{10, 100, 1000, 10000}})
+	resp := decodeJSON[ComplexityResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code, resp.Error)
+	require.Len(t, resp.Runs, 4)
+	assert.False(t, resp.Runs[2].Truncated)
+	assert.True(t, resp.Runs[3].Truncated)
+	assert.Equal(t, 10000, resp.Runs[3].Steps)
+	assert.Equal(t, complexity.ClassLinear, resp.Best)
+
+	// шаги завершенных и прерванных выполнений считаются одинаково, с начала main
+	rr = doJSON(t, NewComplexityHandler(complexityTestConfig()), http.MethodPost, "/complexity", ComplexityRequest{Code: "int g = 0;\n" + complexityHandlerCode, Sizes: []int{10, 100, 1000, 10000}})
+	resp = decodeJSON[ComplexityResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code, resp.Error)
+	assert.True(t, resp.Runs[3].Truncated)
+	assert.Equal(t, 10000-1, resp.Runs[3].Steps)
+}
+
+func TestNewComplexityHandler_QuadraticWithDefaultLimits(t *testing.T) {
+	code := `int main() {
+	int n = {{n}};
+	int s = 0;
+	for (int i = 0; i < n; i++) {
+		for (int j = 0; j < n; j++) {
+			s += j;
+		}
+	}
+	return s;
+}`
+
+	rr := doJSON(t, NewComplexityHandler(config.Default()), http.MethodPost, "/complexity", ComplexityRequest{Code: code, Sizes: []int{10, 20, 40, 80}, Metric: "comparisons"})
+	resp := decodeJSON[ComplexityResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code, resp.Error)
+	for _, r := range resp.Runs {
+		assert.False(t, r.Truncated)
+	}
+	assert.Equal(t, complexity.ClassQuadratic, resp.Best)
+}
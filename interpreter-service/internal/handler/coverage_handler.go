@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/coverage"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// defaultSourceFile - имя файла программы в отчетах LCOV и pprof
+const defaultSourceFile = "main.c"
+
+// CoverageRequest - запрос покрытия кода одним запуском программы. Format "lcov" возвращает
+// отчет в формате LCOV для файла SourceFile вместо JSON
+type CoverageRequest struct {
+	Code       string `json:"code"`
+	Format     string `json:"format,omitempty"`
+	SourceFile string `json:"source_file,omitempty"`
+}
+
+// CoverageResponse - отчет о покрытии в формате JSON
+type CoverageResponse struct {
+	Success  bool             `json:"success"`
+	Error    string           `json:"error,omitempty"`
+	Coverage *coverage.Report `json:"coverage,omitempty"`
+}
+
+func NewCoverageHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, CoverageResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req CoverageRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, CoverageResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, CoverageResponse{Success: false, Error: "code is required"})
+			return
+		}
+		if req.Format != "" && req.Format != "json" && req.Format != "lcov" {
+			writeJSON(w, http.StatusBadRequest, CoverageResponse{Success: false, Error: "unknown format: " + req.Format})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{branchEvents: true})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, CoverageResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		// код уже разобран при выполнении, AST нужен для строк и условий, которые не выполнялись
+		program, parseErr := converter.New().ParseToAST(req.Code)
+		if parseErr != nil {
+			writeJSON(w, http.StatusBadRequest, CoverageResponse{Success: false, Error: "parse error: " + parseErr.Error()})
+			return
+		}
+
+		report, err := coverage.Build(program, exec.steps)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, CoverageResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		if req.Format == "lcov" {
+			source := req.SourceFile
+			if source == "" {
+				source = defaultSourceFile
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(report.LCOV(source)))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, CoverageResponse{Success: true, Coverage: report})
+	}
+}
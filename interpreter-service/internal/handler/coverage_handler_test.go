@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const coverageHandlerCode = `int main() {
+	int a = 5;
+	if (a % 2 == 0) {
+		a = a / 2;
+	} else {
+		a = 3 * a + 1;
+	}
+	return a;
+}`
+
+func TestNewCoverageHandler_JSON(t *testing.T) {
+	rr := doJSON(t, NewCoverageHandler(config.Default(), nil), http.MethodPost, "/coverage", CoverageRequest{Code: coverageHandlerCode})
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp := decodeJSON[CoverageResponse](t, rr)
+	require.True(t, resp.Success)
+	require.NotNil(t, resp.Coverage)
+
+	assert.Equal(t, []int{4}, resp.Coverage.Uncovered)
+	require.Len(t, resp.Coverage.Branches, 1)
+	assert.Equal(t, 0, resp.Coverage.Branches[0].True)
+	assert.Equal(t, 1, resp.Coverage.Branches[0].False)
+	assert.Equal(t, 1, resp.Coverage.BranchesHit)
+}
+
+func TestNewCoverageHandler_LCOV(t *testing.T) {
+	rr := doJSON(t, NewCoverageHandler(config.Default(), nil), http.MethodPost, "/coverage", CoverageRequest{Code: coverageHandlerCode, Format: "lcov", SourceFile: "collatz.c"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/plain")
+
+	body := rr.Body.String()
+	assert.Contains(t, body, "SF:collatz.c\n")
+	assert.Contains(t, body, "BRDA:3,0,0,0\n")
+	assert.Contains(t, body, "DA:4,0\n")
+	assert.Contains(t, body, "DA:6,1\n")
+	assert.Contains(t, body, "LF:5\nLH:4\n")
+}
+
+func TestNewCoverageHandler_Validation(t *testing.T) {
+	rr := doJSON(t, NewCoverageHandler(config.Default(), nil), http.MethodGet, "/coverage", CoverageRequest{Code: coverageHandlerCode})
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	rr = doJSON(t, NewCoverageHandler(config.Default(), nil), http.MethodPost, "/coverage", CoverageRequest{Code: ""})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "code is required", decodeJSON[CoverageResponse](t, rr).Error)
+
+	rr = doJSON(t, NewCoverageHandler(config.Default(), nil), http.MethodPost, "/coverage", CoverageRequest{Code: coverageHandlerCode, Format: "xml"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, decodeJSON[CoverageResponse](t, rr).Error, "unknown format")
+
+	rr = doJSON(t, NewCoverageHandler(config.Default(), nil), http.MethodPost, "/coverage", map[string]any{"code": coverageHandlerCode, "branches": true})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, decodeJSON[CoverageResponse](t, rr).Error, "invalid request body")
+}
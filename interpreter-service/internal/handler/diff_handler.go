@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/diff"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// DiffRequest - запрос различий снимков на шагах From и To (To может быть раньше From)
+type DiffRequest struct {
+	Code            string `json:"code"`
+	From            int    `json:"from"`
+	To              int    `json:"to"`
+	ExpressionSteps bool   `json:"expression_steps,omitempty"`
+}
+
+// DiffResponse - различия снимков: измененные значения, вошедшие и покинутые кадры и области видимости,
+// появившиеся и исчезнувшие объявления, выделенные и освобожденные блоки кучи и перемещение строки
+type DiffResponse struct {
+	Success    bool       `json:"success"`
+	Error      string     `json:"error,omitempty"`
+	StepsCount int        `json:"steps_count,omitempty"`
+	Diff       *diff.Diff `json:"diff,omitempty"`
+}
+
+func NewDiffHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, DiffResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req DiffRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, DiffResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, DiffResponse{Success: false, Error: "code is required"})
+			return
+		}
+
+		if req.From < 0 || req.To < 0 {
+			writeJSON(w, http.StatusBadRequest, DiffResponse{Success: false, Error: "from and to must be non-negative"})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, DiffResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		ed := eventdispatcher.NewEventDispatcher(exec.stepBegin)
+		ed.Steps = exec.steps
+		ed.SetCheckpoints(exec.checkpoints)
+		d, err := diff.Compare(ed, exec.stepBegin, req.From, req.To)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, DiffResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, DiffResponse{Success: true, StepsCount: max(len(exec.steps)-exec.stepBegin, 0), Diff: &d})
+	}
+}
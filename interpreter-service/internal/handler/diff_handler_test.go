@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiffHandler_WholeProgram(t *testing.T) {
+	// последний шаг - завершение программы, предпоследний - return sum
+	count := snapshotResponse(t, rangeCode, 0).StepsCount
+	last := count - 2
+
+	rr := doJSON(t, NewDiffHandler(config.Default(), nil), http.MethodPost, "/diff", DiffRequest{Code: rangeCode, From: 0, To: last})
+	resp := decodeJSON[DiffResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, resp.Success)
+	require.NotNil(t, resp.Diff)
+	assert.Equal(t, 0, resp.Diff.From)
+	assert.Equal(t, last, resp.Diff.To)
+	assert.Equal(t, count, resp.StepsCount)
+	assert.Equal(t, 6, resp.Diff.Line.To)
+
+	// i объявлена в области видимости цикла, которая закрыта к концу программы
+	require.Len(t, resp.Diff.Declared, 1)
+	assert.Equal(t, "sum", resp.Diff.Declared[0].Name)
+	assert.Equal(t, "main", resp.Diff.Declared[0].Function)
+
+	back := requestDiffOK(t, DiffRequest{Code: rangeCode, From: last, To: 0})
+	assert.Equal(t, resp.Diff.Declared, back.Diff.Removed)
+	assert.Equal(t, 6, back.Diff.Line.From)
+}
+
+func requestDiffOK(t *testing.T, body DiffRequest) DiffResponse {
+	t.Helper()
+
+	rr := doJSON(t, NewDiffHandler(config.Default(), nil), http.MethodPost, "/diff", body)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return decodeJSON[DiffResponse](t, rr)
+}
+
+func TestNewDiffHandler_Validation(t *testing.T) {
+	rr := doJSON(t, NewDiffHandler(config.Default(), nil), http.MethodPost, "/diff", DiffRequest{Code: rangeCode, From: -1, To: 2})
+	resp := decodeJSON[DiffResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.False(t, resp.Success)
+
+	rr = doJSON(t, NewDiffHandler(config.Default(), nil), http.MethodPost, "/diff", DiffRequest{Code: rangeCode, From: 0, To: 1000})
+	resp = decodeJSON[DiffResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "invalid step index")
+
+	rr = doJSON(t, NewDiffHandler(config.Default(), nil), http.MethodPost, "/diff", map[string]any{"code": rangeCode, "unknown": 1})
+	resp = decodeJSON[DiffResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "invalid request body")
+
+	rr = doJSON(t, NewDiffHandler(config.Default(), nil), http.MethodPost, "/diff", DiffRequest{From: 0, To: 1})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// doJSON отправляет обработчику h запрос method на path с телом body в формате JSON
+func doJSON(t *testing.T, h http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+// decodeJSON разбирает JSON-ответ обработчика
+func decodeJSON[T any](t *testing.T, rr *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var resp T
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	return resp
+}
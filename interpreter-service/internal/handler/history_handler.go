@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/history"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// HistoryQuery - вид запроса к истории значений
+type HistoryQuery string
+
+const (
+	QueryChanges   HistoryQuery = "changes"    // все изменения значения выражения на шагах from..to
+	QueryFirst     HistoryQuery = "first"      // первый шаг из from..to, на котором выражение истинно
+	QueryLastWrite HistoryQuery = "last_write" // последняя запись объекта не позже шага before
+)
+
+// HistoryRequest - запрос к истории значения выражения по трассе выполнения.
+// To по умолчанию - последний шаг, Before используется запросом last_write
+type HistoryRequest struct {
+	Code            string       `json:"code"`
+	Expression      string       `json:"expression"`
+	Query           HistoryQuery `json:"query,omitempty"`
+	From            int          `json:"from,omitempty"`
+	To              *int         `json:"to,omitempty"`
+	Before          int          `json:"before,omitempty"`
+	ExpressionSteps bool         `json:"expression_steps,omitempty"`
+}
+
+// HistoryResponse - точки истории (changes) или найденный шаг (first, last_write; nil, если шаг не найден)
+type HistoryResponse struct {
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+	StepsCount int             `json:"steps_count,omitempty"`
+	Points     []history.Point `json:"points,omitempty"`
+	Match      *history.Point  `json:"match,omitempty"`
+}
+
+func NewHistoryHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, HistoryResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req HistoryRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, HistoryResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, HistoryResponse{Success: false, Error: "code is required"})
+			return
+		}
+
+		if strings.TrimSpace(req.Expression) == "" {
+			writeJSON(w, http.StatusBadRequest, HistoryResponse{Success: false, Error: "expression is required"})
+			return
+		}
+
+		if req.Query == "" {
+			req.Query = QueryChanges
+		}
+		if req.Query != QueryChanges && req.Query != QueryFirst && req.Query != QueryLastWrite {
+			writeJSON(w, http.StatusBadRequest, HistoryResponse{Success: false, Error: "query must be changes, first or last_write"})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, HistoryResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		ed := eventdispatcher.NewEventDispatcher(exec.stepBegin)
+		ed.Steps = exec.steps
+		ed.SetCheckpoints(exec.checkpoints)
+		tracker := history.NewTracker(ed, exec.stepBegin)
+
+		to := tracker.StepsCount() - 1
+		if req.To != nil {
+			to = *req.To
+		}
+
+		resp := HistoryResponse{Success: true, StepsCount: tracker.StepsCount()}
+		var err error
+		switch req.Query {
+		case QueryChanges:
+			resp.Points, err = tracker.Changes(req.Expression, req.From, to)
+		case QueryFirst:
+			resp.Match, err = tracker.First(req.Expression, req.From, to)
+		case QueryLastWrite:
+			resp.Match, err = tracker.LastWrite(req.Expression, req.Before)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, HistoryResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistoryHandler_Changes(t *testing.T) {
+	rr := doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "sum"})
+	resp := decodeJSON[HistoryResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, resp.Success)
+	assert.Positive(t, resp.StepsCount)
+
+	var writes []int
+	for _, p := range resp.Points {
+		if p.Write {
+			writes = append(writes, p.Value.Int)
+		}
+	}
+	assert.Equal(t, []int{0, 0, 1, 3}, writes)
+	assert.Nil(t, resp.Match)
+}
+
+func TestNewHistoryHandler_FirstAndLastWrite(t *testing.T) {
+	rr := doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "i == 2", Query: QueryFirst})
+	resp := decodeJSON[HistoryResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, resp.Match)
+	first := resp.Match.Step
+
+	rr = doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "sum", Query: QueryLastWrite, Before: first})
+	resp = decodeJSON[HistoryResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, resp.Match)
+	assert.Equal(t, 1, resp.Match.Value.Int)
+	assert.Less(t, resp.Match.Step, first)
+	assert.Equal(t, 4, resp.Match.Line)
+
+	to := 1
+	rr = doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "sum > 100", Query: QueryFirst, To: &to})
+	resp = decodeJSON[HistoryResponse](t, rr)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, resp.Success)
+	assert.Nil(t, resp.Match)
+}
+
+func TestNewHistoryHandler_Validation(t *testing.T) {
+	rr := doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode})
+	resp := decodeJSON[HistoryResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "expression is required", resp.Error)
+
+	rr = doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "sum", Query: "median"})
+	resp = decodeJSON[HistoryResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "query must be")
+
+	rr = doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "sum = 2"})
+	resp = decodeJSON[HistoryResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "assignment is not allowed")
+
+	rr = doJSON(t, NewHistoryHandler(config.Default(), nil), http.MethodPost, "/history", HistoryRequest{Code: rangeCode, Expression: "sum", From: 1000})
+	resp = decodeJSON[HistoryResponse](t, rr)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, resp.Error, "invalid step range")
+}
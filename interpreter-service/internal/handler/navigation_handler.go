@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// NavigationRequest - команда навигации от шага Step. Line задает строку для next_line и prev_line
+type NavigationRequest struct {
+	Code            string   `json:"code"`
+	Step            int      `json:"step"`
+	Line            int      `json:"line,omitempty"`
+	ExpressionSteps bool     `json:"expression_steps,omitempty"`
+	ReadEvents      bool     `json:"read_events,omitempty"`
+	IndexMarkers    bool     `json:"index_markers,omitempty"`
+	Watch           []string `json:"watch,omitempty"`
+}
+
+// NavigationPath возвращает путь обработчика команды навигации, например /navigate/step_over
+func NavigationPath(action step.Action) string {
+	return "/navigate/" + string(action)
+}
+
+// NewNavigationHandler возвращает обработчик команды навигации action: он находит шаг,
+// к которому ведет команда, и возвращает снимок состояния на этом шаге
+func NewNavigationHandler(cfg *configinfra.Config, cacher cache.Cacher, action step.Action) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, SnapshotResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req NavigationRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "code is required"})
+			return
+		}
+
+		if req.Step < 0 {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "step must be non-negative"})
+			return
+		}
+
+		if (action == step.ActionNextLine || action == step.ActionPrevLine) && req.Line <= 0 {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "line must be positive"})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps, readEvents: req.ReadEvents, indexMarkers: req.IndexMarkers})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		// шаги до начала main (глобальные объявления) недоступны для навигации
+		var steps []step.Step
+		if exec.stepBegin < len(exec.steps) {
+			steps = exec.steps[exec.stepBegin:]
+		}
+		target, err := step.Navigate(steps, req.Step, action, req.Line)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		resp, reqErr := snapshotAt(exec, target, req.Watch)
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
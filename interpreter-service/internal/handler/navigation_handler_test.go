@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const navigationCode = `int twice(int n) {
+	return n * 2;
+}
+
+int main() {
+	int x = twice(3);
+	x = x + 1;
+	return x;
+}`
+
+func navigate(t *testing.T, action step.Action, body NavigationRequest) (int, SnapshotResponse) {
+	t.Helper()
+
+	h := NewNavigationHandler(config.Default(), nil, action)
+
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, NavigationPath(action), bytes.NewReader(payload))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	var resp SnapshotResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	return rr.Code, resp
+}
+
+func TestNewNavigationHandler_StepOverAndInto(t *testing.T) {
+	code, resp := navigate(t, step.ActionStepOver, NavigationRequest{Code: navigationCode, Step: 0})
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, resp.Success)
+	// шаг с обходом не заходит в twice и останавливается на возврате в строку вызова
+	assert.Equal(t, 2, resp.CurrentStep)
+	assert.Equal(t, 6, resp.Snapshot.GetCurrentLine())
+
+	code, resp = navigate(t, step.ActionStepInto, NavigationRequest{Code: navigationCode, Step: 0})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, resp.CurrentStep)
+	assert.Equal(t, 2, resp.Snapshot.GetCurrentLine())
+	assert.Equal(t, 3, resp.Snapshot.GetFramesCount())
+}
+
+func TestNewNavigationHandler_StepOutAndLines(t *testing.T) {
+	code, resp := navigate(t, step.ActionStepOut, NavigationRequest{Code: navigationCode, Step: 1})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, resp.CurrentStep)
+
+	code, resp = navigate(t, step.ActionNextLine, NavigationRequest{Code: navigationCode, Step: 0, Line: 8})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 8, resp.Snapshot.GetCurrentLine())
+
+	code, resp = navigate(t, step.ActionPrevLine, NavigationRequest{Code: navigationCode, Step: 4, Line: 2})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, resp.CurrentStep)
+
+	code, resp = navigate(t, step.ActionRunToEnd, NavigationRequest{Code: navigationCode, Step: 0})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, resp.StepsCount-1, resp.CurrentStep)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, 7, *resp.Result)
+}
+
+func TestNewNavigationHandler_Errors(t *testing.T) {
+	code, resp := navigate(t, step.ActionNextLine, NavigationRequest{Code: navigationCode, Step: 0})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "line must be positive", resp.Error)
+
+	code, resp = navigate(t, step.ActionNextLine, NavigationRequest{Code: navigationCode, Step: 0, Line: 4})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "line 4 is not reached after step 0", resp.Error)
+
+	code, resp = navigate(t, step.ActionStepOver, NavigationRequest{Code: navigationCode, Step: 100})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, resp.Error, "invalid step index")
+
+	code, resp = navigate(t, step.ActionStepOver, NavigationRequest{Code: "  ", Step: 0})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "code is required", resp.Error)
+}
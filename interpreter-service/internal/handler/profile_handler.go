@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/profile"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// ProfileRequest - запрос профиля выполнения программы. TopLines ограничивает число самых затратных строк
+// (0 - все строки); Format "pprof" возвращает профиль в формате pprof для файла SourceFile вместо JSON
+type ProfileRequest struct {
+	Code       string `json:"code"`
+	TopLines   int    `json:"top_lines,omitempty"`
+	Format     string `json:"format,omitempty"`
+	SourceFile string `json:"source_file,omitempty"`
+}
+
+// ProfileResponse - профиль выполнения в формате JSON
+type ProfileResponse struct {
+	Success bool             `json:"success"`
+	Error   string           `json:"error,omitempty"`
+	Profile *profile.Profile `json:"profile,omitempty"`
+}
+
+func NewProfileHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, ProfileResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req ProfileRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ProfileResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, ProfileResponse{Success: false, Error: "code is required"})
+			return
+		}
+		if req.TopLines < 0 {
+			writeJSON(w, http.StatusBadRequest, ProfileResponse{Success: false, Error: "top_lines must be >= 0"})
+			return
+		}
+		if req.Format != "" && req.Format != "json" && req.Format != "pprof" {
+			writeJSON(w, http.StatusBadRequest, ProfileResponse{Success: false, Error: "unknown format: " + req.Format})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{readEvents: true})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, ProfileResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		p := profile.Build(exec.steps)
+
+		if req.Format == "pprof" {
+			source := req.SourceFile
+			if source == "" {
+				source = defaultSourceFile
+			}
+			var buf bytes.Buffer
+			if err := p.WritePprof(&buf, source); err != nil {
+				writeJSON(w, http.StatusInternalServerError, ProfileResponse{Success: false, Error: err.Error()})
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(buf.Bytes())
+			return
+		}
+
+		if req.TopLines > 0 && len(p.Lines) > req.TopLines {
+			p.Lines = p.Lines[:req.TopLines]
+		}
+		writeJSON(w, http.StatusOK, ProfileResponse{Success: true, Profile: p})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileHandlerCode = `int square(int x) {
+	return x * x;
+}
+int main() {
+	int s = 0;
+	for (int i = 0; i < 4; i++) {
+		s += square(i);
+	}
+	return s;
+}`
+
+func TestNewProfileHandler_JSON(t *testing.T) {
+	rr := doJSON(t, NewProfileHandler(config.Default(), nil), http.MethodPost, "/profile", ProfileRequest{Code: profileHandlerCode, TopLines: 2})
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp := decodeJSON[ProfileResponse](t, rr)
+	require.True(t, resp.Success)
+	require.NotNil(t, resp.Profile)
+
+	require.Len(t, resp.Profile.Functions, 2)
+	assert.Equal(t, "main", resp.Profile.Functions[0].Name)
+	assert.Equal(t, resp.Profile.Steps, resp.Profile.Functions[0].Inclusive)
+	assert.Equal(t, "square", resp.Profile.Functions[1].Name)
+	assert.Equal(t, 4, resp.Profile.Functions[1].Calls)
+	assert.Equal(t, 4, resp.Profile.Functions[1].Exclusive)
+
+	require.Len(t, resp.Profile.Lines, 2)
+	assert.Equal(t, 6, resp.Profile.Lines[0].Line)
+
+	var x, s int
+	for _, v := range resp.Profile.Variables {
+		switch v.Name {
+		case "x":
+			x = v.Reads
+		case "s":
+			s = v.Writes
+		}
+	}
+	assert.Equal(t, 8, x)
+	assert.Equal(t, 5, s)
+}
+
+func TestNewProfileHandler_Pprof(t *testing.T) {
+	rr := doJSON(t, NewProfileHandler(config.Default(), nil), http.MethodPost, "/profile", ProfileRequest{Code: profileHandlerCode, Format: "pprof"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/octet-stream", rr.Header().Get("Content-Type"))
+	// сжатый gzip profile.proto
+	assert.True(t, bytes.HasPrefix(rr.Body.Bytes(), []byte{0x1f, 0x8b}))
+}
+
+func TestNewProfileHandler_Validation(t *testing.T) {
+	rr := doJSON(t, NewProfileHandler(config.Default(), nil), http.MethodGet, "/profile", ProfileRequest{Code: profileHandlerCode})
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	rr = doJSON(t, NewProfileHandler(config.Default(), nil), http.MethodPost, "/profile", ProfileRequest{Code: " "})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "code is required", decodeJSON[ProfileResponse](t, rr).Error)
+
+	rr = doJSON(t, NewProfileHandler(config.Default(), nil), http.MethodPost, "/profile", ProfileRequest{Code: profileHandlerCode, TopLines: -1})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = doJSON(t, NewProfileHandler(config.Default(), nil), http.MethodPost, "/profile", ProfileRequest{Code: profileHandlerCode, Format: "svg"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, decodeJSON[ProfileResponse](t, rr).Error, "unknown format")
+}
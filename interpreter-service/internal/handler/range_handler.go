@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/runtime"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
+	configinfra "github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+)
+
+// RangeRequest - запрос шагов From..To: полный снимок на шаге From и изменения на каждом следующем шаге.
+// To за последним шагом ограничивается последним шагом. При Stream ответ передается в формате NDJSON
+type RangeRequest struct {
+	Code            string `json:"code"`
+	From            int    `json:"from"`
+	To              int    `json:"to"`
+	Stream          bool   `json:"stream,omitempty"`
+	ExpressionSteps bool   `json:"expression_steps,omitempty"`
+	ReadEvents      bool   `json:"read_events,omitempty"`
+	IndexMarkers    bool   `json:"index_markers,omitempty"`
+}
+
+// StepDelta - изменения состояния на шаге: события шага и положение, в котором выполнение останавливается после них
+type StepDelta struct {
+	Step         int                    `json:"step"`
+	Line         int                    `json:"line"`
+	Range        *converter.Location    `json:"range,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	FunctionName string                 `json:"function_name,omitempty"`
+	ReturnValue  *runtime.Value         `json:"return_value,omitempty"`
+	Accessed     []snapshot.Access      `json:"accessed,omitempty"`
+	IndexMarkers []snapshot.IndexMarker `json:"index_markers,omitempty"`
+	Destroyed    []snapshot.Destroyed   `json:"destroyed,omitempty"`
+	Events       []events.EventDTO      `json:"events"`
+}
+
+// RangeResponse - снимок на шаге From и изменения на шагах From+1..To.
+// В потоковом режиме первая строка содержит ответ без deltas, следующие - по одному StepDelta
+type RangeResponse struct {
+	SnapshotResponse
+	From   int         `json:"from"`
+	To     int         `json:"to"`
+	Deltas []StepDelta `json:"deltas,omitempty"`
+}
+
+const ndjsonContentType = "application/x-ndjson"
+
+func NewRangeHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
+	val := buildValidator(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, SnapshotResponse{Success: false, Error: "method not allowed"})
+			return
+		}
+
+		var req RangeRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "invalid request body: " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(req.Code) == "" {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "code is required"})
+			return
+		}
+
+		if req.From < 0 {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "from must be non-negative"})
+			return
+		}
+
+		if req.To < req.From {
+			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "to must not be less than from"})
+			return
+		}
+
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps, readEvents: req.ReadEvents, indexMarkers: req.IndexMarkers})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		first, reqErr := snapshotAt(exec, req.From, nil)
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+		resp := RangeResponse{SnapshotResponse: first, From: req.From, To: min(req.To, first.StepsCount-1)}
+
+		if !req.Stream {
+			err := replayDeltas(exec, resp.From, resp.To, func(delta StepDelta) error {
+				resp.Deltas = append(resp.Deltas, delta)
+				return nil
+			})
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, SnapshotResponse{Success: false, Error: err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+		encoder := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		writeLine := func(body any) error {
+			if err := encoder.Encode(body); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		}
+
+		if err := writeLine(resp); err != nil {
+			return
+		}
+		err := replayDeltas(exec, resp.From, resp.To, func(delta StepDelta) error {
+			return writeLine(delta)
+		})
+		if err != nil {
+			// заголовок уже отправлен, ошибка передается последней строкой потока
+			_ = writeLine(SnapshotResponse{Success: false, Error: err.Error()})
+		}
+	}
+}
+
+// replayDeltas восстанавливает шаги from+1..to и передает изменения каждого из них в emit
+func replayDeltas(exec execution, from, to int, emit func(StepDelta) error) error {
+	ed := eventdispatcher.NewEventDispatcher(exec.stepBegin)
+	ed.Steps = exec.steps
+	ed.SetCheckpoints(exec.checkpoints)
+	if err := ed.ApplyStep(from); err != nil {
+		return err
+	}
+
+	for ind := from + 1; ind <= to; ind++ {
+		if err := ed.ApplyStep(ind); err != nil {
+			return err
+		}
+
+		st := exec.steps[exec.stepBegin+ind]
+		delta := StepDelta{Step: ind, Events: make([]events.EventDTO, len(st.Events))}
+		for k, e := range st.Events {
+			dto, err := events.MarshalEvent(e)
+			if err != nil {
+				return err
+			}
+			delta.Events[k] = dto
+		}
+
+		sn := ed.GetSnapshot()
+		delta.Line, delta.Range, delta.Error = sn.Line, sn.Range, sn.Error
+		delta.FunctionName, delta.ReturnValue = sn.FunctionName, sn.ReturnValue
+		delta.Accessed, delta.IndexMarkers, delta.Destroyed = sn.Accessed, sn.IndexMarkers, sn.Destroyed
+		if err := emit(delta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
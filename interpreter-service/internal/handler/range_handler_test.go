@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rangeCode = `int main() {
+	int sum = 0;
+	for (int i = 0; i < 3; i++) {
+		sum += i;
+	}
+	return sum;
+}`
+
+func snapshotResponse(t *testing.T, code string, step int) SnapshotResponse {
+	t.Helper()
+
+	rr := doJSON(t, NewSnapshotHandler(config.Default(), nil), http.MethodPost, "/snapshot", SnapshotRequest{Code: code, Step: step})
+	require.Equal(t, http.StatusOK, rr.Code)
+	return decodeJSON[SnapshotResponse](t, rr)
+}
+
+func TestNewRangeHandler_SnapshotAndDeltas(t *testing.T) {
+	rr := doJSON(t, NewRangeHandler(config.Default(), nil), http.MethodPost, "/range", RangeRequest{Code: rangeCode, From: 1, To: 4})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	resp := decodeJSON[RangeResponse](t, rr)
+	require.True(t, resp.Success)
+	assert.Equal(t, 1, resp.From)
+	assert.Equal(t, 4, resp.To)
+	assert.Equal(t, 1, resp.CurrentStep)
+	require.NotNil(t, resp.Snapshot)
+	require.Len(t, resp.Deltas, 3)
+
+	// изменения каждого шага совпадают с тем, что возвращает /snapshot для этого шага
+	for _, delta := range resp.Deltas {
+		snap := snapshotResponse(t, rangeCode, delta.Step)
+		assert.Equal(t, snap.Snapshot.Line, delta.Line, "step %d", delta.Step)
+		assert.Equal(t, snap.Snapshot.Range, delta.Range, "step %d", delta.Step)
+		assert.NotEmpty(t, delta.Events)
+	}
+	assert.Equal(t, 2, resp.Deltas[0].Step)
+}
+
+func TestNewRangeHandler_ClampsTo(t *testing.T) {
+	rr := doJSON(t, NewRangeHandler(config.Default(), nil), http.MethodPost, "/range", RangeRequest{Code: rangeCode, From: 0, To: 1000})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	resp := decodeJSON[RangeResponse](t, rr)
+	assert.Equal(t, resp.StepsCount-1, resp.To)
+	assert.Len(t, resp.Deltas, resp.StepsCount-1)
+}
+
+func TestNewRangeHandler_Stream(t *testing.T) {
+	rr := doJSON(t, NewRangeHandler(config.Default(), nil), http.MethodPost, "/range", RangeRequest{Code: rangeCode, From: 0, To: 3, Stream: true})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+
+	scanner := bufio.NewScanner(rr.Body)
+	require.True(t, scanner.Scan())
+	var header RangeResponse
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	assert.True(t, header.Success)
+	assert.NotNil(t, header.Snapshot)
+	assert.Empty(t, header.Deltas)
+
+	var steps []int
+	for scanner.Scan() {
+		var delta StepDelta
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &delta))
+		steps = append(steps, delta.Step)
+	}
+	assert.Equal(t, []int{1, 2, 3}, steps)
+}
+
+func TestNewRangeHandler_Errors(t *testing.T) {
+	rr := doJSON(t, NewRangeHandler(config.Default(), nil), http.MethodPost, "/range", RangeRequest{Code: rangeCode, From: 3, To: 1})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = doJSON(t, NewRangeHandler(config.Default(), nil), http.MethodPost, "/range", RangeRequest{Code: rangeCode, From: -1, To: 1})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = doJSON(t, NewRangeHandler(config.Default(), nil), http.MethodPost, "/range", RangeRequest{Code: rangeCode, From: 1000, To: 1001})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/eventdispatcher"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/interpreter"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/application/watch"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
@@ -21,6 +22,14 @@ import (
 type SnapshotRequest struct {
 	Code string `json:"code"`
 	Step int    `json:"step"`
+	// ExpressionSteps включает пошаговое вычисление выражений
+	ExpressionSteps bool `json:"expression_steps,omitempty"`
+	// ReadEvents включает события чтения переменных и элементов массивов (snapshot.accessed)
+	ReadEvents bool `json:"read_events,omitempty"`
+	// IndexMarkers включает маркеры переменных, входящих в индексы массивов (snapshot.index_markers)
+	IndexMarkers bool `json:"index_markers,omitempty"`
+	// Watch - выражения, значения которых вычисляются на шаге Step
+	Watch []string `json:"watch,omitempty"`
 }
 
 type SnapshotResponse struct {
@@ -31,6 +40,7 @@ type SnapshotResponse struct {
 	StepsCount  int                `json:"steps_count,omitempty"`
 	Result      *int               `json:"result,omitempty"`
 	Snapshot    *snapshot.Snapshot `json:"snapshot,omitempty"`
+	Watches     []watch.Result     `json:"watches,omitempty"`
 }
 
 func NewSnapshotHandler(cfg *configinfra.Config, cacher cache.Cacher) http.HandlerFunc {
@@ -60,86 +70,143 @@ func NewSnapshotHandler(cfg *configinfra.Config, cacher cache.Cacher) http.Handl
 			return
 		}
 
-		cacheKey := fmt.Sprintf("code:%s:max_elements:%d:max_steps:%d", req.Code, cfg.MaxAllocatedElements, cfg.MaxSteps)
+		exec, reqErr := loadExecution(r, cfg, cacher, val, req.Code, traceOptions{expressionSteps: req.ExpressionSteps, readEvents: req.ReadEvents, indexMarkers: req.IndexMarkers})
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+
+		resp, reqErr := snapshotAt(exec, req.Step, req.Watch)
+		if reqErr != nil {
+			writeJSON(w, reqErr.status, SnapshotResponse{Success: false, Error: reqErr.message})
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// execution - результат выполнения программы: шаги и значение, возвращенное main,
+// а также сохраненные состояния, с которых восстанавливаются шаги
+type execution struct {
+	steps       []step.Step
+	stepBegin   int
+	result      *int
+	err         error
+	checkpoints []snapshot.Checkpoint
+}
+
+// requestError - ошибка обработки запроса и HTTP-статус ответа на нее
+type requestError struct {
+	status  int
+	message string
+}
 
-		var steps []step.Step
-		var stepBegin int
-		var result *int
-		var execErr error
+// traceOptions - режимы выполнения, от которых зависят события трассы.
+// События чтения, маркеры индексов и исходы условий не добавляют шагов, поэтому номера шагов зависят только от expressionSteps
+type traceOptions struct {
+	expressionSteps bool
+	readEvents      bool
+	indexMarkers    bool
+	branchEvents    bool
+}
 
-		if cacher != nil {
-			cachedInfo, err := cacher.Get(r.Context(), cacheKey)
-			if err == nil && (cachedInfo.Value != nil || cachedInfo.Err != nil) {
-				steps = cachedInfo.Value
-				stepBegin = cachedInfo.StepBegin
-				result = cachedInfo.Result
-				execErr = cachedInfo.Err
+// loadExecution возвращает шаги выполнения программы из кэша или выполняет ее
+func loadExecution(r *http.Request, cfg *configinfra.Config, cacher cache.Cacher, val *validator.SemanticValidator, code string, opts traceOptions) (execution, *requestError) {
+	cacheKey := fmt.Sprintf("code:%s:max_elements:%d:max_steps:%d:expression_steps:%t:read_events:%t:index_markers:%t:branch_events:%t",
+		code, cfg.MaxAllocatedElements, cfg.MaxSteps, opts.expressionSteps, opts.readEvents, opts.indexMarkers, opts.branchEvents)
+
+	if cacher != nil {
+		cachedInfo, err := cacher.Get(r.Context(), cacheKey)
+		if err == nil && (cachedInfo.Value != nil || cachedInfo.Err != nil) {
+			exec := execution{steps: cachedInfo.Value, stepBegin: cachedInfo.StepBegin, result: cachedInfo.Result, err: cachedInfo.Err, checkpoints: cachedInfo.Checkpoints}
+			if len(exec.checkpoints) == 0 {
+				// запись кэша сделана без сохраненных состояний
+				exec.checkpoints, _ = eventdispatcher.BuildCheckpoints(exec.steps, exec.stepBegin, cfg.CheckpointInterval)
 			}
+			return exec, nil
 		}
+	}
 
-		if steps == nil && execErr == nil {
-			conv := converter.New()
-			program, parseErr := conv.ParseToAST(req.Code)
-			if parseErr != nil {
-				writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "parse error: " + parseErr.Error()})
-				return
-			}
+	program, reqErr := parseProgram(val, code)
+	if reqErr != nil {
+		return execution{}, reqErr
+	}
 
-			if err := val.ValidateProgram(program, req.Code); err != nil {
-				var unavailableErr validator.CompileUnavailableError
-				if errors.As(err, &unavailableErr) {
-					writeJSON(w, http.StatusServiceUnavailable, SnapshotResponse{Success: false, Error: err.Error()})
-					return
-				}
+	runner := interpreter.NewInterpreterWithLimits(cfg.MaxAllocatedElements, cfg.MaxSteps)
+	runner.SetExpressionStepping(opts.expressionSteps)
+	runner.SetReadEvents(opts.readEvents)
+	runner.SetIndexMarkers(opts.indexMarkers)
+	runner.SetBranchEvents(opts.branchEvents)
 
-				writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "semantic error: " + err.Error()})
-				return
-			}
+	var exec execution
+	exec.result, exec.steps, exec.stepBegin, exec.err = runner.ExecuteProgram(program)
+	if exec.err != nil && exec.steps == nil {
+		return execution{}, &requestError{http.StatusBadRequest, "error: " + exec.err.Error()}
+	}
 
-			runner := interpreter.NewInterpreterWithLimits(cfg.MaxAllocatedElements, cfg.MaxSteps)
-			result, steps, stepBegin, execErr = runner.ExecuteProgram(program)
-			if execErr != nil && steps == nil {
-				writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: "error: " + execErr.Error()})
-				return
-			}
+	// шаг, который не удалось применить, вернет ошибку при запросе его снимка
+	exec.checkpoints, _ = eventdispatcher.BuildCheckpoints(exec.steps, exec.stepBegin, cfg.CheckpointInterval)
 
-			if cacher != nil {
-				cachedInfo := cache.CachedInfo{
-					Value:     steps,
-					StepBegin: stepBegin,
-					Result:    result,
-					Err:       execErr,
-				}
-				_ = cacher.Set(r.Context(), cacheKey, cachedInfo)
-			}
+	if cacher != nil {
+		cachedInfo := cache.CachedInfo{
+			Value:       exec.steps,
+			StepBegin:   exec.stepBegin,
+			Result:      exec.result,
+			Err:         exec.err,
+			Checkpoints: exec.checkpoints,
 		}
+		_ = cacher.Set(r.Context(), cacheKey, cachedInfo)
+	}
 
-		ed := eventdispatcher.NewEventDispatcher(stepBegin)
-		ed.Steps = steps
-		if err := ed.ApplyStep(req.Step); err != nil {
-			writeJSON(w, http.StatusBadRequest, SnapshotResponse{Success: false, Error: err.Error()})
-			return
-		}
+	return exec, nil
+}
 
-		stepsCount := len(steps) - stepBegin
-		if stepsCount < 0 {
-			stepsCount = 0
-		}
+// parseProgram разбирает код программы и проверяет его семантическим анализатором
+func parseProgram(val *validator.SemanticValidator, code string) (*converter.Program, *requestError) {
+	program, parseErr := converter.New().ParseToAST(code)
+	if parseErr != nil {
+		return nil, &requestError{http.StatusBadRequest, "parse error: " + parseErr.Error()}
+	}
 
-		currentStep := ed.GetCurrentStep() - stepBegin
-		if currentStep < 0 {
-			currentStep = 0
+	if err := val.ValidateProgram(program, code); err != nil {
+		var unavailableErr validator.CompileUnavailableError
+		if errors.As(err, &unavailableErr) {
+			return nil, &requestError{http.StatusServiceUnavailable, err.Error()}
 		}
+		return nil, &requestError{http.StatusBadRequest, "semantic error: " + err.Error()}
+	}
+	return program, nil
+}
+
+// snapshotAt восстанавливает состояние программы на шаге stepIndex (нумерация от начала main)
+// и вычисляет на нем наблюдаемые выражения watches
+func snapshotAt(exec execution, stepIndex int, watches []string) (SnapshotResponse, *requestError) {
+	ed := eventdispatcher.NewEventDispatcher(exec.stepBegin)
+	ed.Steps = exec.steps
+	ed.SetCheckpoints(exec.checkpoints)
+	if err := ed.ApplyStep(stepIndex); err != nil {
+		return SnapshotResponse{}, &requestError{http.StatusBadRequest, err.Error()}
+	}
+
+	stepsCount := len(exec.steps) - exec.stepBegin
+	if stepsCount < 0 {
+		stepsCount = 0
+	}
 
-		writeJSON(w, http.StatusOK, SnapshotResponse{
-			Success:     true,
-			Step:        req.Step,
-			CurrentStep: currentStep,
-			StepsCount:  stepsCount,
-			Result:      result,
-			Snapshot:    ed.GetSnapshot(),
-		})
+	currentStep := ed.GetCurrentStep() - exec.stepBegin
+	if currentStep < 0 {
+		currentStep = 0
 	}
+
+	return SnapshotResponse{
+		Success:     true,
+		Step:        stepIndex,
+		CurrentStep: currentStep,
+		StepsCount:  stepsCount,
+		Result:      exec.result,
+		Snapshot:    ed.GetSnapshot(),
+		Watches:     watch.Evaluate(ed.GetSnapshot(), watches),
+	}, nil
 }
 
 func buildValidator(cfg *configinfra.Config) *validator.SemanticValidator {
@@ -161,7 +228,7 @@ func buildValidator(cfg *configinfra.Config) *validator.SemanticValidator {
 	return validator.NewWithOneCompilerClient(client)
 }
 
-func writeJSON(w http.ResponseWriter, status int, body SnapshotResponse) {
+func writeJSON(w http.ResponseWriter, status int, body any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(body)
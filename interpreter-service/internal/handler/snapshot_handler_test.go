@@ -9,6 +9,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/events"
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/cache"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/infrastructure/config"
@@ -136,6 +138,187 @@ func TestNewSnapshotHandler_Success(t *testing.T) {
 	assert.GreaterOrEqual(t, resp.Snapshot.GetFramesCount(), 1)
 }
 
+func TestNewSnapshotHandler_ExpressionSteps(t *testing.T) {
+	cfg := config.Default()
+
+	h := NewSnapshotHandler(cfg, nil)
+
+	code := `int main() {
+	int a = 2;
+	int x = a * 3 + 1;
+	return x;
+}`
+	request := func(expressionSteps bool, step int) SnapshotResponse {
+		payload, err := json.Marshal(SnapshotRequest{Code: code, Step: step, ExpressionSteps: expressionSteps})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/snapshot", bytes.NewReader(payload))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var resp SnapshotResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.True(t, resp.Success)
+		return resp
+	}
+
+	plain := request(false, 0)
+	detailed := request(true, 0)
+	// a, a * 3, a * 3 + 1 и x в return
+	assert.Equal(t, plain.StepsCount+4, detailed.StepsCount)
+
+	resp := request(true, 3)
+	require.NotNil(t, resp.Snapshot)
+	evaluated := resp.Snapshot.GetCurrentFrame().Evaluated
+	require.Len(t, evaluated, 2)
+	assert.Equal(t, 6, evaluated[1].Value.Int)
+}
+
+func TestNewSnapshotHandler_ReadEvents(t *testing.T) {
+	cfg := config.Default()
+
+	h := NewSnapshotHandler(cfg, nil)
+
+	code := `int main() {
+	int a[2] = {4, 3};
+	int j = 0;
+	if (a[j] > a[j + 1]) {
+		j = 1;
+	}
+	return j;
+}`
+	request := func(readEvents bool, step int) SnapshotResponse {
+		payload, err := json.Marshal(SnapshotRequest{Code: code, Step: step, ReadEvents: readEvents})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/snapshot", bytes.NewReader(payload))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var resp SnapshotResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.True(t, resp.Success)
+		return resp
+	}
+
+	plain := request(false, 0)
+	first := request(true, 0)
+	// события чтения не добавляют шагов
+	assert.Equal(t, plain.StepsCount, first.StepsCount)
+
+	// шаг, на котором вычислено условие if: сравниваются a[0] и a[1]
+	var compared [][]int
+	for ind := 0; ind < first.StepsCount && compared == nil; ind++ {
+		resp := request(true, ind)
+		require.NotNil(t, resp.Snapshot)
+		for _, access := range resp.Snapshot.Accessed {
+			if access.Use == events.ReadCompare {
+				assert.Equal(t, "a", access.Name)
+				compared = append(compared, access.Index)
+			}
+		}
+		assert.Empty(t, request(false, ind).Snapshot.Accessed)
+	}
+	assert.Equal(t, [][]int{{0}, {1}}, compared)
+}
+
+func TestNewSnapshotHandler_IndexMarkers(t *testing.T) {
+	cfg := config.Default()
+
+	h := NewSnapshotHandler(cfg, nil)
+
+	code := `int main() {
+	int m[2][2] = {{1, 2}, {3, 4}};
+	int i = 1;
+	int j = 0;
+	int x = m[i][j + 1];
+	return x;
+}`
+	var markers []snapshot.IndexMarker
+	stepsCount := 1
+	for ind := 0; ind < stepsCount && markers == nil; ind++ {
+		payload, err := json.Marshal(SnapshotRequest{Code: code, Step: ind, IndexMarkers: true})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/snapshot", bytes.NewReader(payload))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var resp SnapshotResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.True(t, resp.Success)
+		stepsCount = resp.StepsCount
+		markers = resp.Snapshot.IndexMarkers
+	}
+
+	assert.Equal(t, []snapshot.IndexMarker{
+		{Frame: 1, Variable: "i", Name: "m", Dimension: 0, Index: 1},
+		{Frame: 1, Variable: "j", Name: "m", Dimension: 1, Index: 1, Offset: 1},
+	}, markers)
+}
+
+func TestNewSnapshotHandler_Watch(t *testing.T) {
+	cfg := config.Default()
+
+	h := NewSnapshotHandler(cfg, nil)
+
+	payload, err := json.Marshal(SnapshotRequest{
+		Code:  "int main() {\n\tint a[2] = {4, 5};\n\tint x;\n\treturn a[0];\n}",
+		Step:  2,
+		Watch: []string{"a[0] + a[1]", "x", "a[2]"},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/snapshot", bytes.NewReader(payload))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp SnapshotResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	require.Len(t, resp.Watches, 3)
+	require.NotNil(t, resp.Watches[0].Value)
+	assert.Equal(t, 9, resp.Watches[0].Value.Int)
+	assert.Contains(t, resp.Watches[1].Error, "undefined behavior")
+	assert.NotEmpty(t, resp.Watches[2].Error)
+}
+
+func TestNewSnapshotHandler_FloatingOverflow(t *testing.T) {
+	cfg := config.Default()
+
+	h := NewSnapshotHandler(cfg, nil)
+
+	code := `int main() {
+	double d = 1e308;
+	d = d * 10;
+	return 0;
+}`
+	request := func(step int) (*httptest.ResponseRecorder, SnapshotResponse) {
+		payload, err := json.Marshal(SnapshotRequest{Code: code, Step: step})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/snapshot", bytes.NewReader(payload))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		var resp SnapshotResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		return rr, resp
+	}
+
+	rr, resp := request(0)
+	require.Equal(t, http.StatusOK, rr.Code, resp.Error)
+
+	// шаг после переполнения содержит ошибку, а не бесконечное значение
+	rr, resp = request(resp.StepsCount - 1)
+	require.Equal(t, http.StatusOK, rr.Code, resp.Error)
+	require.NotNil(t, resp.Snapshot)
+	assert.Equal(t, "undefined behavior: floating-point overflow: result is out of range of double", resp.Snapshot.Error)
+}
+
 func TestNewSnapshotHandler_StepOutOfRange(t *testing.T) {
 	cfg := config.Default()
 
@@ -351,6 +534,7 @@ func TestNewSnapshotHandler_WithCacher_CacheKeyFormat(t *testing.T) {
 	assert.Contains(t, mockCacher.getKey, code)
 	assert.Contains(t, mockCacher.getKey, "max_elements:")
 	assert.Contains(t, mockCacher.getKey, "max_steps:")
+	assert.Contains(t, mockCacher.getKey, "expression_steps:false")
 }
 
 func TestNewSnapshotHandler_NoCacher(t *testing.T) {
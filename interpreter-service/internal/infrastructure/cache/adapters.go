@@ -1,8 +1,9 @@
 package cache
 
 import (
-	"fmt"
+	"errors"
 
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
 
@@ -11,6 +12,8 @@ type CachedInfoDTO struct {
 	StepBegin int            `json:"stepBegin"`
 	Result    *int           `json:"result"`
 	Err       string         `json:"err,omitempty"`
+	// Checkpoints - сохраненные состояния в представлении, из которого они восстанавливаются полностью
+	Checkpoints []snapshot.CheckpointDTO `json:"checkpoints,omitempty"`
 }
 
 func MarshalCachedInfo(c CachedInfo) (CachedInfoDTO, error) {
@@ -23,16 +26,26 @@ func MarshalCachedInfo(c CachedInfo) (CachedInfoDTO, error) {
 		stepDTOs[i] = dto
 	}
 
+	checkpointDTOs := make([]snapshot.CheckpointDTO, len(c.Checkpoints))
+	for i, checkpoint := range c.Checkpoints {
+		dto, err := snapshot.MarshalCheckpoint(checkpoint)
+		if err != nil {
+			return CachedInfoDTO{}, err
+		}
+		checkpointDTOs[i] = dto
+	}
+
 	var errStr string
 	if c.Err != nil {
 		errStr = c.Err.Error()
 	}
 
 	return CachedInfoDTO{
-		Value:     stepDTOs,
-		StepBegin: c.StepBegin,
-		Result:    c.Result,
-		Err:       errStr,
+		Value:       stepDTOs,
+		StepBegin:   c.StepBegin,
+		Result:      c.Result,
+		Err:         errStr,
+		Checkpoints: checkpointDTOs,
 	}, nil
 }
 
@@ -46,15 +59,25 @@ func UnmarshalCachedInfo(dto CachedInfoDTO) (CachedInfo, error) {
 		steps[i] = s
 	}
 
+	checkpoints := make([]snapshot.Checkpoint, len(dto.Checkpoints))
+	for i, checkpointDTO := range dto.Checkpoints {
+		c, err := snapshot.UnmarshalCheckpoint(checkpointDTO)
+		if err != nil {
+			return CachedInfo{}, err
+		}
+		checkpoints[i] = c
+	}
+
 	var err error
 	if dto.Err != "" {
-		err = fmt.Errorf(dto.Err)
+		err = errors.New(dto.Err)
 	}
 
 	return CachedInfo{
-		Value:     steps,
-		StepBegin: dto.StepBegin,
-		Result:    dto.Result,
-		Err:       err,
+		Value:       steps,
+		StepBegin:   dto.StepBegin,
+		Result:      dto.Result,
+		Err:         err,
+		Checkpoints: checkpoints,
 	}, nil
 }
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 
+	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/snapshot"
 	"github.com/Oleja123/code-vizualization/interpreter-service/internal/domain/step"
 )
 
@@ -11,6 +12,8 @@ type CachedInfo struct {
 	StepBegin int         `json:"stepBegin"`
 	Result    *int        `json:"result"`
 	Err       error       `json:"err,omitempty"`
+	// Checkpoints - сохраненные состояния, с которых восстанавливаются шаги
+	Checkpoints []snapshot.Checkpoint `json:"checkpoints,omitempty"`
 }
 
 type Cacher interface {
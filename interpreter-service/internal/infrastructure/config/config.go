@@ -14,6 +14,7 @@ type Config struct {
 	OneCompilerConfig `yaml:"onecompiler"`
 	LimitationsConfig `yaml:"limitations"`
 	RedisConfig       `yaml:"redis"`
+	SnapshotConfig    `yaml:"snapshots"`
 }
 
 type ServerConfig struct {
@@ -27,9 +28,21 @@ type OneCompilerConfig struct {
 	TimeoutSeconds int    `yaml:"timeout_seconds"`
 }
 
+// LimitationsConfig - лимиты выполнения программы. Оценка сложности выполняет программу для больших размеров
+// входных данных и использует отдельные лимиты ComplexityMaxAllocatedElements и ComplexityMaxSteps.
+// Запрос оценки содержит не больше ComplexityMaxSizes размеров, каждый не больше ComplexityMaxN
 type LimitationsConfig struct {
-	MaxAllocatedElements int `yaml:"max_allocated_elements"`
-	MaxSteps             int `yaml:"max_steps"`
+	MaxAllocatedElements           int `yaml:"max_allocated_elements"`
+	MaxSteps                       int `yaml:"max_steps"`
+	ComplexityMaxAllocatedElements int `yaml:"complexity_max_allocated_elements"`
+	ComplexityMaxSteps             int `yaml:"complexity_max_steps"`
+	ComplexityMaxSizes             int `yaml:"complexity_max_sizes"`
+	ComplexityMaxN                 int `yaml:"complexity_max_n"`
+}
+
+// SnapshotConfig - восстановление снимков: сохраненное состояние запоминается каждые CheckpointInterval шагов
+type SnapshotConfig struct {
+	CheckpointInterval int `yaml:"checkpoint_interval"`
 }
 
 type RedisConfig struct {
@@ -52,8 +65,12 @@ func Default() *Config {
 			TimeoutSeconds: 10,
 		},
 		LimitationsConfig: LimitationsConfig{
-			MaxAllocatedElements: 100,
-			MaxSteps:             1000,
+			MaxAllocatedElements:           100,
+			MaxSteps:                       1000,
+			ComplexityMaxAllocatedElements: 100000,
+			ComplexityMaxSteps:             200000,
+			ComplexityMaxSizes:             10,
+			ComplexityMaxN:                 100000,
 		},
 		RedisConfig: RedisConfig{
 			Host:         "localhost",
@@ -63,6 +80,9 @@ func Default() *Config {
 			Expiration:   24 * time.Hour,
 			PingAttempts: 3,
 		},
+		SnapshotConfig: SnapshotConfig{
+			CheckpointInterval: 100,
+		},
 	}
 }
 
@@ -97,10 +117,30 @@ func Load(path string) (*Config, error) {
 		cfg.MaxSteps = 1000
 	}
 
+	if cfg.ComplexityMaxAllocatedElements <= 0 {
+		cfg.ComplexityMaxAllocatedElements = 100000
+	}
+
+	if cfg.ComplexityMaxSteps <= 0 {
+		cfg.ComplexityMaxSteps = 200000
+	}
+
+	if cfg.ComplexityMaxSizes <= 0 {
+		cfg.ComplexityMaxSizes = 10
+	}
+
+	if cfg.ComplexityMaxN <= 0 {
+		cfg.ComplexityMaxN = 100000
+	}
+
 	if cfg.Expiration <= 0 {
 		cfg.Expiration = 24 * time.Hour
 	}
 
+	if cfg.CheckpointInterval <= 0 {
+		cfg.CheckpointInterval = 100
+	}
+
 	return cfg, nil
 }
 
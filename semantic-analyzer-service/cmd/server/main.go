@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/builtins"
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/semantic-analyzer-service/internal/infrastructure/config"
 	"github.com/Oleja123/code-vizualization/semantic-analyzer-service/pkg/onecompiler"
@@ -135,13 +136,14 @@ func handleInfo(w http.ResponseWriter, r *http.Request) {
 			"GET /health":    "Health check",
 			"GET /info":      "Service information",
 		},
-		"supported_types": []string{"int", "void"},
+		"supported_types": []string{"int", "char", "float", "double", "void", "int*", "char*", "float*", "double*"},
 		"supported_operators": map[string][]string{
 			"assignment": {"=", "+=", "-=", "/=", "%="},
-			"unary":      {"-", "!", "++", "--"},
+			"unary":      {"-", "+", "!", "++", "--", "*"},
 			"binary":     {"+", "-", "*", "/", "%", "==", "!=", "<", "<=", ">", ">=", "&&", "||"},
 		},
 		"unsupported_operators": []string{"&", "|", "^", "<<", ">>"},
+		"builtin_functions":     builtins.All(),
 	}
 	json.NewEncoder(w).Encode(info)
 }
@@ -2,8 +2,10 @@ package validator
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
+	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/builtins"
 	"github.com/Oleja123/code-vizualization/cst-to-ast-service/pkg/converter"
 	"github.com/Oleja123/code-vizualization/semantic-analyzer-service/pkg/onecompiler"
 )
@@ -18,10 +20,12 @@ type SemanticValidator struct {
 	allowedTypes       map[string]bool
 	oneCompilerClient  *onecompiler.Client
 	oneCompilerEnabled bool
+	// функции, определенные в проверяемой программе (перекрывают встроенные)
+	userFunctions map[string]bool
 }
 
 var arrayMaximumDimension = 2
-var pointerMaximumDepth = 0
+var pointerMaximumDepth = 1
 
 // New создает новый семантический валидатор
 func New() *SemanticValidator {
@@ -40,6 +44,7 @@ func New() *SemanticValidator {
 			"++": true,
 			"--": true,
 			"!":  true,
+			"*":  true,
 		},
 		allowedBinaryOps: map[string]bool{
 			"+":  true,
@@ -57,11 +62,17 @@ func New() *SemanticValidator {
 			"||": true,
 		},
 		allowedReturnTypes: map[string]bool{
-			"int":  true,
-			"void": true,
+			"int":    true,
+			"char":   true,
+			"float":  true,
+			"double": true,
+			"void":   true,
 		},
 		allowedTypes: map[string]bool{
-			"int": true,
+			"int":    true,
+			"char":   true,
+			"float":  true,
+			"double": true,
 		},
 		oneCompilerEnabled: false,
 	}
@@ -97,6 +108,17 @@ func (v *SemanticValidator) ValidateProgram(program *converter.Program, sourceCo
 		code = sourceCode[0]
 	}
 
+	// Состояние проверки конкретной программы храним в копии, чтобы валидатор можно было
+	// использовать из нескольких горутин
+	programValidator := *v
+	programValidator.userFunctions = make(map[string]bool)
+	for _, decl := range program.Declarations {
+		if funcDecl, ok := decl.(*converter.FunctionDecl); ok {
+			programValidator.userFunctions[funcDecl.Name] = true
+		}
+	}
+	v = &programValidator
+
 	// Проверяем все объявления
 	for _, decl := range program.Declarations {
 		if funcDecl, ok := decl.(*converter.FunctionDecl); ok {
@@ -107,6 +129,9 @@ func (v *SemanticValidator) ValidateProgram(program *converter.Program, sourceCo
 			if err := v.validateVariableDecl(varDecl); err != nil {
 				return err
 			}
+		} else if err := v.validateStmt(decl); err != nil {
+			// Определения перечислений и typedef
+			return err
 		}
 	}
 
@@ -178,10 +203,46 @@ func (v *SemanticValidator) validateVariableDecl(varDecl *converter.VariableDecl
 	return nil
 }
 
+// validateEnumDecl проверяет, что значения констант перечисления представимы в int
+func (v *SemanticValidator) validateEnumDecl(enumDecl *converter.EnumDecl) error {
+	for _, e := range enumDecl.Enumerators {
+		if e.Value < math.MinInt32 || e.Value > math.MaxInt32 {
+			return NewSemanticError(
+				ErrInvalidType,
+				fmt.Sprintf("enumerator value out of range: %s", e.Name),
+				e.Loc,
+				"EnumDecl",
+				fmt.Sprintf("value %d of enumerator '%s' is not representable as 'int'", e.Value, e.Name),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateBuiltinCall проверяет число аргументов вызова встроенной функции
+func (v *SemanticValidator) validateBuiltinCall(call *converter.CallExpr) error {
+	if v.userFunctions[call.FunctionName] {
+		return nil
+	}
+	signature, ok := builtins.Lookup(call.FunctionName)
+	if !ok || len(signature.Params) == len(call.Arguments) {
+		return nil
+	}
+
+	return NewSemanticError(
+		ErrInvalidFunctionCall,
+		fmt.Sprintf("wrong number of arguments to built-in function %s", call.FunctionName),
+		call.Loc,
+		"CallExpr",
+		fmt.Sprintf("function %s expects %d arguments, got %d", call.FunctionName, len(signature.Params), len(call.Arguments)),
+	)
+}
+
 // validateType проверяет, является ли тип допустимым
 func (v *SemanticValidator) validateType(t converter.Type, context string, name string, loc converter.Location) error {
-	// Типы должны быть только int (без указателей и массивов в этой версии)
-	// Запрещаем указатели для всех типов
+	// Типы должны быть только int, char, float или double
+	// Допускаются указатели первого уровня (для динамической памяти)
 	if t.PointerLevel > pointerMaximumDepth {
 		return NewSemanticError(
 			ErrInvalidType,
@@ -258,6 +319,11 @@ func (v *SemanticValidator) validateStmt(stmt interface{}) error {
 	switch s := stmt.(type) {
 	case *converter.VariableDecl:
 		return v.validateVariableDecl(s)
+	case *converter.EnumDecl:
+		return v.validateEnumDecl(s)
+	case *converter.TypedefDecl:
+		// Псевдоним подчиняется тем же ограничениям, что и тип переменной
+		return v.validateType(s.Underlying, "typedef", s.Name, s.Loc)
 	case *converter.BlockStmt:
 		for _, s := range s.Statements {
 			if err := v.validateStmt(s); err != nil {
@@ -376,6 +442,9 @@ func (v *SemanticValidator) validateExpr(expr interface{}) error {
 		}
 
 	case *converter.CallExpr:
+		if err := v.validateBuiltinCall(e); err != nil {
+			return err
+		}
 		for _, arg := range e.Arguments {
 			if err := v.validateExpr(arg); err != nil {
 				return err
@@ -396,6 +465,26 @@ func (v *SemanticValidator) validateExpr(expr interface{}) error {
 				return err
 			}
 		}
+
+	case *converter.CastExpr:
+		if err := v.validateType(e.TargetType, "cast", "", e.Loc); err != nil {
+			return err
+		}
+		if err := v.validateExpr(e.Operand); err != nil {
+			return err
+		}
+
+	case *converter.SizeofExpr:
+		if e.ArgType != nil {
+			if err := v.validateType(*e.ArgType, "sizeof", "", e.Loc); err != nil {
+				return err
+			}
+		}
+		if e.Operand != nil {
+			if err := v.validateExpr(e.Operand); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
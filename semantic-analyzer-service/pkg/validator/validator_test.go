@@ -26,8 +26,8 @@ func TestVariableTypes(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name:      "invalid variable type (pointer)",
-			code:      `int main() { int* a; return 0; }`,
+			name:      "invalid variable type (pointer to pointer)",
+			code:      `int main() { int** a; return 0; }`,
 			wantError: true,
 			errCode:   ErrInvalidType,
 		},
@@ -38,8 +38,68 @@ func TestVariableTypes(t *testing.T) {
 			errCode:   ErrInvalidType,
 		},
 		{
-			name:      "invalid variable type (float)",
-			code:      `int main() { float a = 1; return 0; }`,
+			name:      "valid pointer and heap allocation",
+			code:      `int main() { int *p = (int*)malloc(3 * sizeof(int)); double *d = calloc(2, sizeof(double)); *p = 1; p[1] = 2; free(p); free(d); p = NULL; return 0; }`,
+			wantError: false,
+		},
+		{
+			name:      "valid built-in calls",
+			code:      `int main() { srand(42); int r = rand() % 10; assert(r >= 0); int m = max(abs(-3), min(r, 2)); if (m < 0) { exit(1); } return m; }`,
+			wantError: false,
+		},
+		{
+			name:      "invalid built-in call (wrong number of arguments)",
+			code:      `int main() { return abs(1, 2); }`,
+			wantError: true,
+			errCode:   ErrInvalidFunctionCall,
+		},
+		{
+			name:      "user function overrides built-in arity",
+			code:      `int max(int a, int b, int c) { return a; } int main() { return max(1, 2, 3); }`,
+			wantError: false,
+		},
+		{
+			name:      "invalid cast (pointer to pointer)",
+			code:      `int main() { int x = 0; int y = (int)(int**)x; return y; }`,
+			wantError: true,
+			errCode:   ErrInvalidType,
+		},
+		{
+			name:      "valid float and double variables",
+			code:      `int main() { float a = 1.5f; double b[3] = {1.0, 2, 3.5}; return 0; }`,
+			wantError: false,
+		},
+		{
+			name:      "valid char variables and strings",
+			code:      `int main() { char c = 'a'; char s[6] = "hello"; char words[2][4] = {"ab", "cd"}; return strlen(s); }`,
+			wantError: false,
+		},
+		{
+			name:      "invalid variable type (unknown)",
+			code:      `int main() { myint a = 1; return 0; }`,
+			wantError: true,
+			errCode:   ErrInvalidType,
+		},
+		{
+			name:      "valid enum and typedef variables",
+			code:      `enum Color { RED, GREEN }; typedef int myint; typedef myint row[3]; int main() { enum Color c = GREEN; myint a = 1; row r; return a; }`,
+			wantError: false,
+		},
+		{
+			name:      "invalid typedef (pointer to pointer)",
+			code:      `typedef int** intptr; int main() { return 0; }`,
+			wantError: true,
+			errCode:   ErrInvalidType,
+		},
+		{
+			name:      "invalid typedef (array 3d)",
+			code:      `int main() { typedef int cube[2][2][2]; return 0; }`,
+			wantError: true,
+			errCode:   ErrInvalidType,
+		},
+		{
+			name:      "invalid enumerator value (out of int range)",
+			code:      `enum Big { SMALL, HUGE = 3000000000 }; int main() { return 0; }`,
 			wantError: true,
 			errCode:   ErrInvalidType,
 		},
@@ -309,6 +369,16 @@ func TestFunctionTypes(t *testing.T) {
 			code:      `int bar() { return 1; } int main() { return bar(); }`,
 			wantError: false,
 		},
+		{
+			name:      "valid double return and parameter",
+			code:      `double half(double x) { return x / 2; } int main() { double h = half(3); return 0; }`,
+			wantError: false,
+		},
+		{
+			name:      "valid enum return and parameter",
+			code:      `enum Dir { UP, DOWN }; enum Dir flip(enum Dir d) { if (d == UP) return DOWN; return UP; } int main() { return flip(UP); }`,
+			wantError: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -358,8 +428,8 @@ func TestFunctionParameters(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name:      "invalid function parameter (pointer)",
-			code:      `int sum(int* arr) { return arr[0] + arr[1]; } int main() {  }`,
+			name:      "invalid function parameter (pointer to pointer)",
+			code:      `int sum(int** arr) { return 0; } int main() {  }`,
 			wantError: true,
 			errCode:   ErrInvalidType,
 		},